@@ -14,20 +14,60 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
 )
 
+// Defines values for ReadinessCheckStatus.
+const (
+	Degraded ReadinessCheckStatus = "degraded"
+	Failed   ReadinessCheckStatus = "failed"
+	Ok       ReadinessCheckStatus = "ok"
+)
+
+// Valid indicates whether the value is a known member of the ReadinessCheckStatus enum.
+func (e ReadinessCheckStatus) Valid() bool {
+	switch e {
+	case Degraded:
+		return true
+	case Failed:
+		return true
+	case Ok:
+		return true
+	default:
+		return false
+	}
+}
+
 // HealthResponse defines model for HealthResponse.
 type HealthResponse struct {
 	Status string `json:"status"`
 }
 
+// ReadinessCheck defines model for ReadinessCheck.
+type ReadinessCheck struct {
+	LastRefresh *time.Time           `json:"last_refresh,omitempty"`
+	Message     *string              `json:"message,omitempty"`
+	Name        string               `json:"name"`
+	Status      ReadinessCheckStatus `json:"status"`
+}
+
+// ReadinessCheckStatus defines model for ReadinessCheck.Status.
+type ReadinessCheckStatus string
+
 // ReadinessFailure defines model for ReadinessFailure.
 type ReadinessFailure struct {
-	FailedChecks      []string `json:"failedChecks"`
-	SuccessfullChecks []string `json:"successfullChecks"`
+	Checks            *[]ReadinessCheck `json:"checks,omitempty"`
+	FailedChecks      []string          `json:"failedChecks"`
+	SuccessfullChecks []string          `json:"successfullChecks"`
+}
+
+// ReadinessResponse defines model for ReadinessResponse.
+type ReadinessResponse struct {
+	Checks []ReadinessCheck `json:"checks"`
+	Status string           `json:"status"`
 }
 
 // ServerInterface represents all server handlers.
@@ -291,7 +331,7 @@ type GetReadyzResponseObject interface {
 	VisitGetReadyzResponse(w http.ResponseWriter) error
 }
 
-type GetReadyz200JSONResponse HealthResponse
+type GetReadyz200JSONResponse ReadinessResponse
 
 func (response GetReadyz200JSONResponse) VisitGetReadyzResponse(w http.ResponseWriter) error {
 
@@ -438,17 +478,20 @@ func (sh *strictHandler) GetReadyz(w http.ResponseWriter, r *http.Request) {
 // const string: with thousands of chunks the chained `+` fold is several
 // times slower for the Go compiler than parsing a slice literal.
 var swaggerSpec = []string{
-	"zFVNjyM1EP0rJcMBpFYSdsUlN4QEO4LDapYbmoNjv57U4rYbV3VDWOW/o3In85lZgbSHOcVtlV+9eu/Z",
-	"+eRCGcaSkVXc9pOTsMfg2/IdfNL9NWQsWWA7Yy0jqjKWUvU6tZUeRritE62cb93x2LmKPyeuiG77+7nu",
-	"pjvXld1HBHXHzl3DR84Q+clzmuqFJr3nhPjjHuGP9s2K4VLPO3Bfqz/Yt0whQKSfUvr/x5+O8Ayre8zs",
-	"+XAGwbkv1ixCQuVRuWS3PelKwU4SchwLZxXqS6Vfph1qhkJorGUHIZ8jDSWzFiO6cp1T1oR7mB/eX7nO",
-	"zaiyoH+32qw2NlAZkf3Ibuvetq3OjV73bfj1vp39x9a30OcUr6FTzUKe3mw2tBhIoUQYAbPHW+FVdFv3",
-	"M/TdCc00W8LSurzZbOwnlKzIrYkfx8ShnV1/FOt0Dpytvq7o3dZ9tb5P5PoUx/WTLDZ1H1P+gDpzALHQ",
-	"Mt3iokzD4OthIUoLjInob8WMXUrdjZWuE894WZMH3kwCId2zkBaKUNSBM4h7OpSpkk3sOaNSBmIr2oEq",
-	"RH1VxBV92JcpxVMCzqceiGPmW95sGFFOieqUM+fbFoeTyDxfduPXNsVr8cKbqE+cMIZ26em9ZfwlNyp8",
-	"PLxsx29ISR5emL/2yIuSY4nWugGY+BUBPIO0+r7ncKf/jMr9gSJG5Igc2O5bBfnZc/K7BPomevU7LzBP",
-	"M4L1lo7wt6Jmn+zy2aeG1bcruuqXTNjDIN1DahVDmVtk0Mj1tQwkJ5HuX4ApKydipdGLQC66e72o8lrs",
-	"bRrba/P95u0XY/DsX+HzHHLRM49HMbuD+UzOjneb/+2Vdp3LfrDn94RxvDn+GwAA//8=",
+	"zFZNj9s2EP0rA7aHFlBlN0EvvgXZNFm0QINNbsWiGJNPFrMSqXIot27g/14MZa9df2wTIAV6MkXNx5v3",
+	"Zkb+aGzshxgQspjFRyO2Rc/l+Abc5fYOMsQg0JshxQEpe0ymmfNYTnkzwCyM5OTDymy3lUn4ffQJzix+",
+	"3dvdV3u7uPwAm822Mndg5wNEXrawD+cpOpb8W0KTIK0+NzH1nM3COM74LvsepjrNXpkeIrzCBWSVCdxf",
+	"fnGoBmHsFXd8MJVxWCV2cKYyDfsO7qiOK/WWFNUnlf0j+25MF7i1ykc5+Yy+HL5OaMzCfDU7CDbbqTU7",
+	"4XH7mJNT4o3Z7sG/PA97RsSpp4zWQqQZu+7z3U9b4SzWCbIn2breil+crs/s7crYa/jVw4cmaiwHsckP",
+	"2cdgFrv5ouJJCG6IPmShJib6aVwiBWQIDSkuIcTBUR+Dz1Fx1KYy2ecOhzAv3t6ayqyRZIr+fT2v51pK",
+	"HBB48GZhnperygyc21LbrC2+f+l5hXwO8Q55TEGI6dl8TlOxZKODAlABWA1vnVmY18hvdtGUokmpkuXZ",
+	"fK4/NoaMUJLwMHTeFt/ZB4nhsHj+TbmTnVTY/Sfkd0hrb0FeaKpu6kIZ+57TZgJKUxglkVeiOk6m5l5N",
+	"Z51f4zonR9qMAqHceqEcySEj9T6AfEObOCbSitkHJAqAK0ZLUIJkThmupndtHDu364C91xE5Kr7OixYj",
+	"2XcdpTEEH1alHXYk+/VlNX4uVfxftGAl9UQJRRi0vLfa49fUSGC3uS7He3SdHA/MHy3CxOQQnaYuAZT8",
+	"BAu/BuXETePtI/9rJN9syGFAcAjW67wlEK/Zd7zsQN84zrxkgWoaYDW3VIQ/M1LgTodPH7Otv63ptpl6",
+	"QhebVMfQEvq4Li2DAq5JsSfZkXTYAGPIviOfaWARyEV17yZW/kN5z9fu0woXmmt632K/KLzQzavXdy9u",
+	"Xt1od/OB4Y2+8/3AukdpOWbSpQrJQpbDrteXKNzAVYR6VRfWfrm9eUleZETSEGNIYNsWjXQkLNsWjsSv",
+	"ypQ8YDMpOQpcrcvwh/nzL0/Q/iv+ND8h5omjkyl4DPPEGGwfLz/tI2L2f3T2Mbb3278HAA==",
 }
 
 // decodeSpec returns the embedded OpenAPI spec as raw JSON bytes,
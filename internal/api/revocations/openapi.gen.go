@@ -0,0 +1,1138 @@
+// Package revocations provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.7.0 DO NOT EDIT.
+package revocations
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/oapi-codegen/runtime"
+)
+
+const (
+	API_Key_AuthScopes aPIKeyAuthContextKey = "API_Key_Auth.Scopes"
+	JWT_AuthScopes     jWTAuthContextKey    = "JWT_Auth.Scopes"
+)
+
+// RevokeSubjectRequest defines model for RevokeSubjectRequest.
+type RevokeSubjectRequest struct {
+	// Issuer The `iss` of the subject's tokens. Defaults to `OIDC_ISSUER`.
+	Issuer *string `json:"issuer,omitempty"`
+	Reason *string `json:"reason,omitempty"`
+
+	// RevokedBefore Tokens issued before this time are rejected. Defaults to now.
+	RevokedBefore *time.Time `json:"revoked_before,omitempty"`
+	Subject       string     `json:"subject"`
+}
+
+// RevokeTokenRequest defines model for RevokeTokenRequest.
+type RevokeTokenRequest struct {
+	// ExpiresAt The `exp` of the token, the entry is dropped afterwards. Defaults to 24 hours from now.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Jti       string     `json:"jti"`
+	Reason    *string    `json:"reason,omitempty"`
+}
+
+// RevokedSubject defines model for RevokedSubject.
+type RevokedSubject struct {
+	CreatedAt     time.Time `json:"created_at"`
+	Issuer        string    `json:"issuer"`
+	Reason        *string   `json:"reason,omitempty"`
+	RevokedBefore time.Time `json:"revoked_before"`
+	Subject       string    `json:"subject"`
+}
+
+// RevokedSubjectList defines model for RevokedSubjectList.
+type RevokedSubjectList struct {
+	Data []RevokedSubject `json:"data"`
+}
+
+// RevokedToken defines model for RevokedToken.
+type RevokedToken struct {
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Jti       string    `json:"jti"`
+	Reason    *string   `json:"reason,omitempty"`
+}
+
+// RevokedTokenList defines model for RevokedTokenList.
+type RevokedTokenList struct {
+	Data []RevokedToken `json:"data"`
+}
+
+// BadRequest defines model for Bad Request.
+type BadRequest struct {
+	Message string `json:"message"`
+}
+
+// Forbidden defines model for Forbidden.
+type Forbidden struct {
+	Message string `json:"message"`
+}
+
+// InternalServerError defines model for Internal Server Error.
+type InternalServerError struct {
+	Message string `json:"message"`
+}
+
+// NotFound defines model for Not Found.
+type NotFound struct {
+	Message string `json:"message"`
+}
+
+// Unauthorized defines model for Unauthorized.
+type Unauthorized struct {
+	Message string `json:"message"`
+}
+
+// aPIKeyAuthContextKey is the context key for API Key Auth security scheme
+type aPIKeyAuthContextKey string
+
+// jWTAuthContextKey is the context key for JWT Auth security scheme
+type jWTAuthContextKey string
+
+// DeleteRevokedSubjectParams defines parameters for DeleteRevokedSubject.
+type DeleteRevokedSubjectParams struct {
+	// Issuer The issuer of the subject. Defaults to `OIDC_ISSUER`.
+	Issuer *string `form:"issuer,omitempty" json:"issuer,omitempty"`
+}
+
+// RevokeSubjectJSONRequestBody defines body for RevokeSubject for application/json ContentType.
+type RevokeSubjectJSONRequestBody = RevokeSubjectRequest
+
+// RevokeTokenJSONRequestBody defines body for RevokeToken for application/json ContentType.
+type RevokeTokenJSONRequestBody = RevokeTokenRequest
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// List revoked subjects
+	// (GET /admin/revocations/subjects)
+	ListRevokedSubjects(w http.ResponseWriter, r *http.Request)
+	// Revoke subject
+	// (POST /admin/revocations/subjects)
+	RevokeSubject(w http.ResponseWriter, r *http.Request)
+	// Remove subject revocation
+	// (DELETE /admin/revocations/subjects/{subject})
+	DeleteRevokedSubject(w http.ResponseWriter, r *http.Request, subject string, params DeleteRevokedSubjectParams)
+	// List revoked tokens
+	// (GET /admin/revocations/tokens)
+	ListRevokedTokens(w http.ResponseWriter, r *http.Request)
+	// Revoke token
+	// (POST /admin/revocations/tokens)
+	RevokeToken(w http.ResponseWriter, r *http.Request)
+}
+
+// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+
+type Unimplemented struct{}
+
+// List revoked subjects
+// (GET /admin/revocations/subjects)
+func (_ Unimplemented) ListRevokedSubjects(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Revoke subject
+// (POST /admin/revocations/subjects)
+func (_ Unimplemented) RevokeSubject(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Remove subject revocation
+// (DELETE /admin/revocations/subjects/{subject})
+func (_ Unimplemented) DeleteRevokedSubject(w http.ResponseWriter, r *http.Request, subject string, params DeleteRevokedSubjectParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List revoked tokens
+// (GET /admin/revocations/tokens)
+func (_ Unimplemented) ListRevokedTokens(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Revoke token
+// (POST /admin/revocations/tokens)
+func (_ Unimplemented) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// ListRevokedSubjects operation middleware
+func (siw *ServerInterfaceWrapper) ListRevokedSubjects(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListRevokedSubjects(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RevokeSubject operation middleware
+func (siw *ServerInterfaceWrapper) RevokeSubject(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RevokeSubject(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteRevokedSubject operation middleware
+func (siw *ServerInterfaceWrapper) DeleteRevokedSubject(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "subject" -------------
+	var subject string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "subject", chi.URLParam(r, "subject"), &subject, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "subject", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteRevokedSubjectParams
+
+	// ------------- Optional query parameter "issuer" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "issuer", r.URL.Query(), &params.Issuer, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "issuer"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "issuer", Err: err})
+		}
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteRevokedSubject(w, r, subject, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListRevokedTokens operation middleware
+func (siw *ServerInterfaceWrapper) ListRevokedTokens(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListRevokedTokens(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RevokeToken operation middleware
+func (siw *ServerInterfaceWrapper) RevokeToken(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RevokeToken(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL          string
+	BaseRouter       chi.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/revocations/subjects", wrapper.ListRevokedSubjects)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/revocations/subjects", wrapper.RevokeSubject)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/revocations/subjects/{subject}", wrapper.DeleteRevokedSubject)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/revocations/tokens", wrapper.ListRevokedTokens)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/revocations/tokens", wrapper.RevokeToken)
+	})
+
+	return r
+}
+
+type BadRequestJSONResponse struct {
+	Message string `json:"message"`
+}
+
+type ForbiddenJSONResponse struct {
+	Message string `json:"message"`
+}
+
+type InternalServerErrorJSONResponse struct {
+	Message string `json:"message"`
+}
+
+type NotFoundJSONResponse struct {
+	Message string `json:"message"`
+}
+
+type UnauthorizedJSONResponse struct {
+	Message string `json:"message"`
+}
+
+type ListRevokedSubjectsRequestObject struct {
+}
+
+type ListRevokedSubjectsResponseObject interface {
+	VisitListRevokedSubjectsResponse(w http.ResponseWriter) error
+}
+
+type ListRevokedSubjects200JSONResponse RevokedSubjectList
+
+func (response ListRevokedSubjects200JSONResponse) VisitListRevokedSubjectsResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListRevokedSubjects401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response ListRevokedSubjects401JSONResponse) VisitListRevokedSubjectsResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListRevokedSubjects403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response ListRevokedSubjects403JSONResponse) VisitListRevokedSubjectsResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListRevokedSubjects500JSONResponse struct {
+	InternalServerErrorJSONResponse
+}
+
+func (response ListRevokedSubjects500JSONResponse) VisitListRevokedSubjectsResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type RevokeSubjectRequestObject struct {
+	Body *RevokeSubjectJSONRequestBody
+}
+
+type RevokeSubjectResponseObject interface {
+	VisitRevokeSubjectResponse(w http.ResponseWriter) error
+}
+
+type RevokeSubject201JSONResponse RevokedSubject
+
+func (response RevokeSubject201JSONResponse) VisitRevokeSubjectResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type RevokeSubject400JSONResponse struct{ BadRequestJSONResponse }
+
+func (response RevokeSubject400JSONResponse) VisitRevokeSubjectResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type RevokeSubject401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response RevokeSubject401JSONResponse) VisitRevokeSubjectResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type RevokeSubject403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response RevokeSubject403JSONResponse) VisitRevokeSubjectResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type RevokeSubject500JSONResponse struct {
+	InternalServerErrorJSONResponse
+}
+
+func (response RevokeSubject500JSONResponse) VisitRevokeSubjectResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type DeleteRevokedSubjectRequestObject struct {
+	Subject string `json:"subject"`
+	Params  DeleteRevokedSubjectParams
+}
+
+type DeleteRevokedSubjectResponseObject interface {
+	VisitDeleteRevokedSubjectResponse(w http.ResponseWriter) error
+}
+
+type DeleteRevokedSubject204Response struct {
+}
+
+func (response DeleteRevokedSubject204Response) VisitDeleteRevokedSubjectResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteRevokedSubject401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response DeleteRevokedSubject401JSONResponse) VisitDeleteRevokedSubjectResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type DeleteRevokedSubject403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response DeleteRevokedSubject403JSONResponse) VisitDeleteRevokedSubjectResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type DeleteRevokedSubject404JSONResponse struct{ NotFoundJSONResponse }
+
+func (response DeleteRevokedSubject404JSONResponse) VisitDeleteRevokedSubjectResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type DeleteRevokedSubject500JSONResponse struct {
+	InternalServerErrorJSONResponse
+}
+
+func (response DeleteRevokedSubject500JSONResponse) VisitDeleteRevokedSubjectResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListRevokedTokensRequestObject struct {
+}
+
+type ListRevokedTokensResponseObject interface {
+	VisitListRevokedTokensResponse(w http.ResponseWriter) error
+}
+
+type ListRevokedTokens200JSONResponse RevokedTokenList
+
+func (response ListRevokedTokens200JSONResponse) VisitListRevokedTokensResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListRevokedTokens401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response ListRevokedTokens401JSONResponse) VisitListRevokedTokensResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListRevokedTokens403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response ListRevokedTokens403JSONResponse) VisitListRevokedTokensResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListRevokedTokens500JSONResponse struct {
+	InternalServerErrorJSONResponse
+}
+
+func (response ListRevokedTokens500JSONResponse) VisitListRevokedTokensResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type RevokeTokenRequestObject struct {
+	Body *RevokeTokenJSONRequestBody
+}
+
+type RevokeTokenResponseObject interface {
+	VisitRevokeTokenResponse(w http.ResponseWriter) error
+}
+
+type RevokeToken201JSONResponse RevokedToken
+
+func (response RevokeToken201JSONResponse) VisitRevokeTokenResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type RevokeToken400JSONResponse struct{ BadRequestJSONResponse }
+
+func (response RevokeToken400JSONResponse) VisitRevokeTokenResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type RevokeToken401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response RevokeToken401JSONResponse) VisitRevokeTokenResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type RevokeToken403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response RevokeToken403JSONResponse) VisitRevokeTokenResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type RevokeToken500JSONResponse struct {
+	InternalServerErrorJSONResponse
+}
+
+func (response RevokeToken500JSONResponse) VisitRevokeTokenResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// List revoked subjects
+	// (GET /admin/revocations/subjects)
+	ListRevokedSubjects(ctx context.Context, request ListRevokedSubjectsRequestObject) (ListRevokedSubjectsResponseObject, error)
+	// Revoke subject
+	// (POST /admin/revocations/subjects)
+	RevokeSubject(ctx context.Context, request RevokeSubjectRequestObject) (RevokeSubjectResponseObject, error)
+	// Remove subject revocation
+	// (DELETE /admin/revocations/subjects/{subject})
+	DeleteRevokedSubject(ctx context.Context, request DeleteRevokedSubjectRequestObject) (DeleteRevokedSubjectResponseObject, error)
+	// List revoked tokens
+	// (GET /admin/revocations/tokens)
+	ListRevokedTokens(ctx context.Context, request ListRevokedTokensRequestObject) (ListRevokedTokensResponseObject, error)
+	// Revoke token
+	// (POST /admin/revocations/tokens)
+	RevokeToken(ctx context.Context, request RevokeTokenRequestObject) (RevokeTokenResponseObject, error)
+}
+
+type StrictHandlerFunc func(ctx context.Context, w http.ResponseWriter, r *http.Request, request any) (any, error)
+type StrictMiddlewareFunc func(f StrictHandlerFunc, operationID string) StrictHandlerFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// ListRevokedSubjects operation middleware
+func (sh *strictHandler) ListRevokedSubjects(w http.ResponseWriter, r *http.Request) {
+	var request ListRevokedSubjectsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListRevokedSubjects(ctx, request.(ListRevokedSubjectsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListRevokedSubjects")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListRevokedSubjectsResponseObject); ok {
+		if err := validResponse.VisitListRevokedSubjectsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// RevokeSubject operation middleware
+func (sh *strictHandler) RevokeSubject(w http.ResponseWriter, r *http.Request) {
+	var request RevokeSubjectRequestObject
+
+	var body RevokeSubjectJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RevokeSubject(ctx, request.(RevokeSubjectRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RevokeSubject")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RevokeSubjectResponseObject); ok {
+		if err := validResponse.VisitRevokeSubjectResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteRevokedSubject operation middleware
+func (sh *strictHandler) DeleteRevokedSubject(w http.ResponseWriter, r *http.Request, subject string, params DeleteRevokedSubjectParams) {
+	var request DeleteRevokedSubjectRequestObject
+
+	request.Subject = subject
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteRevokedSubject(ctx, request.(DeleteRevokedSubjectRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteRevokedSubject")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteRevokedSubjectResponseObject); ok {
+		if err := validResponse.VisitDeleteRevokedSubjectResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListRevokedTokens operation middleware
+func (sh *strictHandler) ListRevokedTokens(w http.ResponseWriter, r *http.Request) {
+	var request ListRevokedTokensRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListRevokedTokens(ctx, request.(ListRevokedTokensRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListRevokedTokens")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListRevokedTokensResponseObject); ok {
+		if err := validResponse.VisitListRevokedTokensResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// RevokeToken operation middleware
+func (sh *strictHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	var request RevokeTokenRequestObject
+
+	var body RevokeTokenJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RevokeToken(ctx, request.(RevokeTokenRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RevokeToken")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RevokeTokenResponseObject); ok {
+		if err := validResponse.VisitRevokeTokenResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// Base64 encoded, compressed with deflate, json marshaled OpenAPI spec.
+// Stored as a slice of fixed-width chunks rather than one concatenated
+// const string: with thousands of chunks the chained `+` fold is several
+// times slower for the Go compiler than parsing a slice literal.
+var swaggerSpec = []string{
+	"7Fjfj9o4EP5XRr6T7iUFtt17yRu9thJtdVctW/WkFSoGD8RbYqf2hG0O5X8/2QkJCQGW67Zd6fqWEP8Y",
+	"f983M5/ZsLmOE61QkWXhhhm0iVYW/ctzLuAKP6doyb3OtSJU/pEnyUrOOUmt+rdWK/ebnUcYc/eUGJ2g",
+	"IVmsEqO1fInukbIEWcgsGamWLM8DZvBzKg0KFt5UAyfBdqCe3eKcWO5GCrRzIxO3JQsZywP2SpuZFALV",
+	"YwxupAiN4isYo1mjgZfGaPMYA/1TE7zSqRKPMbj3iqcUaSP/wUcYXx6UO/pNrnCtP+E49RN20qYZirQ2",
+	"RS+E5mrXEcJUWjsFvQCKEGyx0G8WSH9CZXvwAhc8XZH7AaZ/jV788XE0Hr9/eTXtsYDFUr1FtaSIhRdB",
+	"+5TukLzEqOOTi1t8nOFCG+yIzG8PPnABxSigSFogGSNwg2DQhYqiGaPSdy6yhTYxJxYywQmfuDmsI8Dy",
+	"uJ6tY0dpEbadtk9YUBLiwz9IB35JpEH7kdMBSvBLUlHiiQj8IyoyGUgLwugkQQF8QWjuuBEtop5eQqRT",
+	"Y2FhdHweJLckT8JxhNkWUm61wyiJcY1/E6G5QU4oSoTuF3kt8q8T4tnSOQ5BGVY9Y2/PYPe4p9F6K7s0",
+	"JTj5GiQJY//DrwYXLGS/9Ote2y8LR78Ff17tyY3h2d4R/NpHAvNqfxgSm6lxlmS/SqSNne9LiD/3w9FR",
+	"wPgfyXCSxHlqJGVjt2wRxvDdCN5gBsPUZfKGScVCFiEXXpGKx26Jv58M342evMGsBpcn0r3nAXv94bqa",
+	"PUNu0LzakvL6wzUrm5GbU3yt14iIkqKFSbXQ+5VuKGKpAJVItFRF3SoSA15/uLZ1zccMCm4CQEkRGuBg",
+	"pVquytoIswwkWZjekpyCNsBXq+KLdTWUb5taq5lw8PuCVL6l9GC4MxCkQEVyId34zBdfP9sAV8K/Tm06",
+	"m8J8xWXsdpFUtUyHgKQVstDrpHALFobvRixgazS2OP5Fb9AbOIR1goonkoXsWW/Qu2ABSzhFnr4+dxj1",
+	"Tb1Mv4zRf16iV57Tnf86EixkTo/N/LYsaFrsp4PBWb7m/qXEbX7IVl0OLg4tVkXXb3gvP+nZ6Um1J88D",
+	"9vtgcHpGt1HeTSIW3mx2xH8zyYN2Ot1M8okr63HMTVYiX0pYgK2xJ760LnN3aGSTPGCJth0GYKTWfCVd",
+	"wbNeyRatlbql5XAr8Kamp83OMm36JBa0pNIwj6woM2jpuRbZA+ujZVDzZlEjk2K+p9GLb6TRLn1e194X",
+	"7rjdstgrJHgPQe3eWv8PWi8ghdrTdIs8D47VsP6mfMqLNFghdVwFhvM5JlV5bd1UgC+5VPvSfuEXaxHv",
+	"KqvhMRIa68/s26GrtnUztI1sqPUZ7Ghtz050mfiyXzTDPXGf8vF8TtFkdUC1f6z2P35Xmexl0mX3NaOm",
+	"pNR8rNeV5r+Lfi8Hl6dn1P8V/GDFO3gq3dXgnSn+QsY77btJzBVSalRR+re9pFQ+RZwg4msEpan0RAIy",
+	"pH3575iA4ib9HSxA7YZ/GoCKtLPbfwGmPepxves81M49Dd+0mTf+3Pgxrby8KnU38gKxn238/m2cSs0c",
+	"qGN59WWz7Uq7I/JJ/u8A",
+}
+
+// decodeSpec returns the embedded OpenAPI spec as raw JSON bytes,
+// after base64-decoding and flate-decompressing the embedded blob.
+func decodeSpec() ([]byte, error) {
+	encoded := strings.Join(swaggerSpec, "")
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr := flate.NewReader(bytes.NewReader(compressed))
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(zr); err != nil {
+		return nil, fmt.Errorf("read flate: %w", err)
+	}
+	if err := zr.Close(); err != nil {
+		return nil, fmt.Errorf("close flate reader: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cache of the decoded OpenAPI spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSpec returns the OpenAPI specification corresponding to the generated
+// code in this file. External references in the spec are resolved through
+// PathToRawSpec; externally-referenced files must be embedded in their
+// corresponding Go packages (via the import-mapping feature). URL-based
+// external refs are not supported.
+func GetSpec() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// GetSpecJSON returns the raw JSON bytes of the embedded OpenAPI
+// specification: decompressed but not unmarshaled. External references
+// are not resolved here; the bytes are the spec exactly as embedded by
+// codegen. The result is cached at package init time, so repeated calls
+// are cheap.
+func GetSpecJSON() ([]byte, error) {
+	return rawSpec()
+}
+
+// GetSwagger returns the OpenAPI specification corresponding to the
+// generated code in this file.
+//
+// Deprecated: GetSwagger predates kin-openapi renaming openapi3.Swagger
+// to openapi3.T. Use [GetSpec] instead. This wrapper is retained for
+// backwards compatibility.
+func GetSwagger() (*openapi3.T, error) {
+	return GetSpec()
+}
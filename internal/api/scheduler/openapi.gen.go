@@ -0,0 +1,745 @@
+// Package scheduler provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.7.0 DO NOT EDIT.
+package scheduler
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/oapi-codegen/runtime"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	API_Key_AuthScopes aPIKeyAuthContextKey = "API_Key_Auth.Scopes"
+	JWT_AuthScopes     jWTAuthContextKey    = "JWT_Auth.Scopes"
+)
+
+// Defines values for ScheduledTaskRunStatus.
+const (
+	Failed    ScheduledTaskRunStatus = "failed"
+	Running   ScheduledTaskRunStatus = "running"
+	Succeeded ScheduledTaskRunStatus = "succeeded"
+)
+
+// Valid indicates whether the value is a known member of the ScheduledTaskRunStatus enum.
+func (e ScheduledTaskRunStatus) Valid() bool {
+	switch e {
+	case Failed:
+		return true
+	case Running:
+		return true
+	case Succeeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// ScheduledTask defines model for ScheduledTask.
+type ScheduledTask struct {
+	LastRun *ScheduledTaskRun `json:"last_run,omitempty"`
+	Name    string            `json:"name"`
+
+	// NextRunAt Missing if the schedule never matches again.
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+
+	// Schedule Cron expression, evaluated in UTC.
+	Schedule string `json:"schedule"`
+}
+
+// ScheduledTaskList defines model for ScheduledTaskList.
+type ScheduledTaskList struct {
+	Data []ScheduledTask `json:"data"`
+
+	// Leader Whether the replica answering the request is the leader.
+	Leader bool `json:"leader"`
+}
+
+// ScheduledTaskRun defines model for ScheduledTaskRun.
+type ScheduledTaskRun struct {
+	Error      *string    `json:"error,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	// Replica Host name of the leader that ran the task.
+	Replica   string             `json:"replica"`
+	RunId     openapi_types.UUID `json:"run_id"`
+	StartedAt time.Time          `json:"started_at"`
+
+	// Status A run stays running if its leader stopped before it finished.
+	Status ScheduledTaskRunStatus `json:"status"`
+	Task   string                 `json:"task"`
+}
+
+// ScheduledTaskRunStatus A run stays running if its leader stopped before it finished.
+type ScheduledTaskRunStatus string
+
+// ScheduledTaskRunList defines model for ScheduledTaskRunList.
+type ScheduledTaskRunList struct {
+	Data []ScheduledTaskRun `json:"data"`
+
+	// NextCursor Cursor of the next page, missing on the last page.
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// BadRequest defines model for Bad Request.
+type BadRequest struct {
+	Message string `json:"message"`
+}
+
+// Forbidden defines model for Forbidden.
+type Forbidden struct {
+	Message string `json:"message"`
+}
+
+// InternalServerError defines model for Internal Server Error.
+type InternalServerError struct {
+	Message string `json:"message"`
+}
+
+// Unauthorized defines model for Unauthorized.
+type Unauthorized struct {
+	Message string `json:"message"`
+}
+
+// aPIKeyAuthContextKey is the context key for API Key Auth security scheme
+type aPIKeyAuthContextKey string
+
+// jWTAuthContextKey is the context key for JWT Auth security scheme
+type jWTAuthContextKey string
+
+// ListScheduledTaskRunsParams defines parameters for ListScheduledTaskRuns.
+type ListScheduledTaskRunsParams struct {
+	Task *string `form:"task,omitempty" json:"task,omitempty"`
+
+	// Cursor next_cursor of the previous page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+	Limit  *int    `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// List scheduled tasks
+	// (GET /admin/scheduled-tasks)
+	ListScheduledTasks(w http.ResponseWriter, r *http.Request)
+	// List runs
+	// (GET /admin/scheduled-tasks/runs)
+	ListScheduledTaskRuns(w http.ResponseWriter, r *http.Request, params ListScheduledTaskRunsParams)
+}
+
+// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+
+type Unimplemented struct{}
+
+// List scheduled tasks
+// (GET /admin/scheduled-tasks)
+func (_ Unimplemented) ListScheduledTasks(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List runs
+// (GET /admin/scheduled-tasks/runs)
+func (_ Unimplemented) ListScheduledTaskRuns(w http.ResponseWriter, r *http.Request, params ListScheduledTaskRunsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// ListScheduledTasks operation middleware
+func (siw *ServerInterfaceWrapper) ListScheduledTasks(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListScheduledTasks(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListScheduledTaskRuns operation middleware
+func (siw *ServerInterfaceWrapper) ListScheduledTaskRuns(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListScheduledTaskRunsParams
+
+	// ------------- Optional query parameter "task" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "task", r.URL.Query(), &params.Task, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "task"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "task", Err: err})
+		}
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "cursor", r.URL.Query(), &params.Cursor, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "cursor"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		}
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "limit", r.URL.Query(), &params.Limit, runtime.BindQueryParameterOptions{Type: "integer", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "limit"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		}
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListScheduledTaskRuns(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL          string
+	BaseRouter       chi.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/scheduled-tasks", wrapper.ListScheduledTasks)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/scheduled-tasks/runs", wrapper.ListScheduledTaskRuns)
+	})
+
+	return r
+}
+
+type BadRequestJSONResponse struct {
+	Message string `json:"message"`
+}
+
+type ForbiddenJSONResponse struct {
+	Message string `json:"message"`
+}
+
+type InternalServerErrorJSONResponse struct {
+	Message string `json:"message"`
+}
+
+type UnauthorizedJSONResponse struct {
+	Message string `json:"message"`
+}
+
+type ListScheduledTasksRequestObject struct {
+}
+
+type ListScheduledTasksResponseObject interface {
+	VisitListScheduledTasksResponse(w http.ResponseWriter) error
+}
+
+type ListScheduledTasks200JSONResponse ScheduledTaskList
+
+func (response ListScheduledTasks200JSONResponse) VisitListScheduledTasksResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScheduledTasks401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response ListScheduledTasks401JSONResponse) VisitListScheduledTasksResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScheduledTasks403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response ListScheduledTasks403JSONResponse) VisitListScheduledTasksResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScheduledTasks500JSONResponse struct {
+	InternalServerErrorJSONResponse
+}
+
+func (response ListScheduledTasks500JSONResponse) VisitListScheduledTasksResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScheduledTaskRunsRequestObject struct {
+	Params ListScheduledTaskRunsParams
+}
+
+type ListScheduledTaskRunsResponseObject interface {
+	VisitListScheduledTaskRunsResponse(w http.ResponseWriter) error
+}
+
+type ListScheduledTaskRuns200JSONResponse ScheduledTaskRunList
+
+func (response ListScheduledTaskRuns200JSONResponse) VisitListScheduledTaskRunsResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScheduledTaskRuns400JSONResponse struct{ BadRequestJSONResponse }
+
+func (response ListScheduledTaskRuns400JSONResponse) VisitListScheduledTaskRunsResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScheduledTaskRuns401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response ListScheduledTaskRuns401JSONResponse) VisitListScheduledTaskRunsResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScheduledTaskRuns403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response ListScheduledTaskRuns403JSONResponse) VisitListScheduledTaskRunsResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScheduledTaskRuns500JSONResponse struct {
+	InternalServerErrorJSONResponse
+}
+
+func (response ListScheduledTaskRuns500JSONResponse) VisitListScheduledTaskRunsResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// List scheduled tasks
+	// (GET /admin/scheduled-tasks)
+	ListScheduledTasks(ctx context.Context, request ListScheduledTasksRequestObject) (ListScheduledTasksResponseObject, error)
+	// List runs
+	// (GET /admin/scheduled-tasks/runs)
+	ListScheduledTaskRuns(ctx context.Context, request ListScheduledTaskRunsRequestObject) (ListScheduledTaskRunsResponseObject, error)
+}
+
+type StrictHandlerFunc func(ctx context.Context, w http.ResponseWriter, r *http.Request, request any) (any, error)
+type StrictMiddlewareFunc func(f StrictHandlerFunc, operationID string) StrictHandlerFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// ListScheduledTasks operation middleware
+func (sh *strictHandler) ListScheduledTasks(w http.ResponseWriter, r *http.Request) {
+	var request ListScheduledTasksRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListScheduledTasks(ctx, request.(ListScheduledTasksRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListScheduledTasks")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListScheduledTasksResponseObject); ok {
+		if err := validResponse.VisitListScheduledTasksResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListScheduledTaskRuns operation middleware
+func (sh *strictHandler) ListScheduledTaskRuns(w http.ResponseWriter, r *http.Request, params ListScheduledTaskRunsParams) {
+	var request ListScheduledTaskRunsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListScheduledTaskRuns(ctx, request.(ListScheduledTaskRunsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListScheduledTaskRuns")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListScheduledTaskRunsResponseObject); ok {
+		if err := validResponse.VisitListScheduledTaskRunsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// Base64 encoded, compressed with deflate, json marshaled OpenAPI spec.
+// Stored as a slice of fixed-width chunks rather than one concatenated
+// const string: with thousands of chunks the chained `+` fold is several
+// times slower for the Go compiler than parsing a slice literal.
+var swaggerSpec = []string{
+	"5Ffdbhs3E30Vgt8H9GYtyUl7ozs3aFAnLWA4DlLAEAJ6OdJOsktuZoZOVGPfvSDF1U92XclAURjonbgk",
+	"Z84cnjmkHnTpm9Y7cMJ6/qAJuPWOIQ1+NlZdw5cALHFYeifg0k/TtjWWRtC76Sf2Ln7jsoLGxF8t+RZI",
+	"cBOlAWazgvhT1i3ouWYhdCvddYUm+BKQwOr57XbhougX+rtPUIru4koLXBK2MaWea90V+rWnO7QW3HME",
+	"d+kEyJlavQO6B1K/EHl6jkDfOxOk8oR/gn1++LoiZ0xJ3pUV2FCDvTH8eYihNiwfKSSc/ydY6rn+33Sn",
+	"72mOND0Icx1cpMGZZgx8oR18S0E/msTJIcDfkRndSuFSSQWKc2DlIJ55Y6SsgJVZGXQTXeilpyaG0dYI",
+	"nAk2oIthxj7KMN0r8k7Bt5aAGb0rFNybOhgBq9Cp9zevJro4Qn+qcy/H8BiKQ5p/Q5Yh1dZIEgEKNPwk",
+	"vnW3zWiIzDqOazAWaFjvhwqkAkrcEiQxKuP4K8TS8tdkTwo5DTeB9li4874G4wY0JPzbxEdJuA5uyAH0",
+	"LT04wSU65Aps1sxpx54LHLLwq2dR8dyUX+5VqaQyosi49E0Mf56Mhg3uI9oDGCGgHVvKYkieiJrFSOAh",
+	"6AtFwSkWs+b4y+UuQeEePotvW7DqDpaeQKGonrZYB7jQxIPKe3WhOZQlgIUIfWmwBqsXI4AkW8Pft0Fm",
+	"JS/fkb8t6ICNU/TxT/dJ9qXvWyXZURmI/Ui/vErfe5nEpao1KyhUk33Kb8QSjTLNHPeLVMCIVxeaoQyE",
+	"so6om021F1eX6i2s1UWQKlUdQVWbHustVv9xdnF1efYW1rvcpsU47gr95sPNdvcdGAJ63cvwzYebbFwp",
+	"zGZ2F6MSaTd3CLqlHxGkbdApcLb16ISVeIWOWyglUdICobdYpkZiZZxNnytk8bTOlCJFLfNE3aRFUeGZ",
+	"0d6dKl/b3pt6j6UfWBl7jxwD1b7c9ClKdPitjEhdXF3qQt8D8Qbw+WQ2mUVOfAvOtKjn+uVkNjnXhW6N",
+	"VInwqYlVTftM9iyhjzMrGLmurkECOc6IV8gCBDaX/BWlykUm5UQKklAopMsryjq9BS6tnuso9wO9si4O",
+	"X48vZrMnvSdO7oyY+rHXzI+z88dibcFND548adPL45t2r82u0D/NZsd3jD8B91tHz28f9iR/u+iK75vo",
+	"dtEtovc1jaF15n2rrHx0ycZWHNu1nyG9iInG9TGNIj5NJMFx1L6paxWNxW0umkI5+AocHZtYJurKMKs9",
+	"Z+odqOdCGVZ5RrxagRz60wnquo6Io/DJNCBAnKhL/vIlAK139pINfaergb99X/AI8JbgHn3gLbyxTJst",
+	"x3KN7ayxQTnYaGFpQi16/mJW6MZ8wyZef+ezOEKXR1uvQyewgqilxb/VdP0N92jfndAQ+/8n/zO9SsE9",
+	"2qDd9vtDL43dfLfo/hoA",
+}
+
+// decodeSpec returns the embedded OpenAPI spec as raw JSON bytes,
+// after base64-decoding and flate-decompressing the embedded blob.
+func decodeSpec() ([]byte, error) {
+	encoded := strings.Join(swaggerSpec, "")
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr := flate.NewReader(bytes.NewReader(compressed))
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(zr); err != nil {
+		return nil, fmt.Errorf("read flate: %w", err)
+	}
+	if err := zr.Close(); err != nil {
+		return nil, fmt.Errorf("close flate reader: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cache of the decoded OpenAPI spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSpec returns the OpenAPI specification corresponding to the generated
+// code in this file. External references in the spec are resolved through
+// PathToRawSpec; externally-referenced files must be embedded in their
+// corresponding Go packages (via the import-mapping feature). URL-based
+// external refs are not supported.
+func GetSpec() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// GetSpecJSON returns the raw JSON bytes of the embedded OpenAPI
+// specification: decompressed but not unmarshaled. External references
+// are not resolved here; the bytes are the spec exactly as embedded by
+// codegen. The result is cached at package init time, so repeated calls
+// are cheap.
+func GetSpecJSON() ([]byte, error) {
+	return rawSpec()
+}
+
+// GetSwagger returns the OpenAPI specification corresponding to the
+// generated code in this file.
+//
+// Deprecated: GetSwagger predates kin-openapi renaming openapi3.Swagger
+// to openapi3.T. Use [GetSpec] instead. This wrapper is retained for
+// backwards compatibility.
+func GetSwagger() (*openapi3.T, error) {
+	return GetSpec()
+}
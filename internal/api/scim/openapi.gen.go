@@ -0,0 +1,2561 @@
+// Package scim provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.7.0 DO NOT EDIT.
+package scim
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/oapi-codegen/runtime"
+)
+
+const (
+	API_Key_AuthScopes    aPIKeyAuthContextKey    = "API_Key_Auth.Scopes"
+	JWT_AuthScopes        jWTAuthContextKey       = "JWT_Auth.Scopes"
+	SCIM_Token_AuthScopes sCIMTokenAuthContextKey = "SCIM_Token_Auth.Scopes"
+)
+
+// ScimDocument A discovery document, see RFC 7643 sections 5 to 7.
+type ScimDocument map[string]interface{}
+
+// ScimDocumentList defines model for ScimDocumentList.
+type ScimDocumentList struct {
+	Resources    []ScimDocument `json:"Resources"`
+	ItemsPerPage int            `json:"itemsPerPage"`
+	Schemas      []string       `json:"schemas"`
+	StartIndex   int            `json:"startIndex"`
+	TotalResults int            `json:"totalResults"`
+}
+
+// ScimEmail defines model for ScimEmail.
+type ScimEmail struct {
+	Primary              *bool                  `json:"primary,omitempty"`
+	Type                 *string                `json:"type,omitempty"`
+	Value                string                 `json:"value"`
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
+// ScimError defines model for ScimError.
+type ScimError struct {
+	Detail   *string  `json:"detail,omitempty"`
+	Schemas  []string `json:"schemas"`
+	ScimType *string  `json:"scimType,omitempty"`
+	Status   string   `json:"status"`
+}
+
+// ScimMeta defines model for ScimMeta.
+type ScimMeta struct {
+	Created      *time.Time `json:"created,omitempty"`
+	Location     *string    `json:"location,omitempty"`
+	ResourceType string     `json:"resourceType"`
+}
+
+// ScimName defines model for ScimName.
+type ScimName struct {
+	FamilyName           *string                `json:"familyName,omitempty"`
+	Formatted            *string                `json:"formatted,omitempty"`
+	GivenName            *string                `json:"givenName,omitempty"`
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
+// ScimPatchOperation defines model for ScimPatchOperation.
+type ScimPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  *string     `json:"path,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ScimPatchRequest defines model for ScimPatchRequest.
+type ScimPatchRequest struct {
+	Operations []ScimPatchOperation `json:"Operations"`
+	Schemas    []string             `json:"schemas"`
+}
+
+// ScimUser Identity providers send further attributes and schema extensions, they are accepted and ignored.
+type ScimUser struct {
+	Active               *bool                  `json:"active,omitempty"`
+	Emails               *[]ScimEmail           `json:"emails,omitempty"`
+	ExternalId           *string                `json:"externalId,omitempty"`
+	Id                   *string                `json:"id,omitempty"`
+	Meta                 *ScimMeta              `json:"meta,omitempty"`
+	Name                 *ScimName              `json:"name,omitempty"`
+	Schemas              []string               `json:"schemas"`
+	UserName             string                 `json:"userName"`
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
+// ScimUserList defines model for ScimUserList.
+type ScimUserList struct {
+	Resources    []ScimUser `json:"Resources"`
+	ItemsPerPage int        `json:"itemsPerPage"`
+	Schemas      []string   `json:"schemas"`
+	StartIndex   int        `json:"startIndex"`
+	TotalResults int        `json:"totalResults"`
+}
+
+// BadRequest defines model for Bad Request.
+type BadRequest = ScimError
+
+// Conflict defines model for Conflict.
+type Conflict = ScimError
+
+// Forbidden defines model for Forbidden.
+type Forbidden = ScimError
+
+// InternalServerError defines model for Internal Server Error.
+type InternalServerError = ScimError
+
+// NotFound defines model for Not Found.
+type NotFound = ScimError
+
+// Unauthorized defines model for Unauthorized.
+type Unauthorized = ScimError
+
+// aPIKeyAuthContextKey is the context key for API Key Auth security scheme
+type aPIKeyAuthContextKey string
+
+// jWTAuthContextKey is the context key for JWT Auth security scheme
+type jWTAuthContextKey string
+
+// sCIMTokenAuthContextKey is the context key for SCIM Token Auth security scheme
+type sCIMTokenAuthContextKey string
+
+// ListScimUsersParams defines parameters for ListScimUsers.
+type ListScimUsersParams struct {
+	Filter *string `form:"filter,omitempty" json:"filter,omitempty"`
+
+	// StartIndex 1-based index of the first result.
+	StartIndex *int `form:"startIndex,omitempty" json:"startIndex,omitempty"`
+
+	// Count Maximum number of results, at most 100.
+	Count *int `form:"count,omitempty" json:"count,omitempty"`
+}
+
+// CreateScimUserApplicationScimPlusJSONRequestBody defines body for CreateScimUser for application/scim+json ContentType.
+type CreateScimUserApplicationScimPlusJSONRequestBody = ScimUser
+
+// PatchScimUserApplicationScimPlusJSONRequestBody defines body for PatchScimUser for application/scim+json ContentType.
+type PatchScimUserApplicationScimPlusJSONRequestBody = ScimPatchRequest
+
+// ReplaceScimUserApplicationScimPlusJSONRequestBody defines body for ReplaceScimUser for application/scim+json ContentType.
+type ReplaceScimUserApplicationScimPlusJSONRequestBody = ScimUser
+
+// Getter for additional properties for ScimEmail. Returns the specified
+// element and whether it was found
+func (a ScimEmail) Get(fieldName string) (value interface{}, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for ScimEmail
+func (a *ScimEmail) Set(fieldName string, value interface{}) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]interface{})
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for ScimEmail to handle AdditionalProperties
+func (a *ScimEmail) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if raw, found := object["primary"]; found {
+		err = json.Unmarshal(raw, &a.Primary)
+		if err != nil {
+			return fmt.Errorf("error reading 'primary': %w", err)
+		}
+		delete(object, "primary")
+	}
+
+	if raw, found := object["type"]; found {
+		err = json.Unmarshal(raw, &a.Type)
+		if err != nil {
+			return fmt.Errorf("error reading 'type': %w", err)
+		}
+		delete(object, "type")
+	}
+
+	if raw, found := object["value"]; found {
+		err = json.Unmarshal(raw, &a.Value)
+		if err != nil {
+			return fmt.Errorf("error reading 'value': %w", err)
+		}
+		delete(object, "value")
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]interface{})
+		for fieldName, fieldBuf := range object {
+			var fieldVal interface{}
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for ScimEmail to handle AdditionalProperties
+func (a ScimEmail) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	if a.Primary != nil {
+		object["primary"], err = json.Marshal(a.Primary)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'primary': %w", err)
+		}
+	}
+
+	if a.Type != nil {
+		object["type"], err = json.Marshal(a.Type)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'type': %w", err)
+		}
+	}
+
+	object["value"], err = json.Marshal(a.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling 'value': %w", err)
+	}
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
+
+// Getter for additional properties for ScimName. Returns the specified
+// element and whether it was found
+func (a ScimName) Get(fieldName string) (value interface{}, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for ScimName
+func (a *ScimName) Set(fieldName string, value interface{}) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]interface{})
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for ScimName to handle AdditionalProperties
+func (a *ScimName) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if raw, found := object["familyName"]; found {
+		err = json.Unmarshal(raw, &a.FamilyName)
+		if err != nil {
+			return fmt.Errorf("error reading 'familyName': %w", err)
+		}
+		delete(object, "familyName")
+	}
+
+	if raw, found := object["formatted"]; found {
+		err = json.Unmarshal(raw, &a.Formatted)
+		if err != nil {
+			return fmt.Errorf("error reading 'formatted': %w", err)
+		}
+		delete(object, "formatted")
+	}
+
+	if raw, found := object["givenName"]; found {
+		err = json.Unmarshal(raw, &a.GivenName)
+		if err != nil {
+			return fmt.Errorf("error reading 'givenName': %w", err)
+		}
+		delete(object, "givenName")
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]interface{})
+		for fieldName, fieldBuf := range object {
+			var fieldVal interface{}
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for ScimName to handle AdditionalProperties
+func (a ScimName) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	if a.FamilyName != nil {
+		object["familyName"], err = json.Marshal(a.FamilyName)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'familyName': %w", err)
+		}
+	}
+
+	if a.Formatted != nil {
+		object["formatted"], err = json.Marshal(a.Formatted)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'formatted': %w", err)
+		}
+	}
+
+	if a.GivenName != nil {
+		object["givenName"], err = json.Marshal(a.GivenName)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'givenName': %w", err)
+		}
+	}
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
+
+// Getter for additional properties for ScimUser. Returns the specified
+// element and whether it was found
+func (a ScimUser) Get(fieldName string) (value interface{}, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for ScimUser
+func (a *ScimUser) Set(fieldName string, value interface{}) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]interface{})
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for ScimUser to handle AdditionalProperties
+func (a *ScimUser) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if raw, found := object["active"]; found {
+		err = json.Unmarshal(raw, &a.Active)
+		if err != nil {
+			return fmt.Errorf("error reading 'active': %w", err)
+		}
+		delete(object, "active")
+	}
+
+	if raw, found := object["emails"]; found {
+		err = json.Unmarshal(raw, &a.Emails)
+		if err != nil {
+			return fmt.Errorf("error reading 'emails': %w", err)
+		}
+		delete(object, "emails")
+	}
+
+	if raw, found := object["externalId"]; found {
+		err = json.Unmarshal(raw, &a.ExternalId)
+		if err != nil {
+			return fmt.Errorf("error reading 'externalId': %w", err)
+		}
+		delete(object, "externalId")
+	}
+
+	if raw, found := object["id"]; found {
+		err = json.Unmarshal(raw, &a.Id)
+		if err != nil {
+			return fmt.Errorf("error reading 'id': %w", err)
+		}
+		delete(object, "id")
+	}
+
+	if raw, found := object["meta"]; found {
+		err = json.Unmarshal(raw, &a.Meta)
+		if err != nil {
+			return fmt.Errorf("error reading 'meta': %w", err)
+		}
+		delete(object, "meta")
+	}
+
+	if raw, found := object["name"]; found {
+		err = json.Unmarshal(raw, &a.Name)
+		if err != nil {
+			return fmt.Errorf("error reading 'name': %w", err)
+		}
+		delete(object, "name")
+	}
+
+	if raw, found := object["schemas"]; found {
+		err = json.Unmarshal(raw, &a.Schemas)
+		if err != nil {
+			return fmt.Errorf("error reading 'schemas': %w", err)
+		}
+		delete(object, "schemas")
+	}
+
+	if raw, found := object["userName"]; found {
+		err = json.Unmarshal(raw, &a.UserName)
+		if err != nil {
+			return fmt.Errorf("error reading 'userName': %w", err)
+		}
+		delete(object, "userName")
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]interface{})
+		for fieldName, fieldBuf := range object {
+			var fieldVal interface{}
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for ScimUser to handle AdditionalProperties
+func (a ScimUser) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	if a.Active != nil {
+		object["active"], err = json.Marshal(a.Active)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'active': %w", err)
+		}
+	}
+
+	if a.Emails != nil {
+		object["emails"], err = json.Marshal(a.Emails)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'emails': %w", err)
+		}
+	}
+
+	if a.ExternalId != nil {
+		object["externalId"], err = json.Marshal(a.ExternalId)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'externalId': %w", err)
+		}
+	}
+
+	if a.Id != nil {
+		object["id"], err = json.Marshal(a.Id)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'id': %w", err)
+		}
+	}
+
+	if a.Meta != nil {
+		object["meta"], err = json.Marshal(a.Meta)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'meta': %w", err)
+		}
+	}
+
+	if a.Name != nil {
+		object["name"], err = json.Marshal(a.Name)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'name': %w", err)
+		}
+	}
+
+	if a.Schemas != nil {
+		object["schemas"], err = json.Marshal(a.Schemas)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'schemas': %w", err)
+		}
+	}
+
+	object["userName"], err = json.Marshal(a.UserName)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling 'userName': %w", err)
+	}
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// List resource types
+	// (GET /scim/v2/ResourceTypes)
+	ListScimResourceTypes(w http.ResponseWriter, r *http.Request)
+	// Get resource type
+	// (GET /scim/v2/ResourceTypes/{id})
+	GetScimResourceType(w http.ResponseWriter, r *http.Request, id string)
+	// List schemas
+	// (GET /scim/v2/Schemas)
+	ListScimSchemas(w http.ResponseWriter, r *http.Request)
+	// Get schema
+	// (GET /scim/v2/Schemas/{id})
+	GetScimSchema(w http.ResponseWriter, r *http.Request, id string)
+	// Get service provider configuration
+	// (GET /scim/v2/ServiceProviderConfig)
+	GetScimServiceProviderConfig(w http.ResponseWriter, r *http.Request)
+	// List SCIM users
+	// (GET /scim/v2/Users)
+	ListScimUsers(w http.ResponseWriter, r *http.Request, params ListScimUsersParams)
+	// Create SCIM user
+	// (POST /scim/v2/Users)
+	CreateScimUser(w http.ResponseWriter, r *http.Request)
+	// Delete SCIM user
+	// (DELETE /scim/v2/Users/{id})
+	DeleteScimUser(w http.ResponseWriter, r *http.Request, id string)
+	// Get SCIM user
+	// (GET /scim/v2/Users/{id})
+	GetScimUser(w http.ResponseWriter, r *http.Request, id string)
+	// Patch SCIM user
+	// (PATCH /scim/v2/Users/{id})
+	PatchScimUser(w http.ResponseWriter, r *http.Request, id string)
+	// Replace SCIM user
+	// (PUT /scim/v2/Users/{id})
+	ReplaceScimUser(w http.ResponseWriter, r *http.Request, id string)
+}
+
+// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+
+type Unimplemented struct{}
+
+// List resource types
+// (GET /scim/v2/ResourceTypes)
+func (_ Unimplemented) ListScimResourceTypes(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get resource type
+// (GET /scim/v2/ResourceTypes/{id})
+func (_ Unimplemented) GetScimResourceType(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List schemas
+// (GET /scim/v2/Schemas)
+func (_ Unimplemented) ListScimSchemas(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get schema
+// (GET /scim/v2/Schemas/{id})
+func (_ Unimplemented) GetScimSchema(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get service provider configuration
+// (GET /scim/v2/ServiceProviderConfig)
+func (_ Unimplemented) GetScimServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List SCIM users
+// (GET /scim/v2/Users)
+func (_ Unimplemented) ListScimUsers(w http.ResponseWriter, r *http.Request, params ListScimUsersParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Create SCIM user
+// (POST /scim/v2/Users)
+func (_ Unimplemented) CreateScimUser(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Delete SCIM user
+// (DELETE /scim/v2/Users/{id})
+func (_ Unimplemented) DeleteScimUser(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get SCIM user
+// (GET /scim/v2/Users/{id})
+func (_ Unimplemented) GetScimUser(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Patch SCIM user
+// (PATCH /scim/v2/Users/{id})
+func (_ Unimplemented) PatchScimUser(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Replace SCIM user
+// (PUT /scim/v2/Users/{id})
+func (_ Unimplemented) ReplaceScimUser(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// ListScimResourceTypes operation middleware
+func (siw *ServerInterfaceWrapper) ListScimResourceTypes(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SCIM_Token_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListScimResourceTypes(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetScimResourceType operation middleware
+func (siw *ServerInterfaceWrapper) GetScimResourceType(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SCIM_Token_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetScimResourceType(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListScimSchemas operation middleware
+func (siw *ServerInterfaceWrapper) ListScimSchemas(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SCIM_Token_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListScimSchemas(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetScimSchema operation middleware
+func (siw *ServerInterfaceWrapper) GetScimSchema(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SCIM_Token_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetScimSchema(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetScimServiceProviderConfig operation middleware
+func (siw *ServerInterfaceWrapper) GetScimServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SCIM_Token_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetScimServiceProviderConfig(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListScimUsers operation middleware
+func (siw *ServerInterfaceWrapper) ListScimUsers(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SCIM_Token_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListScimUsersParams
+
+	// ------------- Optional query parameter "filter" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "filter", r.URL.Query(), &params.Filter, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "filter"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "filter", Err: err})
+		}
+		return
+	}
+
+	// ------------- Optional query parameter "startIndex" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "startIndex", r.URL.Query(), &params.StartIndex, runtime.BindQueryParameterOptions{Type: "integer", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "startIndex"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "startIndex", Err: err})
+		}
+		return
+	}
+
+	// ------------- Optional query parameter "count" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "count", r.URL.Query(), &params.Count, runtime.BindQueryParameterOptions{Type: "integer", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "count"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "count", Err: err})
+		}
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListScimUsers(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateScimUser operation middleware
+func (siw *ServerInterfaceWrapper) CreateScimUser(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SCIM_Token_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateScimUser(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteScimUser operation middleware
+func (siw *ServerInterfaceWrapper) DeleteScimUser(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SCIM_Token_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteScimUser(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetScimUser operation middleware
+func (siw *ServerInterfaceWrapper) GetScimUser(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SCIM_Token_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetScimUser(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PatchScimUser operation middleware
+func (siw *ServerInterfaceWrapper) PatchScimUser(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SCIM_Token_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PatchScimUser(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ReplaceScimUser operation middleware
+func (siw *ServerInterfaceWrapper) ReplaceScimUser(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SCIM_Token_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReplaceScimUser(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL          string
+	BaseRouter       chi.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/scim/v2/ResourceTypes", wrapper.ListScimResourceTypes)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/scim/v2/ResourceTypes/{id}", wrapper.GetScimResourceType)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/scim/v2/Schemas", wrapper.ListScimSchemas)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/scim/v2/Schemas/{id}", wrapper.GetScimSchema)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/scim/v2/ServiceProviderConfig", wrapper.GetScimServiceProviderConfig)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/scim/v2/Users", wrapper.ListScimUsers)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/scim/v2/Users", wrapper.CreateScimUser)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/scim/v2/Users/{id}", wrapper.DeleteScimUser)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/scim/v2/Users/{id}", wrapper.GetScimUser)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/scim/v2/Users/{id}", wrapper.PatchScimUser)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/scim/v2/Users/{id}", wrapper.ReplaceScimUser)
+	})
+
+	return r
+}
+
+type BadRequestApplicationScimPlusJSONResponse ScimError
+
+type ConflictApplicationScimPlusJSONResponse ScimError
+
+type ForbiddenApplicationScimPlusJSONResponse ScimError
+
+type InternalServerErrorApplicationScimPlusJSONResponse ScimError
+
+type NotFoundApplicationScimPlusJSONResponse ScimError
+
+type UnauthorizedApplicationScimPlusJSONResponse ScimError
+
+type ListScimResourceTypesRequestObject struct {
+}
+
+type ListScimResourceTypesResponseObject interface {
+	VisitListScimResourceTypesResponse(w http.ResponseWriter) error
+}
+
+type ListScimResourceTypes200ApplicationScimPlusJSONResponse ScimDocumentList
+
+func (response ListScimResourceTypes200ApplicationScimPlusJSONResponse) VisitListScimResourceTypesResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScimResourceTypes401ApplicationScimPlusJSONResponse struct {
+	UnauthorizedApplicationScimPlusJSONResponse
+}
+
+func (response ListScimResourceTypes401ApplicationScimPlusJSONResponse) VisitListScimResourceTypesResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScimResourceTypes403ApplicationScimPlusJSONResponse struct {
+	ForbiddenApplicationScimPlusJSONResponse
+}
+
+func (response ListScimResourceTypes403ApplicationScimPlusJSONResponse) VisitListScimResourceTypesResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetScimResourceTypeRequestObject struct {
+	Id string `json:"id"`
+}
+
+type GetScimResourceTypeResponseObject interface {
+	VisitGetScimResourceTypeResponse(w http.ResponseWriter) error
+}
+
+type GetScimResourceType200ApplicationScimPlusJSONResponse ScimDocument
+
+func (response GetScimResourceType200ApplicationScimPlusJSONResponse) VisitGetScimResourceTypeResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetScimResourceType401ApplicationScimPlusJSONResponse struct {
+	UnauthorizedApplicationScimPlusJSONResponse
+}
+
+func (response GetScimResourceType401ApplicationScimPlusJSONResponse) VisitGetScimResourceTypeResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetScimResourceType403ApplicationScimPlusJSONResponse struct {
+	ForbiddenApplicationScimPlusJSONResponse
+}
+
+func (response GetScimResourceType403ApplicationScimPlusJSONResponse) VisitGetScimResourceTypeResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetScimResourceType404ApplicationScimPlusJSONResponse struct {
+	NotFoundApplicationScimPlusJSONResponse
+}
+
+func (response GetScimResourceType404ApplicationScimPlusJSONResponse) VisitGetScimResourceTypeResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(404)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScimSchemasRequestObject struct {
+}
+
+type ListScimSchemasResponseObject interface {
+	VisitListScimSchemasResponse(w http.ResponseWriter) error
+}
+
+type ListScimSchemas200ApplicationScimPlusJSONResponse ScimDocumentList
+
+func (response ListScimSchemas200ApplicationScimPlusJSONResponse) VisitListScimSchemasResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScimSchemas401ApplicationScimPlusJSONResponse struct {
+	UnauthorizedApplicationScimPlusJSONResponse
+}
+
+func (response ListScimSchemas401ApplicationScimPlusJSONResponse) VisitListScimSchemasResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScimSchemas403ApplicationScimPlusJSONResponse struct {
+	ForbiddenApplicationScimPlusJSONResponse
+}
+
+func (response ListScimSchemas403ApplicationScimPlusJSONResponse) VisitListScimSchemasResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetScimSchemaRequestObject struct {
+	Id string `json:"id"`
+}
+
+type GetScimSchemaResponseObject interface {
+	VisitGetScimSchemaResponse(w http.ResponseWriter) error
+}
+
+type GetScimSchema200ApplicationScimPlusJSONResponse ScimDocument
+
+func (response GetScimSchema200ApplicationScimPlusJSONResponse) VisitGetScimSchemaResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetScimSchema401ApplicationScimPlusJSONResponse struct {
+	UnauthorizedApplicationScimPlusJSONResponse
+}
+
+func (response GetScimSchema401ApplicationScimPlusJSONResponse) VisitGetScimSchemaResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetScimSchema403ApplicationScimPlusJSONResponse struct {
+	ForbiddenApplicationScimPlusJSONResponse
+}
+
+func (response GetScimSchema403ApplicationScimPlusJSONResponse) VisitGetScimSchemaResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetScimSchema404ApplicationScimPlusJSONResponse struct {
+	NotFoundApplicationScimPlusJSONResponse
+}
+
+func (response GetScimSchema404ApplicationScimPlusJSONResponse) VisitGetScimSchemaResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(404)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetScimServiceProviderConfigRequestObject struct {
+}
+
+type GetScimServiceProviderConfigResponseObject interface {
+	VisitGetScimServiceProviderConfigResponse(w http.ResponseWriter) error
+}
+
+type GetScimServiceProviderConfig200ApplicationScimPlusJSONResponse ScimDocument
+
+func (response GetScimServiceProviderConfig200ApplicationScimPlusJSONResponse) VisitGetScimServiceProviderConfigResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetScimServiceProviderConfig401ApplicationScimPlusJSONResponse struct {
+	UnauthorizedApplicationScimPlusJSONResponse
+}
+
+func (response GetScimServiceProviderConfig401ApplicationScimPlusJSONResponse) VisitGetScimServiceProviderConfigResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetScimServiceProviderConfig403ApplicationScimPlusJSONResponse struct {
+	ForbiddenApplicationScimPlusJSONResponse
+}
+
+func (response GetScimServiceProviderConfig403ApplicationScimPlusJSONResponse) VisitGetScimServiceProviderConfigResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScimUsersRequestObject struct {
+	Params ListScimUsersParams
+}
+
+type ListScimUsersResponseObject interface {
+	VisitListScimUsersResponse(w http.ResponseWriter) error
+}
+
+type ListScimUsers200ApplicationScimPlusJSONResponse ScimUserList
+
+func (response ListScimUsers200ApplicationScimPlusJSONResponse) VisitListScimUsersResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScimUsers400ApplicationScimPlusJSONResponse struct {
+	BadRequestApplicationScimPlusJSONResponse
+}
+
+func (response ListScimUsers400ApplicationScimPlusJSONResponse) VisitListScimUsersResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(400)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScimUsers401ApplicationScimPlusJSONResponse struct {
+	UnauthorizedApplicationScimPlusJSONResponse
+}
+
+func (response ListScimUsers401ApplicationScimPlusJSONResponse) VisitListScimUsersResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScimUsers403ApplicationScimPlusJSONResponse struct {
+	ForbiddenApplicationScimPlusJSONResponse
+}
+
+func (response ListScimUsers403ApplicationScimPlusJSONResponse) VisitListScimUsersResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ListScimUsers500ApplicationScimPlusJSONResponse struct {
+	InternalServerErrorApplicationScimPlusJSONResponse
+}
+
+func (response ListScimUsers500ApplicationScimPlusJSONResponse) VisitListScimUsersResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type CreateScimUserRequestObject struct {
+	Body *CreateScimUserApplicationScimPlusJSONRequestBody
+}
+
+type CreateScimUserResponseObject interface {
+	VisitCreateScimUserResponse(w http.ResponseWriter) error
+}
+
+type CreateScimUser201ApplicationScimPlusJSONResponse ScimUser
+
+func (response CreateScimUser201ApplicationScimPlusJSONResponse) VisitCreateScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(201)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type CreateScimUser400ApplicationScimPlusJSONResponse struct {
+	BadRequestApplicationScimPlusJSONResponse
+}
+
+func (response CreateScimUser400ApplicationScimPlusJSONResponse) VisitCreateScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(400)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type CreateScimUser401ApplicationScimPlusJSONResponse struct {
+	UnauthorizedApplicationScimPlusJSONResponse
+}
+
+func (response CreateScimUser401ApplicationScimPlusJSONResponse) VisitCreateScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type CreateScimUser403ApplicationScimPlusJSONResponse struct {
+	ForbiddenApplicationScimPlusJSONResponse
+}
+
+func (response CreateScimUser403ApplicationScimPlusJSONResponse) VisitCreateScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type CreateScimUser409ApplicationScimPlusJSONResponse struct {
+	ConflictApplicationScimPlusJSONResponse
+}
+
+func (response CreateScimUser409ApplicationScimPlusJSONResponse) VisitCreateScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(409)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type CreateScimUser500ApplicationScimPlusJSONResponse struct {
+	InternalServerErrorApplicationScimPlusJSONResponse
+}
+
+func (response CreateScimUser500ApplicationScimPlusJSONResponse) VisitCreateScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type DeleteScimUserRequestObject struct {
+	Id string `json:"id"`
+}
+
+type DeleteScimUserResponseObject interface {
+	VisitDeleteScimUserResponse(w http.ResponseWriter) error
+}
+
+type DeleteScimUser204Response struct {
+}
+
+func (response DeleteScimUser204Response) VisitDeleteScimUserResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteScimUser401ApplicationScimPlusJSONResponse struct {
+	UnauthorizedApplicationScimPlusJSONResponse
+}
+
+func (response DeleteScimUser401ApplicationScimPlusJSONResponse) VisitDeleteScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type DeleteScimUser403ApplicationScimPlusJSONResponse struct {
+	ForbiddenApplicationScimPlusJSONResponse
+}
+
+func (response DeleteScimUser403ApplicationScimPlusJSONResponse) VisitDeleteScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type DeleteScimUser404ApplicationScimPlusJSONResponse struct {
+	NotFoundApplicationScimPlusJSONResponse
+}
+
+func (response DeleteScimUser404ApplicationScimPlusJSONResponse) VisitDeleteScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(404)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type DeleteScimUser500ApplicationScimPlusJSONResponse struct {
+	InternalServerErrorApplicationScimPlusJSONResponse
+}
+
+func (response DeleteScimUser500ApplicationScimPlusJSONResponse) VisitDeleteScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetScimUserRequestObject struct {
+	Id string `json:"id"`
+}
+
+type GetScimUserResponseObject interface {
+	VisitGetScimUserResponse(w http.ResponseWriter) error
+}
+
+type GetScimUser200ApplicationScimPlusJSONResponse ScimUser
+
+func (response GetScimUser200ApplicationScimPlusJSONResponse) VisitGetScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetScimUser401ApplicationScimPlusJSONResponse struct {
+	UnauthorizedApplicationScimPlusJSONResponse
+}
+
+func (response GetScimUser401ApplicationScimPlusJSONResponse) VisitGetScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetScimUser403ApplicationScimPlusJSONResponse struct {
+	ForbiddenApplicationScimPlusJSONResponse
+}
+
+func (response GetScimUser403ApplicationScimPlusJSONResponse) VisitGetScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetScimUser404ApplicationScimPlusJSONResponse struct {
+	NotFoundApplicationScimPlusJSONResponse
+}
+
+func (response GetScimUser404ApplicationScimPlusJSONResponse) VisitGetScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(404)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetScimUser500ApplicationScimPlusJSONResponse struct {
+	InternalServerErrorApplicationScimPlusJSONResponse
+}
+
+func (response GetScimUser500ApplicationScimPlusJSONResponse) VisitGetScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type PatchScimUserRequestObject struct {
+	Id   string `json:"id"`
+	Body *PatchScimUserApplicationScimPlusJSONRequestBody
+}
+
+type PatchScimUserResponseObject interface {
+	VisitPatchScimUserResponse(w http.ResponseWriter) error
+}
+
+type PatchScimUser200ApplicationScimPlusJSONResponse ScimUser
+
+func (response PatchScimUser200ApplicationScimPlusJSONResponse) VisitPatchScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type PatchScimUser400ApplicationScimPlusJSONResponse struct {
+	BadRequestApplicationScimPlusJSONResponse
+}
+
+func (response PatchScimUser400ApplicationScimPlusJSONResponse) VisitPatchScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(400)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type PatchScimUser401ApplicationScimPlusJSONResponse struct {
+	UnauthorizedApplicationScimPlusJSONResponse
+}
+
+func (response PatchScimUser401ApplicationScimPlusJSONResponse) VisitPatchScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type PatchScimUser403ApplicationScimPlusJSONResponse struct {
+	ForbiddenApplicationScimPlusJSONResponse
+}
+
+func (response PatchScimUser403ApplicationScimPlusJSONResponse) VisitPatchScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type PatchScimUser404ApplicationScimPlusJSONResponse struct {
+	NotFoundApplicationScimPlusJSONResponse
+}
+
+func (response PatchScimUser404ApplicationScimPlusJSONResponse) VisitPatchScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(404)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type PatchScimUser409ApplicationScimPlusJSONResponse struct {
+	ConflictApplicationScimPlusJSONResponse
+}
+
+func (response PatchScimUser409ApplicationScimPlusJSONResponse) VisitPatchScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(409)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type PatchScimUser500ApplicationScimPlusJSONResponse struct {
+	InternalServerErrorApplicationScimPlusJSONResponse
+}
+
+func (response PatchScimUser500ApplicationScimPlusJSONResponse) VisitPatchScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ReplaceScimUserRequestObject struct {
+	Id   string `json:"id"`
+	Body *ReplaceScimUserApplicationScimPlusJSONRequestBody
+}
+
+type ReplaceScimUserResponseObject interface {
+	VisitReplaceScimUserResponse(w http.ResponseWriter) error
+}
+
+type ReplaceScimUser200ApplicationScimPlusJSONResponse ScimUser
+
+func (response ReplaceScimUser200ApplicationScimPlusJSONResponse) VisitReplaceScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ReplaceScimUser400ApplicationScimPlusJSONResponse struct {
+	BadRequestApplicationScimPlusJSONResponse
+}
+
+func (response ReplaceScimUser400ApplicationScimPlusJSONResponse) VisitReplaceScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(400)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ReplaceScimUser401ApplicationScimPlusJSONResponse struct {
+	UnauthorizedApplicationScimPlusJSONResponse
+}
+
+func (response ReplaceScimUser401ApplicationScimPlusJSONResponse) VisitReplaceScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ReplaceScimUser403ApplicationScimPlusJSONResponse struct {
+	ForbiddenApplicationScimPlusJSONResponse
+}
+
+func (response ReplaceScimUser403ApplicationScimPlusJSONResponse) VisitReplaceScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ReplaceScimUser404ApplicationScimPlusJSONResponse struct {
+	NotFoundApplicationScimPlusJSONResponse
+}
+
+func (response ReplaceScimUser404ApplicationScimPlusJSONResponse) VisitReplaceScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(404)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ReplaceScimUser409ApplicationScimPlusJSONResponse struct {
+	ConflictApplicationScimPlusJSONResponse
+}
+
+func (response ReplaceScimUser409ApplicationScimPlusJSONResponse) VisitReplaceScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(409)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ReplaceScimUser500ApplicationScimPlusJSONResponse struct {
+	InternalServerErrorApplicationScimPlusJSONResponse
+}
+
+func (response ReplaceScimUser500ApplicationScimPlusJSONResponse) VisitReplaceScimUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// List resource types
+	// (GET /scim/v2/ResourceTypes)
+	ListScimResourceTypes(ctx context.Context, request ListScimResourceTypesRequestObject) (ListScimResourceTypesResponseObject, error)
+	// Get resource type
+	// (GET /scim/v2/ResourceTypes/{id})
+	GetScimResourceType(ctx context.Context, request GetScimResourceTypeRequestObject) (GetScimResourceTypeResponseObject, error)
+	// List schemas
+	// (GET /scim/v2/Schemas)
+	ListScimSchemas(ctx context.Context, request ListScimSchemasRequestObject) (ListScimSchemasResponseObject, error)
+	// Get schema
+	// (GET /scim/v2/Schemas/{id})
+	GetScimSchema(ctx context.Context, request GetScimSchemaRequestObject) (GetScimSchemaResponseObject, error)
+	// Get service provider configuration
+	// (GET /scim/v2/ServiceProviderConfig)
+	GetScimServiceProviderConfig(ctx context.Context, request GetScimServiceProviderConfigRequestObject) (GetScimServiceProviderConfigResponseObject, error)
+	// List SCIM users
+	// (GET /scim/v2/Users)
+	ListScimUsers(ctx context.Context, request ListScimUsersRequestObject) (ListScimUsersResponseObject, error)
+	// Create SCIM user
+	// (POST /scim/v2/Users)
+	CreateScimUser(ctx context.Context, request CreateScimUserRequestObject) (CreateScimUserResponseObject, error)
+	// Delete SCIM user
+	// (DELETE /scim/v2/Users/{id})
+	DeleteScimUser(ctx context.Context, request DeleteScimUserRequestObject) (DeleteScimUserResponseObject, error)
+	// Get SCIM user
+	// (GET /scim/v2/Users/{id})
+	GetScimUser(ctx context.Context, request GetScimUserRequestObject) (GetScimUserResponseObject, error)
+	// Patch SCIM user
+	// (PATCH /scim/v2/Users/{id})
+	PatchScimUser(ctx context.Context, request PatchScimUserRequestObject) (PatchScimUserResponseObject, error)
+	// Replace SCIM user
+	// (PUT /scim/v2/Users/{id})
+	ReplaceScimUser(ctx context.Context, request ReplaceScimUserRequestObject) (ReplaceScimUserResponseObject, error)
+}
+
+type StrictHandlerFunc func(ctx context.Context, w http.ResponseWriter, r *http.Request, request any) (any, error)
+type StrictMiddlewareFunc func(f StrictHandlerFunc, operationID string) StrictHandlerFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// ListScimResourceTypes operation middleware
+func (sh *strictHandler) ListScimResourceTypes(w http.ResponseWriter, r *http.Request) {
+	var request ListScimResourceTypesRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListScimResourceTypes(ctx, request.(ListScimResourceTypesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListScimResourceTypes")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListScimResourceTypesResponseObject); ok {
+		if err := validResponse.VisitListScimResourceTypesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetScimResourceType operation middleware
+func (sh *strictHandler) GetScimResourceType(w http.ResponseWriter, r *http.Request, id string) {
+	var request GetScimResourceTypeRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetScimResourceType(ctx, request.(GetScimResourceTypeRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetScimResourceType")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetScimResourceTypeResponseObject); ok {
+		if err := validResponse.VisitGetScimResourceTypeResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListScimSchemas operation middleware
+func (sh *strictHandler) ListScimSchemas(w http.ResponseWriter, r *http.Request) {
+	var request ListScimSchemasRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListScimSchemas(ctx, request.(ListScimSchemasRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListScimSchemas")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListScimSchemasResponseObject); ok {
+		if err := validResponse.VisitListScimSchemasResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetScimSchema operation middleware
+func (sh *strictHandler) GetScimSchema(w http.ResponseWriter, r *http.Request, id string) {
+	var request GetScimSchemaRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetScimSchema(ctx, request.(GetScimSchemaRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetScimSchema")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetScimSchemaResponseObject); ok {
+		if err := validResponse.VisitGetScimSchemaResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetScimServiceProviderConfig operation middleware
+func (sh *strictHandler) GetScimServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	var request GetScimServiceProviderConfigRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetScimServiceProviderConfig(ctx, request.(GetScimServiceProviderConfigRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetScimServiceProviderConfig")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetScimServiceProviderConfigResponseObject); ok {
+		if err := validResponse.VisitGetScimServiceProviderConfigResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListScimUsers operation middleware
+func (sh *strictHandler) ListScimUsers(w http.ResponseWriter, r *http.Request, params ListScimUsersParams) {
+	var request ListScimUsersRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListScimUsers(ctx, request.(ListScimUsersRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListScimUsers")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListScimUsersResponseObject); ok {
+		if err := validResponse.VisitListScimUsersResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateScimUser operation middleware
+func (sh *strictHandler) CreateScimUser(w http.ResponseWriter, r *http.Request) {
+	var request CreateScimUserRequestObject
+
+	var body CreateScimUserApplicationScimPlusJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateScimUser(ctx, request.(CreateScimUserRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateScimUser")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateScimUserResponseObject); ok {
+		if err := validResponse.VisitCreateScimUserResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteScimUser operation middleware
+func (sh *strictHandler) DeleteScimUser(w http.ResponseWriter, r *http.Request, id string) {
+	var request DeleteScimUserRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteScimUser(ctx, request.(DeleteScimUserRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteScimUser")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteScimUserResponseObject); ok {
+		if err := validResponse.VisitDeleteScimUserResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetScimUser operation middleware
+func (sh *strictHandler) GetScimUser(w http.ResponseWriter, r *http.Request, id string) {
+	var request GetScimUserRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetScimUser(ctx, request.(GetScimUserRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetScimUser")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetScimUserResponseObject); ok {
+		if err := validResponse.VisitGetScimUserResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PatchScimUser operation middleware
+func (sh *strictHandler) PatchScimUser(w http.ResponseWriter, r *http.Request, id string) {
+	var request PatchScimUserRequestObject
+
+	request.Id = id
+
+	var body PatchScimUserApplicationScimPlusJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PatchScimUser(ctx, request.(PatchScimUserRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PatchScimUser")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PatchScimUserResponseObject); ok {
+		if err := validResponse.VisitPatchScimUserResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ReplaceScimUser operation middleware
+func (sh *strictHandler) ReplaceScimUser(w http.ResponseWriter, r *http.Request, id string) {
+	var request ReplaceScimUserRequestObject
+
+	request.Id = id
+
+	var body ReplaceScimUserApplicationScimPlusJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ReplaceScimUser(ctx, request.(ReplaceScimUserRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ReplaceScimUser")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ReplaceScimUserResponseObject); ok {
+		if err := validResponse.VisitReplaceScimUserResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// Base64 encoded, compressed with deflate, json marshaled OpenAPI spec.
+// Stored as a slice of fixed-width chunks rather than one concatenated
+// const string: with thousands of chunks the chained `+` fold is several
+// times slower for the Go compiler than parsing a slice literal.
+var swaggerSpec = []string{
+	"7Fltb+O4Ef4rA7ZAW1Sxnd1cD/Wn5rLJwbfdXSPJ4QrkjIKWxjbvJFIhR964gf57QVKSJVuy48PGTW/z",
+	"zTJfZjjzzMwz5CMLVZIqiZIMGz4yjSZV0qD7+I5HcI33GRqyn6GShNL95Gkai5CTULJvQpH89RejpB0w",
+	"4QITbn/9UeOMDdkf+msBfT9q+jehSC61VprleR6wCE2oRWp3Y0PG8oBdKDmLRXhssVdKT0UUoTyy3JEk",
+	"1JLHcIN6iRr85OPq8FERXKlMRkeW+6PkGS2UFv/B44rOg2Ifh3U7+Z0Ks6SUHUXCTuXxWKsUNQk0bEg6",
+	"w82dziESJlRL1CuIih0CMIhwfXUB3/7t7C0YDO1kA98AKfi2xwJGqxTZkKnpLxiStUNdgX8KH3FpTfQj",
+	"u0ajMh36D0GYmKdYoDpUXgnlWvOV/XabjFGP+RztXsW4kIRz1KxpoUpiMcuQFnLetq0hrmkkI3xo35QU",
+	"8fgaTRaTaZuRB0zjfSa0RcRdpcPGwoacjbMENWNNOox9mXAR73N10wOpFgnXq5rOU6Vi5HJthRbzLHmc",
+	"tY1sHNNP69S2TAlNjSKk4hRbYn+b60KR3HYdxBCnzOw/ydphxYquQ31A4ttnCjVy8slgpnTCiQ1ZxAlP",
+	"SCTIgk3hAYuVTxKtSusCCB2n2lC9MbtL7Y88wcOAM+OJiFflui0d/TGLM2+NzsUSZcfavEPFMadw8SlF",
+	"XRmmqZBKW0WlnBY7MbxpL5WyyS4VavShqUCl22HZbONcrQg+GPad8K0p2XXKHw3qw+rFKEJJglaQarUU",
+	"EWoDBmUEs0zTAjVwIi2mGaEBLiPw2gA+EEpjVQmAFrgCrhF4GGJKGLmJYi6VxsgWl6apeUhiie1JC20S",
+	"PMwFPm+2WB4fPIsZtcNYRJ5g8uiTjFelbbamJUVO2KeGyx15wGQRGPvmuwD6rWkxM6i7I7AdPNWaXdD5",
+	"kpXe7vda5SsjWxUxzLSg1Y3d21vzfDyC97iC88xnOiHZkC2QR6hZCSb2r5Pz8ejkPa7W1Yanwn7nAfvh",
+	"p9tq9RS5Rn1VVqkffrplhTVdnLnR9R4LotQ5/2L0AW7VryirjZop4naBYBZcY2Spo0aCUMmZmGf2n8+C",
+	"FmC3+Pftp/eXH3v7JVpTCDlT24KcJm96A/hzyVWDkrWe/QUsoAyUJRFmSvucZdOQkHPI3PhMqwS4BLGZ",
+	"13pQhgAkPDWW+tIC3Z9/MuAyTwDrpFGOm8w5ENSsPp2suYw9LAmKsdT9fDxiAVuiNv5Ap71Bb2BtrFKU",
+	"PBVsyN72Br1T5mubg4DrJfrLN/3rWrF3I3MkXxyLtG8zGbMhasOrOTtotspvBoMv37w02oGO9ulscNq1",
+	"VaVgv9FjuUVv9y9aN8P1UGLDu8dtAN9N8uCxFhn+uxlrd5N8EjCTJZ5CO7uuwUWFVYnPjU8HImETK7nd",
+	"Xf1HEeWdPvset1zmEKB5goTauFO40HeMpwp8EbF6VvIlau2wzdQ/OSYK/qcIsCvO9q9YXyI8D2a+xw3I",
+	"7EbMzbqu7Qztm6r2vAb1FwjqWinf65snxbGf+xrBv5MINqU7d8ED9VKEOC64xIXjP3tx0rroq3Hx8znM",
+	"27VidhUddTbb7UhHImuOa9rmGinT0hRUMrHdvWWWlvjNREyWRN5kaaq063I1uhG8D8B8DiBUrvFNNXg0",
+	"KG1AWSIaVMyzTjADzzlNz11mBGAzRq+6XHFbub/WlzVWRDIVsmTdXLr2ur2I+INu5Sh84EnqCGvFhvEe",
+	"fmY8FiH+oxjthSr5mbHAZ7T7DPVqndK8IdiuNBZs2vX0ZMoNRiBs31RS6ZnQnnJlMfU6hDW6rS2BteZt",
+	"U+IH/iCSLAGZJVPUVqQXZALgBIkyBKeDQZfYUGWSdks8SqquevLOOB7sD8n6u9nx0vs3T1Gt/aXpGbmA",
+	"2ykrQmMjTQQsVaYlm1+4C+DSHUU9R0PfqWj1PC737m7ShnwLb6fPKXz7BsBaDT5zA8WFeO+FA/Bs8Pf9",
+	"K6qX3ZeIWA+8NWafUNkq/hphjITbYH7n/m+AuQGqs/brn8r5ft+od1RHHkYUX6AnvdV3eTLYySTbfTU4",
+	"XgJ4dfehJHWnr5+jcUwtW92O3nMLCvt4E0UBaExjHnpyqTFRS4QKb6YH6wcmRzBVRsDB6gXEf7WrQFWX",
+	"odW7kJvgH4cck7UMy70hEUxXcClJcxi92yaq7u3sWFW18fr3pOp69OB6wYX00ID8/y+9Di97YjhrydfX",
+	"PsBeHll8hfPXDOcCljupZF799Vh133Yon+T/HQA=",
+}
+
+// decodeSpec returns the embedded OpenAPI spec as raw JSON bytes,
+// after base64-decoding and flate-decompressing the embedded blob.
+func decodeSpec() ([]byte, error) {
+	encoded := strings.Join(swaggerSpec, "")
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr := flate.NewReader(bytes.NewReader(compressed))
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(zr); err != nil {
+		return nil, fmt.Errorf("read flate: %w", err)
+	}
+	if err := zr.Close(); err != nil {
+		return nil, fmt.Errorf("close flate reader: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cache of the decoded OpenAPI spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSpec returns the OpenAPI specification corresponding to the generated
+// code in this file. External references in the spec are resolved through
+// PathToRawSpec; externally-referenced files must be embedded in their
+// corresponding Go packages (via the import-mapping feature). URL-based
+// external refs are not supported.
+func GetSpec() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// GetSpecJSON returns the raw JSON bytes of the embedded OpenAPI
+// specification: decompressed but not unmarshaled. External references
+// are not resolved here; the bytes are the spec exactly as embedded by
+// codegen. The result is cached at package init time, so repeated calls
+// are cheap.
+func GetSpecJSON() ([]byte, error) {
+	return rawSpec()
+}
+
+// GetSwagger returns the OpenAPI specification corresponding to the
+// generated code in this file.
+//
+// Deprecated: GetSwagger predates kin-openapi renaming openapi3.Swagger
+// to openapi3.T. Use [GetSpec] instead. This wrapper is retained for
+// backwards compatibility.
+func GetSwagger() (*openapi3.T, error) {
+	return GetSpec()
+}
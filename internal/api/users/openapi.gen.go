@@ -11,10 +11,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
@@ -22,9 +24,54 @@ import (
 )
 
 const (
-	JWT_AuthScopes jWTAuthContextKey = "JWT_Auth.Scopes"
+	API_Key_AuthScopes aPIKeyAuthContextKey = "API_Key_Auth.Scopes"
+	JWT_AuthScopes     jWTAuthContextKey    = "JWT_Auth.Scopes"
 )
 
+// Defines values for UserStatus.
+const (
+	Active      UserStatus = "active"
+	Deactivated UserStatus = "deactivated"
+	Suspended   UserStatus = "suspended"
+)
+
+// Valid indicates whether the value is a known member of the UserStatus enum.
+func (e UserStatus) Valid() bool {
+	switch e {
+	case Active:
+		return true
+	case Deactivated:
+		return true
+	case Suspended:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for ExportUsersParamsFormat.
+const (
+	Csv    ExportUsersParamsFormat = "csv"
+	Ndjson ExportUsersParamsFormat = "ndjson"
+)
+
+// Valid indicates whether the value is a known member of the ExportUsersParamsFormat enum.
+func (e ExportUsersParamsFormat) Valid() bool {
+	switch e {
+	case Csv:
+		return true
+	case Ndjson:
+		return true
+	default:
+		return false
+	}
+}
+
+// MiddlewareError defines model for MiddlewareError.
+type MiddlewareError struct {
+	Error string `json:"error"`
+}
+
 // PaginationMetadata defines model for PaginationMetadata.
 type PaginationMetadata struct {
 	CurrentPage  int  `json:"current_page"`
@@ -37,10 +84,13 @@ type PaginationMetadata struct {
 
 // User defines model for User.
 type User struct {
-	Email     string `json:"email"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	UserId    string `json:"user_id"`
+	// DeletedAt Set if the user was soft deleted.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Email     string     `json:"email"`
+	FirstName string     `json:"first_name"`
+	LastName  string     `json:"last_name"`
+	Status    UserStatus `json:"status"`
+	UserId    string     `json:"user_id"`
 }
 
 // UserCreate Data transfer object for creating a new User.
@@ -50,67 +100,187 @@ type UserCreate struct {
 	LastName  string `json:"last_name"`
 }
 
+// UserImportError defines model for UserImportError.
+type UserImportError struct {
+	Message string `json:"message"`
+
+	// Row Line of the row in the NDJSON stream or CSV file, starting at 1.
+	Row int64 `json:"row"`
+}
+
+// UserImportReport defines model for UserImportReport.
+type UserImportReport struct {
+	// Errors The invalid rows, at most the first 1000.
+	Errors []UserImportError `json:"errors"`
+
+	// Failed Number of invalid rows.
+	Failed int64 `json:"failed"`
+
+	// Imported Number of created users.
+	Imported int64 `json:"imported"`
+}
+
 // UserListResponse defines model for UserListResponse.
 type UserListResponse struct {
 	Data       []User             `json:"data"`
 	Pagination PaginationMetadata `json:"pagination"`
 }
 
+// UserProfileUpdate Fields of the current user's profile to change, missing fields are left unchanged.
+type UserProfileUpdate struct {
+	FirstName *string `json:"first_name,omitempty"`
+	LastName  *string `json:"last_name,omitempty"`
+}
+
+// UserStatus defines model for UserStatus.
+type UserStatus string
+
+// IdempotencyKey defines model for Idempotency-Key.
+type IdempotencyKey = string
+
 // BadRequest defines model for Bad Request.
 type BadRequest struct {
 	Message string `json:"message"`
 }
 
+// ContentTooLarge defines model for Content Too Large.
+type ContentTooLarge struct {
+	Error string `json:"error"`
+}
+
 // Forbidden defines model for Forbidden.
 type Forbidden struct {
 	Message string `json:"message"`
 }
 
+// GatewayTimeout defines model for Gateway Timeout.
+type GatewayTimeout struct {
+	Error *string `json:"error,omitempty"`
+}
+
+// IdempotencyMismatch defines model for Idempotency Mismatch.
+type IdempotencyMismatch = MiddlewareError
+
 // InternalServerError defines model for Internal Server Error.
 type InternalServerError struct {
 	Message string `json:"message"`
 }
 
+// TooManyRequests defines model for Too Many Requests.
+type TooManyRequests struct {
+	Error *string `json:"error,omitempty"`
+}
+
 // Unauthorized defines model for Unauthorized.
 type Unauthorized struct {
 	Message string `json:"message"`
 }
 
+// UnsupportedMediaType defines model for Unsupported Media Type.
+type UnsupportedMediaType struct {
+	Message string `json:"message"`
+}
+
+// aPIKeyAuthContextKey is the context key for API Key Auth security scheme
+type aPIKeyAuthContextKey string
+
 // jWTAuthContextKey is the context key for JWT Auth security scheme
 type jWTAuthContextKey string
 
 // GetUserParams defines parameters for GetUser.
 type GetUserParams struct {
 	UserId string `form:"user_id" json:"user_id"`
+
+	// IncludeDeleted Include soft deleted users, requires the admin scope.
+	IncludeDeleted *bool `form:"include_deleted,omitempty" json:"include_deleted,omitempty"`
+
+	// IfNoneMatch ETags of cached representations, returns 304 if one is current.
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+}
+
+// CreateUserParams defines parameters for CreateUser.
+type CreateUserParams struct {
+	// IdempotencyKey Unique key of the request, e.g. a UUID. Retries with the same key get the stored response instead of executing the request again, marked with Idempotent-Replayed. Keys expire after IDEMPOTENCY_KEY_TTL.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
 }
 
 // GetUsersParams defines parameters for GetUsers.
 type GetUsersParams struct {
-	Page   *int    `form:"page,omitempty" json:"page,omitempty"`
-	Limit  *int    `form:"limit,omitempty" json:"limit,omitempty"`
-	Accept *string `json:"Accept,omitempty"`
+	Page  *int `form:"page,omitempty" json:"page,omitempty"`
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// IncludeDeleted Include soft deleted users, requires the admin scope.
+	IncludeDeleted *bool   `form:"include_deleted,omitempty" json:"include_deleted,omitempty"`
+	Accept         *string `json:"Accept,omitempty"`
+}
+
+// StreamUserEventsParams defines parameters for StreamUserEvents.
+type StreamUserEventsParams struct {
+	// LastEventID ID of the last event received, the stream resumes after it.
+	LastEventID *string `json:"Last-Event-ID,omitempty"`
+}
+
+// ExportUsersParams defines parameters for ExportUsers.
+type ExportUsersParams struct {
+	Format *ExportUsersParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// IncludeDeleted Include soft deleted users, requires the admin scope.
+	IncludeDeleted *bool `form:"include_deleted,omitempty" json:"include_deleted,omitempty"`
 }
 
+// ExportUsersParamsFormat defines parameters for ExportUsers.
+type ExportUsersParamsFormat string
+
+// UpdateMeJSONRequestBody defines body for UpdateMe for application/json ContentType.
+type UpdateMeJSONRequestBody = UserProfileUpdate
+
 // CreateUserJSONRequestBody defines body for CreateUser for application/json ContentType.
 type CreateUserJSONRequestBody = UserCreate
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Get current user
+	// (GET /me)
+	GetMe(w http.ResponseWriter, r *http.Request)
+	// Update current user
+	// (PATCH /me)
+	UpdateMe(w http.ResponseWriter, r *http.Request)
 	// Get user
 	// (GET /user)
 	GetUser(w http.ResponseWriter, r *http.Request, params GetUserParams)
 	// Create user
 	// (POST /user)
-	CreateUser(w http.ResponseWriter, r *http.Request)
+	CreateUser(w http.ResponseWriter, r *http.Request, params CreateUserParams)
 	// Get users
 	// (GET /users)
 	GetUsers(w http.ResponseWriter, r *http.Request, params GetUsersParams)
+	// Stream user events
+	// (GET /users/events)
+	StreamUserEvents(w http.ResponseWriter, r *http.Request, params StreamUserEventsParams)
+	// Export users
+	// (GET /users:export)
+	ExportUsers(w http.ResponseWriter, r *http.Request, params ExportUsersParams)
+	// Import users
+	// (POST /users:import)
+	ImportUsers(w http.ResponseWriter, r *http.Request)
 }
 
 // Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
 
 type Unimplemented struct{}
 
+// Get current user
+// (GET /me)
+func (_ Unimplemented) GetMe(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Update current user
+// (PATCH /me)
+func (_ Unimplemented) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // Get user
 // (GET /user)
 func (_ Unimplemented) GetUser(w http.ResponseWriter, r *http.Request, params GetUserParams) {
@@ -119,7 +289,7 @@ func (_ Unimplemented) GetUser(w http.ResponseWriter, r *http.Request, params Ge
 
 // Create user
 // (POST /user)
-func (_ Unimplemented) CreateUser(w http.ResponseWriter, r *http.Request) {
+func (_ Unimplemented) CreateUser(w http.ResponseWriter, r *http.Request, params CreateUserParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
@@ -129,6 +299,24 @@ func (_ Unimplemented) GetUsers(w http.ResponseWriter, r *http.Request, params G
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// Stream user events
+// (GET /users/events)
+func (_ Unimplemented) StreamUserEvents(w http.ResponseWriter, r *http.Request, params StreamUserEventsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Export users
+// (GET /users:export)
+func (_ Unimplemented) ExportUsers(w http.ResponseWriter, r *http.Request, params ExportUsersParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Import users
+// (POST /users:import)
+func (_ Unimplemented) ImportUsers(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // ServerInterfaceWrapper converts contexts to parameters.
 type ServerInterfaceWrapper struct {
 	Handler            ServerInterface
@@ -138,6 +326,46 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.Handler) http.Handler
 
+// GetMe operation middleware
+func (siw *ServerInterfaceWrapper) GetMe(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetMe(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateMe operation middleware
+func (siw *ServerInterfaceWrapper) UpdateMe(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateMe(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // GetUser operation middleware
 func (siw *ServerInterfaceWrapper) GetUser(w http.ResponseWriter, r *http.Request) {
 
@@ -160,6 +388,40 @@ func (siw *ServerInterfaceWrapper) GetUser(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// ------------- Optional query parameter "include_deleted" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "include_deleted", r.URL.Query(), &params.IncludeDeleted, runtime.BindQueryParameterOptions{Type: "boolean", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "include_deleted"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_deleted", Err: err})
+		}
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false, Type: "string", Format: ""})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.GetUser(w, r, params)
 	}))
@@ -174,8 +436,35 @@ func (siw *ServerInterfaceWrapper) GetUser(w http.ResponseWriter, r *http.Reques
 // CreateUser operation middleware
 func (siw *ServerInterfaceWrapper) CreateUser(w http.ResponseWriter, r *http.Request) {
 
+	var err error
+	_ = err
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateUserParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false, Type: "string", Format: ""})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateUser(w, r)
+		siw.Handler.CreateUser(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -195,6 +484,8 @@ func (siw *ServerInterfaceWrapper) GetUsers(w http.ResponseWriter, r *http.Reque
 
 	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
 
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
 	r = r.WithContext(ctx)
 
 	// Parameter object where we will unmarshal all parameters from the context
@@ -226,6 +517,19 @@ func (siw *ServerInterfaceWrapper) GetUsers(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// ------------- Optional query parameter "include_deleted" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "include_deleted", r.URL.Query(), &params.IncludeDeleted, runtime.BindQueryParameterOptions{Type: "boolean", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "include_deleted"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_deleted", Err: err})
+		}
+		return
+	}
+
 	headers := r.Header
 
 	// ------------- Optional header parameter "Accept" -------------
@@ -258,6 +562,131 @@ func (siw *ServerInterfaceWrapper) GetUsers(w http.ResponseWriter, r *http.Reque
 	handler.ServeHTTP(w, r)
 }
 
+// StreamUserEvents operation middleware
+func (siw *ServerInterfaceWrapper) StreamUserEvents(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StreamUserEventsParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Last-Event-ID" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Last-Event-ID")]; found {
+		var LastEventID string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Last-Event-ID", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Last-Event-ID", valueList[0], &LastEventID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false, Type: "string", Format: ""})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Last-Event-ID", Err: err})
+			return
+		}
+
+		params.LastEventID = &LastEventID
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.StreamUserEvents(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ExportUsers operation middleware
+func (siw *ServerInterfaceWrapper) ExportUsers(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportUsersParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "format", r.URL.Query(), &params.Format, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "format"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		}
+		return
+	}
+
+	// ------------- Optional query parameter "include_deleted" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "include_deleted", r.URL.Query(), &params.IncludeDeleted, runtime.BindQueryParameterOptions{Type: "boolean", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "include_deleted"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_deleted", Err: err})
+		}
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ExportUsers(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ImportUsers operation middleware
+func (siw *ServerInterfaceWrapper) ImportUsers(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, JWT_AuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, API_Key_AuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ImportUsers(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 type UnescapedCookieParamError struct {
 	ParamName string
 	Err       error
@@ -371,6 +800,12 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/me", wrapper.GetMe)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/me", wrapper.UpdateMe)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/user", wrapper.GetUser)
 	})
@@ -380,129 +815,681 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/users", wrapper.GetUsers)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/users/events", wrapper.StreamUserEvents)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/users:export", wrapper.ExportUsers)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users:import", wrapper.ImportUsers)
+	})
+
+	return r
+}
 
-	return r
-}
-
 type BadRequestJSONResponse struct {
 	Message string `json:"message"`
 }
 
-type ForbiddenJSONResponse struct {
-	Message string `json:"message"`
+type ContentTooLargeJSONResponse struct {
+	Error string `json:"error"`
+}
+
+type ForbiddenJSONResponse struct {
+	Message string `json:"message"`
+}
+
+type GatewayTimeoutJSONResponse struct {
+	Error *string `json:"error,omitempty"`
+}
+
+type IdempotencyMismatchJSONResponse MiddlewareError
+
+type InternalServerErrorJSONResponse struct {
+	Message string `json:"message"`
+}
+
+type TooManyRequestsResponseHeaders struct {
+	RetryAfter *int
+}
+type TooManyRequestsJSONResponse struct {
+	Body struct {
+		Error *string `json:"error,omitempty"`
+	}
+
+	Headers TooManyRequestsResponseHeaders
+}
+
+type UnauthorizedJSONResponse struct {
+	Message string `json:"message"`
+}
+
+type UnsupportedMediaTypeJSONResponse struct {
+	Message string `json:"message"`
+}
+
+type GetMeRequestObject struct {
+}
+
+type GetMeResponseObject interface {
+	VisitGetMeResponse(w http.ResponseWriter) error
+}
+
+type GetMe200JSONResponse User
+
+func (response GetMe200JSONResponse) VisitGetMeResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetMe401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response GetMe401JSONResponse) VisitGetMeResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetMe403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response GetMe403JSONResponse) VisitGetMeResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetMe500JSONResponse struct {
+	InternalServerErrorJSONResponse
+}
+
+func (response GetMe500JSONResponse) VisitGetMeResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type UpdateMeRequestObject struct {
+	Body *UpdateMeJSONRequestBody
+}
+
+type UpdateMeResponseObject interface {
+	VisitUpdateMeResponse(w http.ResponseWriter) error
+}
+
+type UpdateMe200JSONResponse User
+
+func (response UpdateMe200JSONResponse) VisitUpdateMeResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type UpdateMe400JSONResponse struct{ BadRequestJSONResponse }
+
+func (response UpdateMe400JSONResponse) VisitUpdateMeResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type UpdateMe401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response UpdateMe401JSONResponse) VisitUpdateMeResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type UpdateMe403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response UpdateMe403JSONResponse) VisitUpdateMeResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type UpdateMe500JSONResponse struct {
+	InternalServerErrorJSONResponse
+}
+
+func (response UpdateMe500JSONResponse) VisitUpdateMeResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetUserRequestObject struct {
+	Params GetUserParams
+}
+
+type GetUserResponseObject interface {
+	VisitGetUserResponse(w http.ResponseWriter) error
+}
+
+type GetUser200ResponseHeaders struct {
+	ETag string
+}
+
+type GetUser200JSONResponse struct {
+	Body    User
+	Headers GetUser200ResponseHeaders
+}
+
+func (response GetUser200JSONResponse) VisitGetUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response.Body); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetUser304ResponseHeaders struct {
+	ETag string
+}
+
+type GetUser304Response struct {
+	Headers GetUser304ResponseHeaders
+}
+
+func (response GetUser304Response) VisitGetUserResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(304)
+	return nil
+}
+
+type GetUser400JSONResponse struct {
+	Errors *[]struct {
+		Message string `json:"message"`
+	} `json:"errors,omitempty"`
+	Message string `json:"message"`
+}
+
+func (response GetUser400JSONResponse) VisitGetUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetUser403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response GetUser403JSONResponse) VisitGetUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetUser404JSONResponse struct {
+	Message string `json:"message"`
+}
+
+func (response GetUser404JSONResponse) VisitGetUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetUser500JSONResponse struct {
+	InternalServerErrorJSONResponse
+}
+
+func (response GetUser500JSONResponse) VisitGetUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type CreateUserRequestObject struct {
+	Params CreateUserParams
+	Body   *CreateUserJSONRequestBody
+}
+
+type CreateUserResponseObject interface {
+	VisitCreateUserResponse(w http.ResponseWriter) error
+}
+
+type CreateUser201JSONResponse User
+
+func (response CreateUser201JSONResponse) VisitCreateUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type CreateUser400JSONResponse struct {
+	Errors *[]struct {
+		Message string `json:"message"`
+	} `json:"errors,omitempty"`
+	Message string `json:"message"`
+}
+
+func (response CreateUser400JSONResponse) VisitCreateUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type CreateUser409JSONResponse MiddlewareError
+
+func (response CreateUser409JSONResponse) VisitCreateUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type CreateUser422JSONResponse struct {
+	IdempotencyMismatchJSONResponse
+}
+
+func (response CreateUser422JSONResponse) VisitCreateUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(422)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type CreateUser500JSONResponse struct {
+	Message string `json:"message"`
+}
+
+func (response CreateUser500JSONResponse) VisitCreateUserResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetUsersRequestObject struct {
+	Params GetUsersParams
+}
+
+type GetUsersResponseObject interface {
+	VisitGetUsersResponse(w http.ResponseWriter) error
+}
+
+type GetUsers200JSONResponse UserListResponse
+
+func (response GetUsers200JSONResponse) VisitGetUsersResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetUsers400JSONResponse struct{ BadRequestJSONResponse }
+
+func (response GetUsers400JSONResponse) VisitGetUsersResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetUsers401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response GetUsers401JSONResponse) VisitGetUsersResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetUsers403JSONResponse struct{ ForbiddenJSONResponse }
+
+func (response GetUsers403JSONResponse) VisitGetUsersResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type GetUsers500JSONResponse struct {
+	InternalServerErrorJSONResponse
+}
+
+func (response GetUsers500JSONResponse) VisitGetUsersResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type StreamUserEventsRequestObject struct {
+	Params StreamUserEventsParams
 }
 
-type InternalServerErrorJSONResponse struct {
-	Message string `json:"message"`
+type StreamUserEventsResponseObject interface {
+	VisitStreamUserEventsResponse(w http.ResponseWriter) error
 }
 
-type UnauthorizedJSONResponse struct {
-	Message string `json:"message"`
+type StreamUserEvents200TexteventStreamResponse struct {
+	Body          io.Reader
+	ContentLength int64
 }
 
-type GetUserRequestObject struct {
-	Params GetUserParams
-}
+func (response StreamUserEvents200TexteventStreamResponse) VisitStreamUserEventsResponse(w http.ResponseWriter) error {
 
-type GetUserResponseObject interface {
-	VisitGetUserResponse(w http.ResponseWriter) error
+	w.Header().Set("Content-Type", "text/event-stream")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		// If w doesn't support flushing, fall back to io.Copy.
+		_, err := io.Copy(w, response.Body)
+		return err
+	}
+	// text/event-stream messages are typically small; use a
+	// modest buffer and flush after each chunk so clients see
+	// events immediately instead of waiting on OS buffering.
+	buf := make([]byte, 4096)
+	for {
+		n, err := response.Body.Read(buf)
+		if n > 0 {
+			if _, writeErr := w.Write(buf[:n]); writeErr != nil {
+				return writeErr
+			}
+			flusher.Flush()
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
 }
 
-type GetUser200JSONResponse User
+type StreamUserEvents401JSONResponse struct{ UnauthorizedJSONResponse }
 
-func (response GetUser200JSONResponse) VisitGetUserResponse(w http.ResponseWriter) error {
+func (response StreamUserEvents401JSONResponse) VisitStreamUserEventsResponse(w http.ResponseWriter) error {
 
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(response); err != nil {
 		return err
 	}
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	w.WriteHeader(401)
 	_, err := buf.WriteTo(w)
 	return err
 }
 
-type GetUser400JSONResponse struct {
-	Errors *[]struct {
-		Message string `json:"message"`
-	} `json:"errors,omitempty"`
-	Message string `json:"message"`
-}
+type StreamUserEvents403JSONResponse struct{ ForbiddenJSONResponse }
 
-func (response GetUser400JSONResponse) VisitGetUserResponse(w http.ResponseWriter) error {
+func (response StreamUserEvents403JSONResponse) VisitStreamUserEventsResponse(w http.ResponseWriter) error {
 
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(response); err != nil {
 		return err
 	}
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+	w.WriteHeader(403)
 	_, err := buf.WriteTo(w)
 	return err
 }
 
-type GetUser404JSONResponse struct {
-	Message string `json:"message"`
+type StreamUserEvents500JSONResponse struct {
+	InternalServerErrorJSONResponse
 }
 
-func (response GetUser404JSONResponse) VisitGetUserResponse(w http.ResponseWriter) error {
+func (response StreamUserEvents500JSONResponse) VisitStreamUserEventsResponse(w http.ResponseWriter) error {
 
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(response); err != nil {
 		return err
 	}
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(404)
+	w.WriteHeader(500)
 	_, err := buf.WriteTo(w)
 	return err
 }
 
-type CreateUserRequestObject struct {
-	Body *CreateUserJSONRequestBody
+type ExportUsersRequestObject struct {
+	Params ExportUsersParams
 }
 
-type CreateUserResponseObject interface {
-	VisitCreateUserResponse(w http.ResponseWriter) error
+type ExportUsersResponseObject interface {
+	VisitExportUsersResponse(w http.ResponseWriter) error
 }
 
-type CreateUser201JSONResponse User
+type ExportUsers200ApplicationxNdjsonResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
 
-func (response CreateUser201JSONResponse) VisitCreateUserResponse(w http.ResponseWriter) error {
+func (response ExportUsers200ApplicationxNdjsonResponse) VisitExportUsersResponse(w http.ResponseWriter) error {
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		// If w doesn't support flushing, fall back to io.Copy.
+		_, err := io.Copy(w, response.Body)
+		return err
+	}
+	// text/event-stream messages are typically small; use a
+	// modest buffer and flush after each chunk so clients see
+	// events immediately instead of waiting on OS buffering.
+	buf := make([]byte, 4096)
+	for {
+		n, err := response.Body.Read(buf)
+		if n > 0 {
+			if _, writeErr := w.Write(buf[:n]); writeErr != nil {
+				return writeErr
+			}
+			flusher.Flush()
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+type ExportUsers200TextcsvResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response ExportUsers200TextcsvResponse) VisitExportUsersResponse(w http.ResponseWriter) error {
+
+	w.Header().Set("Content-Type", "text/csv")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportUsers401JSONResponse struct{ UnauthorizedJSONResponse }
+
+func (response ExportUsers401JSONResponse) VisitExportUsersResponse(w http.ResponseWriter) error {
 
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(response); err != nil {
 		return err
 	}
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(201)
+	w.WriteHeader(401)
 	_, err := buf.WriteTo(w)
 	return err
 }
 
-type CreateUser400JSONResponse struct {
-	Errors *[]struct {
-		Message string `json:"message"`
-	} `json:"errors,omitempty"`
-	Message string `json:"message"`
-}
+type ExportUsers403JSONResponse struct{ ForbiddenJSONResponse }
 
-func (response CreateUser400JSONResponse) VisitCreateUserResponse(w http.ResponseWriter) error {
+func (response ExportUsers403JSONResponse) VisitExportUsersResponse(w http.ResponseWriter) error {
 
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(response); err != nil {
 		return err
 	}
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+	w.WriteHeader(403)
 	_, err := buf.WriteTo(w)
 	return err
 }
 
-type CreateUser500JSONResponse struct {
-	Message string `json:"message"`
+type ExportUsers500JSONResponse struct {
+	InternalServerErrorJSONResponse
 }
 
-func (response CreateUser500JSONResponse) VisitCreateUserResponse(w http.ResponseWriter) error {
+func (response ExportUsers500JSONResponse) VisitExportUsersResponse(w http.ResponseWriter) error {
 
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(response); err != nil {
@@ -514,17 +1501,32 @@ func (response CreateUser500JSONResponse) VisitCreateUserResponse(w http.Respons
 	return err
 }
 
-type GetUsersRequestObject struct {
-	Params GetUsersParams
+type ExportUsers504JSONResponse struct{ GatewayTimeoutJSONResponse }
+
+func (response ExportUsers504JSONResponse) VisitExportUsersResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(504)
+	_, err := buf.WriteTo(w)
+	return err
 }
 
-type GetUsersResponseObject interface {
-	VisitGetUsersResponse(w http.ResponseWriter) error
+type ImportUsersRequestObject struct {
+	ContentType string
+	Body        io.Reader
 }
 
-type GetUsers200JSONResponse UserListResponse
+type ImportUsersResponseObject interface {
+	VisitImportUsersResponse(w http.ResponseWriter) error
+}
 
-func (response GetUsers200JSONResponse) VisitGetUsersResponse(w http.ResponseWriter) error {
+type ImportUsers200JSONResponse UserImportReport
+
+func (response ImportUsers200JSONResponse) VisitImportUsersResponse(w http.ResponseWriter) error {
 
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(response); err != nil {
@@ -536,9 +1538,9 @@ func (response GetUsers200JSONResponse) VisitGetUsersResponse(w http.ResponseWri
 	return err
 }
 
-type GetUsers400JSONResponse struct{ BadRequestJSONResponse }
+type ImportUsers400JSONResponse struct{ BadRequestJSONResponse }
 
-func (response GetUsers400JSONResponse) VisitGetUsersResponse(w http.ResponseWriter) error {
+func (response ImportUsers400JSONResponse) VisitImportUsersResponse(w http.ResponseWriter) error {
 
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(response); err != nil {
@@ -550,9 +1552,9 @@ func (response GetUsers400JSONResponse) VisitGetUsersResponse(w http.ResponseWri
 	return err
 }
 
-type GetUsers401JSONResponse struct{ UnauthorizedJSONResponse }
+type ImportUsers401JSONResponse struct{ UnauthorizedJSONResponse }
 
-func (response GetUsers401JSONResponse) VisitGetUsersResponse(w http.ResponseWriter) error {
+func (response ImportUsers401JSONResponse) VisitImportUsersResponse(w http.ResponseWriter) error {
 
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(response); err != nil {
@@ -564,9 +1566,9 @@ func (response GetUsers401JSONResponse) VisitGetUsersResponse(w http.ResponseWri
 	return err
 }
 
-type GetUsers403JSONResponse struct{ ForbiddenJSONResponse }
+type ImportUsers403JSONResponse struct{ ForbiddenJSONResponse }
 
-func (response GetUsers403JSONResponse) VisitGetUsersResponse(w http.ResponseWriter) error {
+func (response ImportUsers403JSONResponse) VisitImportUsersResponse(w http.ResponseWriter) error {
 
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(response); err != nil {
@@ -578,11 +1580,58 @@ func (response GetUsers403JSONResponse) VisitGetUsersResponse(w http.ResponseWri
 	return err
 }
 
-type GetUsers500JSONResponse struct {
+type ImportUsers413JSONResponse struct{ ContentTooLargeJSONResponse }
+
+func (response ImportUsers413JSONResponse) VisitImportUsersResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(413)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ImportUsers415JSONResponse struct {
+	UnsupportedMediaTypeJSONResponse
+}
+
+func (response ImportUsers415JSONResponse) VisitImportUsersResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(415)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ImportUsers429JSONResponse struct{ TooManyRequestsJSONResponse }
+
+func (response ImportUsers429JSONResponse) VisitImportUsersResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response.Body); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if response.Headers.RetryAfter != nil {
+		w.Header().Set("Retry-After", fmt.Sprint(*response.Headers.RetryAfter))
+	}
+	w.WriteHeader(429)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+type ImportUsers500JSONResponse struct {
 	InternalServerErrorJSONResponse
 }
 
-func (response GetUsers500JSONResponse) VisitGetUsersResponse(w http.ResponseWriter) error {
+func (response ImportUsers500JSONResponse) VisitImportUsersResponse(w http.ResponseWriter) error {
 
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(response); err != nil {
@@ -594,8 +1643,28 @@ func (response GetUsers500JSONResponse) VisitGetUsersResponse(w http.ResponseWri
 	return err
 }
 
+type ImportUsers504JSONResponse struct{ GatewayTimeoutJSONResponse }
+
+func (response ImportUsers504JSONResponse) VisitImportUsersResponse(w http.ResponseWriter) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(504)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
 // StrictServerInterface represents all server handlers.
 type StrictServerInterface interface {
+	// Get current user
+	// (GET /me)
+	GetMe(ctx context.Context, request GetMeRequestObject) (GetMeResponseObject, error)
+	// Update current user
+	// (PATCH /me)
+	UpdateMe(ctx context.Context, request UpdateMeRequestObject) (UpdateMeResponseObject, error)
 	// Get user
 	// (GET /user)
 	GetUser(ctx context.Context, request GetUserRequestObject) (GetUserResponseObject, error)
@@ -605,6 +1674,15 @@ type StrictServerInterface interface {
 	// Get users
 	// (GET /users)
 	GetUsers(ctx context.Context, request GetUsersRequestObject) (GetUsersResponseObject, error)
+	// Stream user events
+	// (GET /users/events)
+	StreamUserEvents(ctx context.Context, request StreamUserEventsRequestObject) (StreamUserEventsResponseObject, error)
+	// Export users
+	// (GET /users:export)
+	ExportUsers(ctx context.Context, request ExportUsersRequestObject) (ExportUsersResponseObject, error)
+	// Import users
+	// (POST /users:import)
+	ImportUsers(ctx context.Context, request ImportUsersRequestObject) (ImportUsersResponseObject, error)
 }
 
 type StrictHandlerFunc func(ctx context.Context, w http.ResponseWriter, r *http.Request, request any) (any, error)
@@ -636,6 +1714,61 @@ type strictHandler struct {
 	options     StrictHTTPServerOptions
 }
 
+// GetMe operation middleware
+func (sh *strictHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	var request GetMeRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetMe(ctx, request.(GetMeRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetMe")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetMeResponseObject); ok {
+		if err := validResponse.VisitGetMeResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdateMe operation middleware
+func (sh *strictHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	var request UpdateMeRequestObject
+
+	var body UpdateMeJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateMe(ctx, request.(UpdateMeRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateMe")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateMeResponseObject); ok {
+		if err := validResponse.VisitUpdateMeResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // GetUser operation middleware
 func (sh *strictHandler) GetUser(w http.ResponseWriter, r *http.Request, params GetUserParams) {
 	var request GetUserRequestObject
@@ -663,9 +1796,11 @@ func (sh *strictHandler) GetUser(w http.ResponseWriter, r *http.Request, params
 }
 
 // CreateUser operation middleware
-func (sh *strictHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+func (sh *strictHandler) CreateUser(w http.ResponseWriter, r *http.Request, params CreateUserParams) {
 	var request CreateUserRequestObject
 
+	request.Params = params
+
 	var body CreateUserJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
@@ -719,29 +1854,141 @@ func (sh *strictHandler) GetUsers(w http.ResponseWriter, r *http.Request, params
 	}
 }
 
+// StreamUserEvents operation middleware
+func (sh *strictHandler) StreamUserEvents(w http.ResponseWriter, r *http.Request, params StreamUserEventsParams) {
+	var request StreamUserEventsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.StreamUserEvents(ctx, request.(StreamUserEventsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "StreamUserEvents")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(StreamUserEventsResponseObject); ok {
+		if err := validResponse.VisitStreamUserEventsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ExportUsers operation middleware
+func (sh *strictHandler) ExportUsers(w http.ResponseWriter, r *http.Request, params ExportUsersParams) {
+	var request ExportUsersRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ExportUsers(ctx, request.(ExportUsersRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ExportUsers")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ExportUsersResponseObject); ok {
+		if err := validResponse.VisitExportUsersResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ImportUsers operation middleware
+func (sh *strictHandler) ImportUsers(w http.ResponseWriter, r *http.Request) {
+	var request ImportUsersRequestObject
+
+	request.ContentType = r.Header.Get("Content-Type")
+
+	request.Body = r.Body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ImportUsers(ctx, request.(ImportUsersRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ImportUsers")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ImportUsersResponseObject); ok {
+		if err := validResponse.VisitImportUsersResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // Base64 encoded, compressed with deflate, json marshaled OpenAPI spec.
 // Stored as a slice of fixed-width chunks rather than one concatenated
 // const string: with thousands of chunks the chained `+` fold is several
 // times slower for the Go compiler than parsing a slice literal.
 var swaggerSpec = []string{
-	"7FhPc9u2E/0qGPx+R1qSG/eim9MmHXfaTsZxpgePxgMTSxEZEqB3F45YD797BwD1hxItJdMk9aE3isA+",
-	"7D7s213qSeaubpwFyyTnTxKBGmcJ4o/XSotrePBAHH7mzjLY+KiapjK5YuPs9CM5G95RXkKtwlODrgFk",
-	"k1BqIFJLCI/cNiDnkhiNXcquyyTCgzcIWs5vNxsX2Xqju/8IOcsu7NRAOZomHCnnUnaZfOvw3mgN9iU6",
-	"d2UZ0KpKvAd8BBRvEB2+REc/WOW5dGj+CvYvzb8u60+Mh7xTS2OjP78DK614xJHcI4Llu2bojbEMS8AQ",
-	"cWVqw+NLFlbHLBuExyPL7FhVcZ2ObUDIHerRLXtcDfdnw9iG563DOqQ1kx8I8JAoqJWpRu4rk4VB4jur",
-	"ahhdrtSxVU+Ad0afToQtzODELULWu/hcSD8hKI5ODLPmZ8VKMCpLBaBINqJwKPJgYOxSKGHhkwgYE5l9",
-	"D1o+N/RnAs7k6qwwWldwptmdGVu4cMi9Ingf1fFHPDZddNfT85shvu7L+eHtr8VjGOr44v8IhZzL/023",
-	"DWHaS2+acDdeKUTVRj1s9HgKYUS5+6TsgGXJvZESkUmC3KPhNgaeYvn1zxtx6bmMnIBCwLcOa8VyHpZk",
-	"X0ICTlqVG9ySuUmFZ83pMJVuShBvVqpuKhCX764ENZBTzKW6FUsnKBV3hrqpFEPIJjZcBehf3Lr03/Sr",
-	"MpOPgJSQzyezySxw6BqwqjFyLl9NZpPzkI+KyxjX1PfCXUKsV+H+IkFXOhwAHO8lGKCqgQFJzm+fpAn4",
-	"Dx6wlZlMCbmjqS3ljB6ynYK+n7OLbDgO/DCbfVGDOJ1RhxU/EI5p5BCfFAnyeQ5Eha8yoawWXMKa9FKR",
-	"QGCPFtL73g60QCDnMQdhbL+SwhD3TreTwPrFF8ayVyNCM6eBfL56NzxU2zdpsRezmdid8yI3Fy9tELiY",
-	"XQjrQhX3Vg/KgJzfLjJJvq4VtkkVwidZsFoGQcTcJ7kI9crRiJBSH+m11CfRa6fbr5rsfbPqhiwECXYH",
-	"Mjv/12WWGuRGRmFLbJ6g/1PPPo99Ocqdr3RMUm81IPG6XK151h4EO2Hso6qMFtRaVquJCBB5ZcCyoDJi",
-	"1E6boh3YRixshVoqY+MV/PiPruBbkwE2dz58AYEWygpvYdVAHkpz7qw2wUBwqViEiRpsWDAsCnS1KHxV",
-	"mKoKY9oOAxNxDY1Dju8MUeIydgPfpAVQtTBFwGlClyWmydFKkRT5XLHostR+6VT/pc9rwP3IftBtd0b/",
-	"ccM01p+yhDSkhITfT4MswZagdIy0x73Mc2hGgb/bADCYUJ/5PO1LzRjUxrfpQQM7P20z+O6NRq9OG23/",
-	"cthK8LjF+P8Ae3m5O8LeLrrRhkZjSdpt3j3tTnoku0X3dwAAAP//",
+	"7Ft7c9s2tv8qGNw7c9sMJcuJ3bvVf2nsdtzGaSa2t9vJZDwQcSihIQEW59AWN6PvvoMHKUqiLLmON+5u",
+	"/0ksEQDP83de0CeemqI0GjQhH3/iMxASrP/z9FJM3f8SMLWqJGU0H/MLskZPGWhSVDMSU2YyRjNgFYJl",
+	"N2BRGZ0wBC2ZIqY0O8sG54LSGSPDqlIKAmYsk5ADQbtzyBNu4fdKWZB8TLaChGM6g0I4EqgugY85klV6",
+	"yheLRcJLYUUBFGk9k1CUhkCn9eAnqDfJvtLq9wrYR6gbet3bAClhMJwOmWBXV2cnQ/YOyCpAdqto5peh",
+	"KMK2KVD4gowFySxgaTQCUxoJhHTHwhzSipSedl/AxFQonbBC2I8gw8EtuTR4B2UuapBD9hPUyGBeKgtM",
+	"ZASWnZ2cnr/9+fL0zatfr386/fX68vK1k5NyDAVF8YRrUQAfb0igK75CzF+DntKMj58fHyd94mzY8dL8",
+	"Tkj2LlDvPqZGO1Ldn6Isc5UKJ9SD39BJ9lPnPaU1JVhS4ZQCEMUU+vTX1fX7duGHljIz+Q1SCpSt6pEv",
+	"Ev4q0MMujWGvhZ3CA4gEa43dTWJYtg+Blx3NT4ysmUKWOyIto5nQ3jJyVShq7NBR42lN/EcJmahyci6i",
+	"CNl84A4Z+B1Dx/z3xk6UlKCfomZ+EAS3omaXqgBT0ePo5V4qkEoybYhlSiucee9TQQkUaNxXDXG510HH",
+	"19i5wsKh2714/V8LGR/z/zlYgu9BeIoH50rKHG6FhVMvgy0Mrvk7uxXIRG5ByNoBqmSZsUwwqbIMrPOW",
+	"KJHAgCawWuTsAuwNWHbaSPupGZRz8XOh6waO8AuaVJorJ0eYpwASpLcL66KZd86EWSBbR+R2JqSrYgLW",
+	"2RdCarTExtRcjKkHL/3CAOMO1TuRt7NglaFIp9IEU/CmsUj4lRYVzYxV/wT5APE8mg6vNFZlaSyBZOcg",
+	"lWCXfttTo3TRhEz/knU3/NyRI+FvxVRpz/E5kJCCelhNK2tB03W5ym9rAAn3xtf/SMP8rp2lhZs7HpMh",
+	"kfvneNcCC6mxsnfJmiRW1yervK2+r2GrT2xXCD3aCOmkvBbUk7ICMdVJUh1Uosko5qDSeV9mbOH2cikI",
+	"PNjzjTwp4VAIlffoPOGZskjXIRfreZyLu54iCapwV2BwjF+ElYuEO06uldxtgMtXr1C5PKFhq6Vjm9Rf",
+	"WRAEm/I9ESQYWaExc3jn9/jwk7oNLh0WTMMtu4p5/pofPYZM95VBePkGwwmfDzIPAANJZqB0ZtxLJgLh",
+	"wmvjjX9tsMVFFM9Z4UBuC1psx6yEW3O7KdTXSkNbqphbFrOWNyc/Xvz8hiFZEIXLTl5d/J1lKoeEIQkb",
+	"pE3scMWmlaZvjniyy0EdHckdoNll8x24f7egIm6y4wKo0jciV9Kxg4mjsjAYaiqvE3Y4Go18fUNQ7OUN",
+	"XYkvo7iwVtTefoTKQW7S8qYNzF2K9pJYwlURQtldx3q7B+nRBv+IJtqXtEwkjWS3qeW1QnoXi7geeIzR",
+	"ZW/R9smzbAPWrhN6Qts6i53DkkDeNs7eWuMM/Mq3DjbF/r2CfJlbxaDiRf9/yMqw17Ue0pnQU0hYoRCd",
+	"l2Rhn7DAcsiIVTqskJsQ9TAg6mXqooV80FXhBCJSUjfAE44VlqClV7oE/7Wzpo58OocjpJVVVHtYCtS+",
+	"fHvmegnsZUW+KultF/xj8PLtWWwUNHoulfu8SPiPv1y2uycgLNjvGwP+8ZfLprXg9oSnyzNmRGXIrhrM",
+	"3ISB07koyhyYoxNLSNHHiqJmU8Mw1CMERZkLAqcKUpS7o38wTbVyGZ/yhMd+Ex/zw+FoOHLEmxK0KBUf",
+	"8xfD0fDQKVPQzEvmIKhoCj1JwjugympcZgmNQYk89x0d8xH0kF02jxW2bp5ZU/i1PpokbKpuQHuDYEJL",
+	"lolC5XX4nOZCFciM7sBeW5glvC1Cz6TjGOgc+Fpv5vlo9NlKzRi+erP2o9Hhtu0tPQcrpYff9GL3pmUH",
+	"Y5Hw49Fo947+crVr/nz8/lPHbN9/WHxwjlQUwtZBkivIwBNOYorO7dxH5B88vMUyfq1z6HEnGEYDJ2u2",
+	"4RuY7LKxAGcbhdBiCpJNar9SydgwLa25URKstwysddq1n2BjG3YQKIim4G3lOyPrz2oFqxi7WCzWu7GL",
+	"L2aGe9hHt1/5n2a6QSW7rHeR8IMqFkYR3jaw5Cps7TbO38fo8HsFtl4Gh2VlsH9LPln3mzOd5pWElUrL",
+	"k48Ji8cGpxKyUJphakpou9trBKlw1nU8hvcQMjEmB6H7KHGDDJ8gpCKd+dZ9aQFBkxePJydg/4vRkSsU",
+	"jQYP70Hk2zvu2eCN0RCGG/wu2Xz4As7TbYL6grdKU0DMqjzx2OMkH6PtTGAUAcju5MKLCk1lU2jKj4YN",
+	"39pe61o186I+auOyA7/GU/tidNSfHvQqaU0ff/itR/eU/Lbips2jP3tnajPtfpR219FoxDZg874IeDQ6",
+	"eoA0H4mvo9DvN5WWnxelNzKK7ZmEwR4IDu2TfhTuI2+55GB9uBcQ5XFSgUDmfjnA4ReHsdBZamHqVrRp",
+	"+ZD/5e+rcoxwn5oqD0OxSkuwSE04aGdmlS+Ym+YI1prEfMg6MxCc+TMKI1VWr+z1Z9k6DL2jCr79d87G",
+	"XmpDM7ChSHORrVOY+YlYa00rE/71cZpChqTy3AW+0pqpBcTAzvPne+BJ34BwCUZPCTA7hgE6NZUmsCCZ",
+	"0KzSMC8hJZDMja+U2+Bm2MTc5AA0+RlYqF6yKs9ULJSX1uCuU5TGhh6fQgx25T7EiRAj18VUmTunBIsK",
+	"CYd34m5Apy3Q69q2qhE9RdBqUmPclRvjfslxHFfcMZRL+jeGkcaunU8niYbQp+HjTSPdkhG/TFMo6Yum",
+	"witt0L9qyramTNb7gn0tkip6wbbqEg+83+PWJtqFH0tgaIjEOJyET+Hml/QBwn/RWHM4kgmM7AwuQBM7",
+	"9d8O2alIZ2FJC+ZnJ+2djYomZh4eJ0w00clCarSG1A9DPMy/FkgDf+Tg7MQ9B3UTHSe+XpFvDMcLWm21",
+	"4+5msVulpbkdsrOss4yMcU2eOvEhBYEilS5w+P/jtbAmuLa0ZUDpDJaNRsdkoMFkqy7OjM5rh70kIkGx",
+	"MGeVzgGx7UF1Il0XB9grUxRBuBYCVQLZDISlCQjCzU5T0J/zo0DSJiKuwVOrCtcDjxKI4pXhIk2cVFnA",
+	"qgCM1yPU9qp6RVUPhBKCOQWTHQQyVrGkncpMlBa25r0X49ayi4Yfd9vPiyhw6QYY/ruluSp0lwqdpf9X",
+	"4EcwnZB2QWM8PfE5yM+Jdy08j2HejBTvxBaR5417WOlzlUkdJ81GOwOPI9IwG41tWX90UElqNCr0FwhR",
+	"ixJnxpmsiDczRUyGBqik77qhsZt+curPu0fSEE2ta87xhhkfcy1jUG1GQe0XKd70jn2eTJpwv3A+H0TO",
+	"7umGSfBkJ42HOvDPGrxTshIsy5WGWBU0Y/TGCgIsucHwn8V93e6j3bvXr2g+0O2DJ2zLHJzDx4uTfMyP",
+	"C95x9zDj9lVObJesaipk+hh93VcZQq/ff8jYsmURb57gLoUy7fHH+0Nn97LWilfCQ9G4nP0m7ZzXR2xb",
+	"+8MUMl8mh+TGVb1InWMTtrxh4MNwMzRU2veZ/bUZkQbs0nLlRoJfnyuMy2NGYiwFUGtuFwdZxNTKlfXp",
+	"DNKPy9lTU/BGMvvwLFykaPBse2fp2cGzB3tg1OBXfcDwdXOj5avG4b9OWKjnC39z0J0e5GLht1Cbev0e",
+	"HR4P2QmkufDFK7JnB8/YBFJRYfiNwRS0YxikE5dKqal5fYoVq1H0GmnF6eXrYKKV1eaPFB57LLZy2WZL",
+	"9d4xmFuwwJrLI8MnXuscHe6xY/Ouv995vA+BvddOfQvn293bN28e/ynxORjQXfi8/H0BHx+Ojv52/P/f",
+	"jEbugXOX5sEnXoJVRrqLFsUSItBtWSzTukH83h/azAw3QsCipeNTd+KIfPFh8a8BAA==",
 }
 
 // decodeSpec returns the embedded OpenAPI spec as raw JSON bytes,
@@ -0,0 +1,166 @@
+// Package audit records who changed what in the append-only audit_events table.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"reflect"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"com.tom-ludwig/go-server-template/internal/middleware"
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+// Actor types
+const (
+	ActorSubject   = "subject"
+	ActorAPIKey    = "api_key"
+	ActorAnonymous = "anonymous"
+)
+
+type contextKey string
+
+const clientIPContextKey contextKey = "audit_client_ip"
+
+// Event is a single mutation of a resource. Before and After are the API representations of the resource,
+// nil for creations and deletions. Only the fields that changed are stored.
+// An event without Action is not recorded, e.g. when the mutation changed nothing.
+type Event struct {
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Before       any
+	After        any
+}
+
+// ClientIP stores the client IP in the request context, so handlers without access to the request can record it.
+// It must run after chi's RealIP middleware.
+func ClientIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientIPContextKey, ip)))
+	})
+}
+
+// Mutate runs fn in a transaction and records the event it returns in the same transaction.
+// Nothing is recorded if fn fails, and the mutation is rolled back if the event cannot be recorded.
+func Mutate(ctx context.Context, db *pgxpool.Pool, fn func(q *repository.Queries) (Event, error)) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+
+	queries := repository.New(tx)
+	event, err := fn(queries)
+	if err != nil {
+		return err
+	}
+	if event.Action != "" {
+		if err := Record(ctx, queries, event); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// Record writes the event with the caller and request of ctx. queries must use the transaction of the mutation.
+func Record(ctx context.Context, queries *repository.Queries, event Event) error {
+	before, after, err := diff(event.Before, event.After)
+	if err != nil {
+		return fmt.Errorf("failed to diff audit event %s: %w", event.Action, err)
+	}
+
+	actor, actorType := actorOf(ctx)
+	ip, _ := ctx.Value(clientIPContextKey).(string)
+
+	err = queries.InsertAuditEvent(ctx, repository.InsertAuditEventParams{
+		Actor:        actor,
+		ActorType:    actorType,
+		Action:       event.Action,
+		ResourceType: event.ResourceType,
+		ResourceID:   event.ResourceID,
+		Before:       before,
+		After:        after,
+		RequestID:    chimiddleware.GetReqID(ctx),
+		Ip:           ip,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record audit event %s: %w", event.Action, err)
+	}
+	return nil
+}
+
+// actorOf identifies the caller, API keys by their key ID because their subject is the key owner
+func actorOf(ctx context.Context) (string, string) {
+	if keyID, ok := middleware.GetClaim[string](ctx, middleware.APIKeyIDClaim); ok {
+		return keyID, ActorAPIKey
+	}
+	if subject, ok := middleware.GetSubject(ctx); ok && subject != "" {
+		return subject, ActorSubject
+	}
+	return "", ActorAnonymous
+}
+
+// diff encodes the top-level fields of before and after that differ. A nil side stays NULL.
+func diff(before, after any) ([]byte, []byte, error) {
+	beforeFields, err := fields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterFields, err := fields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeFields != nil && afterFields != nil {
+		for name, value := range maps.Clone(beforeFields) {
+			if afterValue, ok := afterFields[name]; ok && reflect.DeepEqual(value, afterValue) {
+				delete(beforeFields, name)
+				delete(afterFields, name)
+			}
+		}
+	}
+
+	beforeJSON, err := encode(beforeFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterJSON, err := encode(afterFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return beforeJSON, afterJSON, nil
+}
+
+// fields converts a resource to its JSON object fields
+func fields(resource any) (map[string]any, error) {
+	if resource == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+func encode(fields map[string]any) ([]byte, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	return json.Marshal(fields)
+}
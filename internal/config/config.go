@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
@@ -13,6 +14,13 @@ type Config struct {
 	Port     string
 	LogLevel slog.Level
 
+	// TLS
+	TLSCertFile       string
+	TLSKeyFile        string
+	TLSClientCAFile   string
+	TLSClientAuth     string // none, optional or require
+	TLSReloadInterval time.Duration
+
 	// Database
 	PGHost        string
 	PGPort        string
@@ -33,10 +41,138 @@ type Config struct {
 	CORSAllowCredentials bool
 	CORSMaxAge           int
 
+	// Security headers - empty values omit a header
+	HSTSMaxAge                time.Duration // Strict-Transport-Security on HTTPS requests, 0 disables it
+	HSTSIncludeSubdomains     bool
+	HSTSPreload               bool
+	CSPPolicy                 string
+	CSPRoutePolicies          map[string]string // path prefix -> policy, e.g. for a Swagger UI
+	CSPReportOnly             bool              // report violations without blocking
+	CSPReportsEnabled         bool              // POST /csp-reports logs the violations, added to the policies as report-uri
+	ReferrerPolicy            string
+	PermissionsPolicy         string
+	CrossOriginOpenerPolicy   string
+	CrossOriginEmbedderPolicy string
+	CrossOriginResourcePolicy string
+
+	// Compression - responses are compressed with zstd, brotli or gzip as negotiated with Accept-Encoding
+	CompressionEnabled      bool
+	CompressionMinSize      int      // bytes, smaller responses are not worth compressing
+	CompressionContentTypes []string // media types that are compressed, Server-Sent Events never are
+
 	// OIDC/JWT Auth
 	OIDCEnabled  bool
 	OIDCIssuer   string // https://your-keycloak.com/realms/your-realm
 	OIDCAudience string // Expected audience
+
+	// Signing keys - static keys disable discovery, the cache file and degraded mode keep pods booting during IdP outages
+	OIDCJWKS                string // inline JSON Web Key Set
+	OIDCJWKSFile            string
+	OIDCJWKSCacheFile       string
+	OIDCAllowDegraded       bool
+	OIDCJWKSRefreshInterval time.Duration
+
+	// DPoP - sender-constrained tokens (RFC 9449) for the JWT scheme: disabled, optional or required
+	OIDCDPoP                string
+	OIDCDPoPPublicURL       string // external base URL to check the proof's htu claim against
+	OIDCDPoPMaxAge          time.Duration
+	OIDCDPoPReplayCacheSize int
+
+	RevocationReloadInterval time.Duration // Full reload of the token denylist, changes are also pushed via LISTEN/NOTIFY
+
+	// Dev Issuer - local OIDC issuer, never enable in production
+	DevIssuerEnabled bool
+	DevIssuerPort    string
+
+	// API Key Auth
+	APIKeysEnabled bool
+
+	// Client Certificate Auth
+	MTLSPrincipalRoles map[string][]string // certificate identity (SAN or CN) -> roles
+	MTLSRolesClaim     string
+
+	// Admin endpoints
+	AdminScope string // Scope required to call the /admin endpoints
+
+	// User lifecycle - deleted users can be restored within the retention window, then they are purged
+	UserRetention     time.Duration
+	UserPurgeInterval time.Duration
+
+	// Jobs - background jobs run by a worker embedded in serve or by dedicated worker processes
+	JobWorkerEmbedded bool
+	JobConcurrency    int
+	JobPollInterval   time.Duration
+	JobRetention      time.Duration // how long finished jobs are kept
+
+	// Scheduler - periodic tasks run on the replica elected as leader
+	SchedulerEnabled          bool
+	SchedulerSchedules        map[string]string // task name -> cron expression or "off", overrides the default schedule
+	SchedulerElectionInterval time.Duration
+	SchedulerRunRetention     time.Duration // how long the history of runs is kept
+
+	// ShutdownTimeout is how long requests and jobs get to finish after SIGINT or SIGTERM
+	ShutdownTimeout time.Duration
+	// RequestTimeout is the deadline of requests, operations may declare their own with x-timeout
+	RequestTimeout time.Duration
+	// RequestBodyLimit is the maximum request body size in bytes, operations may declare their own with x-body-limit
+	RequestBodyLimit int64
+	// JSONMaxDepth is the maximum nesting of objects and arrays in JSON request bodies
+	JSONMaxDepth int
+
+	// Idempotency keys - responses of operations marked x-idempotent are replayed for retries within the TTL
+	IdempotencyKeyTTL          time.Duration
+	IdempotencyCleanupInterval time.Duration
+
+	// Rate limiting - requests per client, identified by API key, subject or IP address
+	RateLimitEnabled   bool
+	RateLimitStore     string // memory (per replica) or postgres (shared by the replicas)
+	RateLimitAlgorithm string // token-bucket or sliding-window
+	RateLimitRequests  int    // default limit, operations may declare their own with x-rate-limit
+	RateLimitPeriod    time.Duration
+	// Failed authentications per IP address and period, further requests are rejected before authentication
+	AuthFailureLimitRequests int
+	AuthFailureLimitPeriod   time.Duration
+
+	// Concurrency limiting - requests processed at once, adapted to the latency; health, admin and streaming
+	// routes are never shed
+	ConcurrencyLimitEnabled  bool
+	ConcurrencyInitialLimit  int
+	ConcurrencyMinLimit      int
+	ConcurrencyMaxLimit      int
+	ConcurrencyLatencyTarget time.Duration // the limit shrinks while requests take longer
+	ConcurrencyQueueSize     int           // requests waiting for a slot before new ones are shed with 503
+	ConcurrencyQueueTimeout  time.Duration
+	ConcurrencyLongRunning   int // operations with an x-timeout above REQUEST_TIMEOUT processed at once, apart from the limit
+
+	// User event stream - GET /users/events streams the user events of the outbox as Server-Sent Events
+	SSEHeartbeatInterval time.Duration
+	SSEReplayWindow      time.Duration // how old events resumed with Last-Event-ID may be
+
+	// WebSocket - GET /ws serves subscriptions to user events
+	WebSocketSendBuffer   int // messages queued per connection, a client falling further behind is disconnected
+	WebSocketAuthTimeout  time.Duration
+	WebSocketPingInterval time.Duration
+
+	// Outbox - domain events written with the mutations are published by a relay on every replica
+	OutboxPublisher      string // log, webhook, file or none
+	OutboxWebhookURL     string
+	OutboxWebhookTimeout time.Duration
+	OutboxFile           string
+	OutboxPollInterval   time.Duration
+	OutboxBatchSize      int
+	OutboxRetention      time.Duration // how long published events are kept
+
+	// Webhooks - outbox events are delivered to the subscriptions managed with the admin API
+	WebhooksEnabled     bool
+	WebhookTimeout      time.Duration
+	WebhookMaxAttempts  int // deliveries are dead after this many failed attempts
+	WebhookPollInterval time.Duration
+
+	// SCIM - user provisioning by the identity provider
+	SCIMEnabled bool
+	SCIMToken   string // shared bearer secret for the IdP's SCIM client, optional if the IdP uses tokens with SCIMScope
+	SCIMScope   string
+	SCIMIssuer  string // stored as issuer of SCIM users, defaults to OIDC_ISSUER so /me finds them
 }
 
 func Load() *Config {
@@ -45,6 +181,13 @@ func Load() *Config {
 		Port:     getEnv("PORT", "8080"),
 		LogLevel: getEnvLogLevel("LOG_LEVEL", slog.LevelInfo),
 
+		// TLS - HTTPS is enabled when a certificate and key are configured
+		TLSCertFile:       getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:        getEnv("TLS_KEY_FILE", ""),
+		TLSClientCAFile:   getEnv("TLS_CLIENT_CA_FILE", ""),
+		TLSClientAuth:     strings.ToLower(getEnv("TLS_CLIENT_AUTH", "none")),
+		TLSReloadInterval: getEnvDuration("TLS_RELOAD_INTERVAL", 30*time.Second),
+
 		// Database
 		PGHost:        getEnv("PG_HOST", "localhost"),
 		PGPort:        getEnv("PG_PORT", "5432"),
@@ -60,15 +203,145 @@ func Load() *Config {
 		// CORS - Default to permissive for development, override in production
 		CORSAllowedOrigins:   getEnvSlice("CORS_ALLOWED_ORIGINS", []string{"*"}),
 		CORSAllowedMethods:   getEnvSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"}),
-		CORSAllowedHeaders:   getEnvSlice("CORS_ALLOWED_HEADERS", []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"}),
-		CORSExposedHeaders:   getEnvSlice("CORS_EXPOSED_HEADERS", []string{"Link"}),
+		CORSAllowedHeaders:   getEnvSlice("CORS_ALLOWED_HEADERS", []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "DPoP", "If-Match", "If-None-Match", "Idempotency-Key", "Last-Event-ID"}),
+		CORSExposedHeaders:   getEnvSlice("CORS_EXPOSED_HEADERS", []string{"Link", "WWW-Authenticate", "ETag", "Idempotent-Replayed", "RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"}),
 		CORSAllowCredentials: getEnvBool("CORS_ALLOW_CREDENTIALS", true),
 		CORSMaxAge:           getEnvInt("CORS_MAX_AGE", 300),
 
+		// Security headers - the API only serves JSON, pages like a Swagger UI need their own CSP
+		HSTSMaxAge:                getEnvDuration("HSTS_MAX_AGE", 365*24*time.Hour),
+		HSTSIncludeSubdomains:     getEnvBool("HSTS_INCLUDE_SUBDOMAINS", true),
+		HSTSPreload:               getEnvBool("HSTS_PRELOAD", false),
+		CSPPolicy:                 getEnv("CSP_POLICY", "default-src 'none'; frame-ancestors 'none'"),
+		CSPRoutePolicies:          getEnvPolicyMap("CSP_ROUTE_POLICIES"),
+		CSPReportOnly:             getEnvBool("CSP_REPORT_ONLY", false),
+		CSPReportsEnabled:         getEnvBool("CSP_REPORTS_ENABLED", false),
+		ReferrerPolicy:            getEnv("REFERRER_POLICY", "no-referrer"),
+		PermissionsPolicy:         getEnv("PERMISSIONS_POLICY", "accelerometer=(), camera=(), geolocation=(), gyroscope=(), magnetometer=(), microphone=(), payment=(), usb=()"),
+		CrossOriginOpenerPolicy:   getEnv("CROSS_ORIGIN_OPENER_POLICY", "same-origin"),
+		CrossOriginEmbedderPolicy: getEnv("CROSS_ORIGIN_EMBEDDER_POLICY", "require-corp"),
+		CrossOriginResourcePolicy: getEnv("CROSS_ORIGIN_RESOURCE_POLICY", "same-origin"),
+
+		// Compression
+		CompressionEnabled:      getEnvBool("COMPRESSION_ENABLED", true),
+		CompressionMinSize:      getEnvInt("COMPRESSION_MIN_SIZE", 1024),
+		CompressionContentTypes: getEnvSlice("COMPRESSION_CONTENT_TYPES", []string{"application/json", "application/scim+json", "application/x-ndjson", "text/csv", "text/plain", "text/html"}),
+
 		// OIDC/JWT Auth
 		OIDCEnabled:  getEnvBool("OIDC_ENABLED", false),
 		OIDCIssuer:   getEnv("OIDC_ISSUER", ""),
 		OIDCAudience: getEnv("OIDC_AUDIENCE", ""),
+
+		OIDCJWKS:                getEnv("OIDC_JWKS", ""),
+		OIDCJWKSFile:            getEnv("OIDC_JWKS_FILE", ""),
+		OIDCJWKSCacheFile:       getEnv("OIDC_JWKS_CACHE_FILE", ""),
+		OIDCAllowDegraded:       getEnvBool("OIDC_ALLOW_DEGRADED", false),
+		OIDCJWKSRefreshInterval: getEnvDuration("OIDC_JWKS_REFRESH_INTERVAL", 15*time.Minute),
+
+		OIDCDPoP:                getEnv("OIDC_DPOP", "disabled"),
+		OIDCDPoPPublicURL:       getEnv("OIDC_DPOP_PUBLIC_URL", ""),
+		OIDCDPoPMaxAge:          getEnvDuration("OIDC_DPOP_MAX_AGE", 5*time.Minute),
+		OIDCDPoPReplayCacheSize: getEnvInt("OIDC_DPOP_REPLAY_CACHE_SIZE", 100000),
+
+		RevocationReloadInterval: getEnvDuration("REVOCATION_RELOAD_INTERVAL", 5*time.Minute),
+
+		// Dev Issuer
+		DevIssuerEnabled: getEnvBool("DEV_ISSUER_ENABLED", false),
+		DevIssuerPort:    getEnv("DEV_ISSUER_PORT", "8081"),
+
+		// API Key Auth
+		APIKeysEnabled: getEnvBool("API_KEYS_ENABLED", false),
+
+		// Client Certificate Auth
+		MTLSPrincipalRoles: getEnvMap("MTLS_PRINCIPAL_ROLES"),
+		MTLSRolesClaim:     getEnv("MTLS_ROLES_CLAIM", "roles"),
+
+		// Admin endpoints
+		AdminScope: getEnv("ADMIN_SCOPE", "admin"),
+
+		// User lifecycle
+		UserRetention:     getEnvDuration("USER_RETENTION", 30*24*time.Hour),
+		UserPurgeInterval: getEnvDuration("USER_PURGE_INTERVAL", time.Hour),
+
+		// Jobs
+		JobWorkerEmbedded: getEnvBool("JOB_WORKER_EMBEDDED", true),
+		JobConcurrency:    getEnvInt("JOB_CONCURRENCY", 10),
+		JobPollInterval:   getEnvDuration("JOB_POLL_INTERVAL", time.Second),
+		JobRetention:      getEnvDuration("JOB_RETENTION", 7*24*time.Hour),
+
+		// Scheduler
+		SchedulerEnabled:          getEnvBool("SCHEDULER_ENABLED", true),
+		SchedulerSchedules:        getEnvStringMap("SCHEDULER_SCHEDULES"),
+		SchedulerElectionInterval: getEnvDuration("SCHEDULER_ELECTION_INTERVAL", 10*time.Second),
+		SchedulerRunRetention:     getEnvDuration("SCHEDULER_RUN_RETENTION", 30*24*time.Hour),
+
+		ShutdownTimeout:  getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		RequestTimeout:   getEnvDuration("REQUEST_TIMEOUT", 10*time.Second),
+		RequestBodyLimit: int64(getEnvInt("REQUEST_BODY_LIMIT", 1<<20)),
+		JSONMaxDepth:     getEnvInt("JSON_MAX_DEPTH", 32),
+
+		// Idempotency keys
+		IdempotencyKeyTTL:          getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		IdempotencyCleanupInterval: getEnvDuration("IDEMPOTENCY_CLEANUP_INTERVAL", time.Hour),
+
+		// Rate limiting
+		RateLimitEnabled:   getEnvBool("RATE_LIMIT_ENABLED", true),
+		RateLimitStore:     strings.ToLower(getEnv("RATE_LIMIT_STORE", "memory")),
+		RateLimitAlgorithm: strings.ToLower(getEnv("RATE_LIMIT_ALGORITHM", "token-bucket")),
+		RateLimitRequests:  getEnvInt("RATE_LIMIT_REQUESTS", 300),
+		RateLimitPeriod:    getEnvDuration("RATE_LIMIT_PERIOD", time.Minute),
+
+		AuthFailureLimitRequests: getEnvInt("AUTH_FAILURE_LIMIT_REQUESTS", 20),
+		AuthFailureLimitPeriod:   getEnvDuration("AUTH_FAILURE_LIMIT_PERIOD", time.Minute),
+
+		// Concurrency limiting
+		ConcurrencyLimitEnabled:  getEnvBool("CONCURRENCY_LIMIT_ENABLED", true),
+		ConcurrencyInitialLimit:  getEnvInt("CONCURRENCY_INITIAL_LIMIT", 100),
+		ConcurrencyMinLimit:      getEnvInt("CONCURRENCY_MIN_LIMIT", 10),
+		ConcurrencyMaxLimit:      getEnvInt("CONCURRENCY_MAX_LIMIT", 1000),
+		ConcurrencyLatencyTarget: getEnvDuration("CONCURRENCY_LATENCY_TARGET", time.Second),
+		ConcurrencyQueueSize:     getEnvInt("CONCURRENCY_QUEUE_SIZE", 100),
+		ConcurrencyQueueTimeout:  getEnvDuration("CONCURRENCY_QUEUE_TIMEOUT", time.Second),
+		ConcurrencyLongRunning:   getEnvInt("CONCURRENCY_LONG_RUNNING_LIMIT", 4),
+
+		// User event stream
+		SSEHeartbeatInterval: getEnvDuration("SSE_HEARTBEAT_INTERVAL", 15*time.Second),
+		SSEReplayWindow:      getEnvDuration("SSE_REPLAY_WINDOW", time.Hour),
+
+		// WebSocket
+		WebSocketSendBuffer:   getEnvInt("WEBSOCKET_SEND_BUFFER", 64),
+		WebSocketAuthTimeout:  getEnvDuration("WEBSOCKET_AUTH_TIMEOUT", 10*time.Second),
+		WebSocketPingInterval: getEnvDuration("WEBSOCKET_PING_INTERVAL", 30*time.Second),
+
+		// Outbox
+		OutboxPublisher:      strings.ToLower(getEnv("OUTBOX_PUBLISHER", "log")),
+		OutboxWebhookURL:     getEnv("OUTBOX_WEBHOOK_URL", ""),
+		OutboxWebhookTimeout: getEnvDuration("OUTBOX_WEBHOOK_TIMEOUT", 10*time.Second),
+		OutboxFile:           getEnv("OUTBOX_FILE", ""),
+		OutboxPollInterval:   getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxBatchSize:      getEnvInt("OUTBOX_BATCH_SIZE", 100),
+		OutboxRetention:      getEnvDuration("OUTBOX_RETENTION", 7*24*time.Hour),
+
+		// Webhooks
+		WebhooksEnabled:     getEnvBool("WEBHOOKS_ENABLED", false),
+		WebhookTimeout:      getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		WebhookMaxAttempts:  getEnvInt("WEBHOOK_MAX_ATTEMPTS", 10),
+		WebhookPollInterval: getEnvDuration("WEBHOOK_POLL_INTERVAL", time.Second),
+
+		// SCIM
+		SCIMEnabled: getEnvBool("SCIM_ENABLED", false),
+		SCIMToken:   getEnv("SCIM_TOKEN", ""),
+		SCIMScope:   getEnv("SCIM_SCOPE", "scim"),
+		SCIMIssuer:  getEnv("SCIM_ISSUER", ""),
+	}
+
+	// The dev issuer replaces the identity provider
+	if cfg.DevIssuerEnabled {
+		cfg.OIDCEnabled = true
+		cfg.OIDCIssuer = cfg.DevIssuerURL()
+	}
+	if cfg.SCIMIssuer == "" {
+		cfg.SCIMIssuer = cfg.OIDCIssuer
 	}
 
 	// Validate configuration
@@ -88,6 +361,26 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("PORT must be a valid port number (1-65535), got: %s", c.Port)
 	}
 
+	// Validate TLS configuration
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	switch c.TLSClientAuth {
+	case "none":
+	case "optional", "require":
+		if !c.TLSEnabled() {
+			return fmt.Errorf("TLS_CLIENT_AUTH=%s requires TLS_CERT_FILE and TLS_KEY_FILE", c.TLSClientAuth)
+		}
+		if c.TLSClientCAFile == "" {
+			return fmt.Errorf("TLS_CLIENT_AUTH=%s requires TLS_CLIENT_CA_FILE", c.TLSClientAuth)
+		}
+	default:
+		return fmt.Errorf("TLS_CLIENT_AUTH must be one of none, optional, require, got: %s", c.TLSClientAuth)
+	}
+	if c.TLSReloadInterval <= 0 {
+		return fmt.Errorf("TLS_RELOAD_INTERVAL must be positive, got: %s", c.TLSReloadInterval)
+	}
+
 	// Validate database configuration
 	if c.PGHost == "" {
 		return fmt.Errorf("PG_HOST cannot be empty")
@@ -112,9 +405,234 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("CORS_MAX_AGE must be non-negative, got: %d", c.CORSMaxAge)
 	}
 
+	if c.HSTSMaxAge < 0 {
+		return fmt.Errorf("HSTS_MAX_AGE cannot be negative, got: %s", c.HSTSMaxAge)
+	}
+	// https://hstspreload.org/#submission-requirements
+	if c.HSTSPreload && (c.HSTSMaxAge < 365*24*time.Hour || !c.HSTSIncludeSubdomains) {
+		return fmt.Errorf("HSTS_PRELOAD requires HSTS_MAX_AGE of at least 8760h and HSTS_INCLUDE_SUBDOMAINS")
+	}
+	for prefix := range c.CSPRoutePolicies {
+		if !strings.HasPrefix(prefix, "/") {
+			return fmt.Errorf("CSP_ROUTE_POLICIES paths must start with /, got: %s", prefix)
+		}
+	}
+
+	if c.CompressionMinSize < 0 {
+		return fmt.Errorf("COMPRESSION_MIN_SIZE cannot be negative, got: %d", c.CompressionMinSize)
+	}
+
+	if c.OIDCEnabled && c.OIDCIssuer == "" && !c.OIDCStaticKeys() {
+		return fmt.Errorf("OIDC_ISSUER must be set when OIDC_ENABLED is true, unless OIDC_JWKS or OIDC_JWKS_FILE is set")
+	}
+	if c.OIDCJWKS != "" && c.OIDCJWKSFile != "" {
+		return fmt.Errorf("only one of OIDC_JWKS and OIDC_JWKS_FILE can be set")
+	}
+	if c.OIDCJWKSRefreshInterval <= 0 {
+		return fmt.Errorf("OIDC_JWKS_REFRESH_INTERVAL must be positive, got: %s", c.OIDCJWKSRefreshInterval)
+	}
+	switch c.OIDCDPoP {
+	case "disabled", "optional", "required":
+	default:
+		return fmt.Errorf("OIDC_DPOP must be one of disabled, optional, required, got: %s", c.OIDCDPoP)
+	}
+	if c.OIDCDPoPMaxAge <= 0 {
+		return fmt.Errorf("OIDC_DPOP_MAX_AGE must be positive, got: %s", c.OIDCDPoPMaxAge)
+	}
+	if c.OIDCDPoPReplayCacheSize <= 0 {
+		return fmt.Errorf("OIDC_DPOP_REPLAY_CACHE_SIZE must be positive, got: %d", c.OIDCDPoPReplayCacheSize)
+	}
+
+	if c.RevocationReloadInterval <= 0 {
+		return fmt.Errorf("REVOCATION_RELOAD_INTERVAL must be positive, got: %s", c.RevocationReloadInterval)
+	}
+
+	if c.DevIssuerEnabled {
+		if portNum, err := strconv.Atoi(c.DevIssuerPort); err != nil || portNum < 1 || portNum > 65535 {
+			return fmt.Errorf("DEV_ISSUER_PORT must be a valid port number (1-65535), got: %s", c.DevIssuerPort)
+		}
+		if c.DevIssuerPort == c.Port {
+			return fmt.Errorf("DEV_ISSUER_PORT must differ from PORT")
+		}
+	}
+
+	if c.AdminScope == "" {
+		return fmt.Errorf("ADMIN_SCOPE cannot be empty")
+	}
+
+	if c.UserRetention < 0 {
+		return fmt.Errorf("USER_RETENTION cannot be negative, got: %s", c.UserRetention)
+	}
+	if c.UserPurgeInterval <= 0 {
+		return fmt.Errorf("USER_PURGE_INTERVAL must be positive, got: %s", c.UserPurgeInterval)
+	}
+
+	if c.JobConcurrency < 1 || c.JobConcurrency > 1000 {
+		return fmt.Errorf("JOB_CONCURRENCY must be between 1 and 1000, got: %d", c.JobConcurrency)
+	}
+	if c.JobPollInterval <= 0 {
+		return fmt.Errorf("JOB_POLL_INTERVAL must be positive, got: %s", c.JobPollInterval)
+	}
+	if c.JobRetention < 0 {
+		return fmt.Errorf("JOB_RETENTION cannot be negative, got: %s", c.JobRetention)
+	}
+	if c.SchedulerElectionInterval <= 0 {
+		return fmt.Errorf("SCHEDULER_ELECTION_INTERVAL must be positive, got: %s", c.SchedulerElectionInterval)
+	}
+	if c.SchedulerRunRetention < 0 {
+		return fmt.Errorf("SCHEDULER_RUN_RETENTION cannot be negative, got: %s", c.SchedulerRunRetention)
+	}
+	if c.ShutdownTimeout <= 0 {
+		return fmt.Errorf("SHUTDOWN_TIMEOUT must be positive, got: %s", c.ShutdownTimeout)
+	}
+	if c.RequestTimeout <= 0 {
+		return fmt.Errorf("REQUEST_TIMEOUT must be positive, got: %s", c.RequestTimeout)
+	}
+	if c.RequestBodyLimit < 1 {
+		return fmt.Errorf("REQUEST_BODY_LIMIT must be positive, got: %d", c.RequestBodyLimit)
+	}
+	if c.JSONMaxDepth < 1 {
+		return fmt.Errorf("JSON_MAX_DEPTH must be positive, got: %d", c.JSONMaxDepth)
+	}
+
+	if c.IdempotencyKeyTTL <= 0 {
+		return fmt.Errorf("IDEMPOTENCY_KEY_TTL must be positive, got: %s", c.IdempotencyKeyTTL)
+	}
+	if c.IdempotencyCleanupInterval <= 0 {
+		return fmt.Errorf("IDEMPOTENCY_CLEANUP_INTERVAL must be positive, got: %s", c.IdempotencyCleanupInterval)
+	}
+
+	if c.RateLimitStore != "memory" && c.RateLimitStore != "postgres" {
+		return fmt.Errorf("RATE_LIMIT_STORE must be memory or postgres, got: %s", c.RateLimitStore)
+	}
+	if c.RateLimitAlgorithm != "token-bucket" && c.RateLimitAlgorithm != "sliding-window" {
+		return fmt.Errorf("RATE_LIMIT_ALGORITHM must be token-bucket or sliding-window, got: %s", c.RateLimitAlgorithm)
+	}
+	if c.RateLimitRequests < 1 {
+		return fmt.Errorf("RATE_LIMIT_REQUESTS must be positive, got: %d", c.RateLimitRequests)
+	}
+	if c.RateLimitPeriod < time.Second {
+		return fmt.Errorf("RATE_LIMIT_PERIOD must be at least 1s, got: %s", c.RateLimitPeriod)
+	}
+	if c.AuthFailureLimitRequests < 1 {
+		return fmt.Errorf("AUTH_FAILURE_LIMIT_REQUESTS must be positive, got: %d", c.AuthFailureLimitRequests)
+	}
+	if c.AuthFailureLimitPeriod < time.Second {
+		return fmt.Errorf("AUTH_FAILURE_LIMIT_PERIOD must be at least 1s, got: %s", c.AuthFailureLimitPeriod)
+	}
+
+	if c.ConcurrencyMinLimit < 1 {
+		return fmt.Errorf("CONCURRENCY_MIN_LIMIT must be positive, got: %d", c.ConcurrencyMinLimit)
+	}
+	if c.ConcurrencyMaxLimit < c.ConcurrencyMinLimit {
+		return fmt.Errorf("CONCURRENCY_MAX_LIMIT must be at least CONCURRENCY_MIN_LIMIT, got: %d", c.ConcurrencyMaxLimit)
+	}
+	if c.ConcurrencyInitialLimit < c.ConcurrencyMinLimit || c.ConcurrencyInitialLimit > c.ConcurrencyMaxLimit {
+		return fmt.Errorf("CONCURRENCY_INITIAL_LIMIT must be between CONCURRENCY_MIN_LIMIT and CONCURRENCY_MAX_LIMIT, got: %d", c.ConcurrencyInitialLimit)
+	}
+	if c.ConcurrencyLatencyTarget <= 0 {
+		return fmt.Errorf("CONCURRENCY_LATENCY_TARGET must be positive, got: %s", c.ConcurrencyLatencyTarget)
+	}
+	if c.ConcurrencyQueueSize < 0 {
+		return fmt.Errorf("CONCURRENCY_QUEUE_SIZE cannot be negative, got: %d", c.ConcurrencyQueueSize)
+	}
+	if c.ConcurrencyQueueTimeout <= 0 {
+		return fmt.Errorf("CONCURRENCY_QUEUE_TIMEOUT must be positive, got: %s", c.ConcurrencyQueueTimeout)
+	}
+	if c.ConcurrencyLongRunning < 1 {
+		return fmt.Errorf("CONCURRENCY_LONG_RUNNING_LIMIT must be positive, got: %d", c.ConcurrencyLongRunning)
+	}
+
+	if c.SSEHeartbeatInterval <= 0 {
+		return fmt.Errorf("SSE_HEARTBEAT_INTERVAL must be positive, got: %s", c.SSEHeartbeatInterval)
+	}
+	if c.SSEReplayWindow < 0 {
+		return fmt.Errorf("SSE_REPLAY_WINDOW cannot be negative, got: %s", c.SSEReplayWindow)
+	}
+
+	if c.WebSocketSendBuffer < 1 || c.WebSocketSendBuffer > 10000 {
+		return fmt.Errorf("WEBSOCKET_SEND_BUFFER must be between 1 and 10000, got: %d", c.WebSocketSendBuffer)
+	}
+	if c.WebSocketAuthTimeout <= 0 {
+		return fmt.Errorf("WEBSOCKET_AUTH_TIMEOUT must be positive, got: %s", c.WebSocketAuthTimeout)
+	}
+	if c.WebSocketPingInterval <= 0 {
+		return fmt.Errorf("WEBSOCKET_PING_INTERVAL must be positive, got: %s", c.WebSocketPingInterval)
+	}
+
+	switch c.OutboxPublisher {
+	case "log", "none":
+	case "webhook":
+		if c.OutboxWebhookURL == "" {
+			return fmt.Errorf("OUTBOX_WEBHOOK_URL must be set when OUTBOX_PUBLISHER is webhook")
+		}
+	case "file":
+		if c.OutboxFile == "" {
+			return fmt.Errorf("OUTBOX_FILE must be set when OUTBOX_PUBLISHER is file")
+		}
+	default:
+		return fmt.Errorf("OUTBOX_PUBLISHER must be one of log, webhook, file, none, got: %s", c.OutboxPublisher)
+	}
+	if c.OutboxWebhookTimeout <= 0 {
+		return fmt.Errorf("OUTBOX_WEBHOOK_TIMEOUT must be positive, got: %s", c.OutboxWebhookTimeout)
+	}
+	if c.OutboxPollInterval <= 0 {
+		return fmt.Errorf("OUTBOX_POLL_INTERVAL must be positive, got: %s", c.OutboxPollInterval)
+	}
+	if c.OutboxBatchSize < 1 || c.OutboxBatchSize > 1000 {
+		return fmt.Errorf("OUTBOX_BATCH_SIZE must be between 1 and 1000, got: %d", c.OutboxBatchSize)
+	}
+	if c.OutboxRetention < 0 {
+		return fmt.Errorf("OUTBOX_RETENTION cannot be negative, got: %s", c.OutboxRetention)
+	}
+
+	if c.WebhooksEnabled {
+		if c.WebhookTimeout <= 0 {
+			return fmt.Errorf("WEBHOOK_TIMEOUT must be positive, got: %s", c.WebhookTimeout)
+		}
+		if c.WebhookMaxAttempts < 1 || c.WebhookMaxAttempts > 100 {
+			return fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must be between 1 and 100, got: %d", c.WebhookMaxAttempts)
+		}
+		if c.WebhookPollInterval <= 0 {
+			return fmt.Errorf("WEBHOOK_POLL_INTERVAL must be positive, got: %s", c.WebhookPollInterval)
+		}
+	}
+
+	if c.SCIMEnabled {
+		if c.SCIMScope == "" {
+			return fmt.Errorf("SCIM_SCOPE cannot be empty")
+		}
+		if c.SCIMToken != "" && len(c.SCIMToken) < 32 {
+			return fmt.Errorf("SCIM_TOKEN must be at least 32 characters long")
+		}
+		if c.SCIMIssuer == "" {
+			return fmt.Errorf("SCIM_ISSUER must be set when SCIM_ENABLED is true and OIDC_ISSUER is empty")
+		}
+	}
+
 	return nil
 }
 
+// TLSEnabled reports whether the server serves HTTPS
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+// OIDCStaticKeys reports whether the signing keys are configured instead of discovered
+func (c *Config) OIDCStaticKeys() bool {
+	return c.OIDCJWKS != "" || c.OIDCJWKSFile != ""
+}
+
+// DevIssuerURL returns the issuer URL of the local dev issuer
+func (c *Config) DevIssuerURL() string {
+	return fmt.Sprintf("http://localhost:%s", c.DevIssuerPort)
+}
+
+// ClientCertsEnabled reports whether client certificates are verified
+func (c *Config) ClientCertsEnabled() bool {
+	return c.TLSEnabled() && c.TLSClientAuth != "none"
+}
+
 func getEnv(key, fallback string) string {
 	if v, ok := os.LookupEnv(key); ok {
 		return v
@@ -156,6 +674,76 @@ func getEnvSlice(key string, fallback []string) []string {
 	return fallback
 }
 
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if v, ok := os.LookupEnv(key); ok {
+		if parsed, err := time.ParseDuration(v); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+// getEnvMap parses entries separated by ";" in the form key=value1,value2
+// The last "=" separates key and values, so keys like distinguished names may contain "="
+func getEnvMap(key string) map[string][]string {
+	result := map[string][]string{}
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return result
+	}
+	for _, entry := range strings.Split(v, ";") {
+		idx := strings.LastIndex(entry, "=")
+		if idx <= 0 {
+			continue
+		}
+		name := strings.TrimSpace(entry[:idx])
+		values := []string{}
+		for _, value := range strings.Split(entry[idx+1:], ",") {
+			if trimmed := strings.TrimSpace(value); trimmed != "" {
+				values = append(values, trimmed)
+			}
+		}
+		result[name] = values
+	}
+	return result
+}
+
+// getEnvStringMap parses entries separated by ";" in the form key=value.
+// The first "=" separates key and value, so values like cron expressions may contain "," and spaces
+func getEnvStringMap(key string) map[string]string {
+	result := map[string]string{}
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return result
+	}
+	for _, entry := range strings.Split(v, ";") {
+		name, value, found := strings.Cut(entry, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			continue
+		}
+		result[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return result
+}
+
+// getEnvPolicyMap parses entries separated by "|" in the form key=value, values like
+// Content-Security-Policies contain ";". The first "=" separates key and value.
+func getEnvPolicyMap(key string) map[string]string {
+	result := map[string]string{}
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return result
+	}
+	for _, entry := range strings.Split(v, "|") {
+		name, value, found := strings.Cut(entry, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			continue
+		}
+		result[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return result
+}
+
 func getEnvLogLevel(key string, fallback slog.Level) slog.Level {
 	if v, ok := os.LookupEnv(key); ok && v != "" {
 		switch strings.ToUpper(strings.TrimSpace(v)) {
@@ -0,0 +1,218 @@
+// Package devissuer is a minimal OpenID Connect issuer for local development and integration tests.
+// It serves a discovery document and JWKS for an in-memory signing key and mints tokens on request,
+// so the real JWT validation path can be used without an identity provider like Keycloak.
+//
+// Never expose it outside of localhost: anybody who can reach it can mint valid tokens.
+package devissuer
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lestrrat-go/jwx/v3/jwa"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+)
+
+// DefaultTokenLifetime is used when TokenOptions.ExpiresIn is zero
+const DefaultTokenLifetime = time.Hour
+
+// Issuer signs tokens with a key that only lives as long as the process
+type Issuer struct {
+	issuer   string
+	audience string
+	key      jwk.Key
+	keySet   jwk.Set
+}
+
+// TokenOptions describes the token to mint
+type TokenOptions struct {
+	Subject    string         `json:"sub"`
+	Scope      string         `json:"scope,omitempty"`
+	Roles      []string       `json:"roles,omitempty"`
+	RolesClaim string         `json:"roles_claim,omitempty"` // defaults to "roles"
+	Audience   string         `json:"aud,omitempty"`         // defaults to the issuer's audience
+	ExpiresIn  time.Duration  `json:"-"`
+	Claims     map[string]any `json:"claims,omitempty"`
+}
+
+// New creates an issuer with a fresh RSA signing key
+// issuer: the public URL of the issuer, it must match OIDC_ISSUER
+// audience: the default audience of minted tokens
+func New(issuer, audience string) (*Issuer, error) {
+	raw, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	key, err := jwk.Import(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import signing key: %w", err)
+	}
+	if err := key.Set(jwk.KeyIDKey, uuid.NewString()); err != nil {
+		return nil, err
+	}
+	if err := key.Set(jwk.AlgorithmKey, jwa.RS256()); err != nil {
+		return nil, err
+	}
+
+	publicKey, err := jwk.PublicKeyOf(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive public key: %w", err)
+	}
+	keySet := jwk.NewSet()
+	if err := keySet.AddKey(publicKey); err != nil {
+		return nil, err
+	}
+
+	return &Issuer{
+		issuer:   strings.TrimSuffix(issuer, "/"),
+		audience: audience,
+		key:      key,
+		keySet:   keySet,
+	}, nil
+}
+
+// Mint signs a new token
+func (i *Issuer) Mint(opts TokenOptions) (string, error) {
+	if opts.Subject == "" {
+		return "", errors.New("subject cannot be empty")
+	}
+	if opts.ExpiresIn == 0 {
+		opts.ExpiresIn = DefaultTokenLifetime
+	}
+	if opts.RolesClaim == "" {
+		opts.RolesClaim = "roles"
+	}
+	if opts.Audience == "" {
+		opts.Audience = i.audience
+	}
+
+	now := time.Now()
+	builder := jwt.NewBuilder().
+		Issuer(i.issuer).
+		Subject(opts.Subject).
+		IssuedAt(now).
+		Expiration(now.Add(opts.ExpiresIn)).
+		JwtID(uuid.NewString())
+	if opts.Audience != "" {
+		builder = builder.Audience([]string{opts.Audience})
+	}
+	if opts.Scope != "" {
+		builder = builder.Claim("scope", opts.Scope)
+	}
+	if len(opts.Roles) > 0 {
+		builder = builder.Claim(opts.RolesClaim, opts.Roles)
+	}
+	for name, value := range opts.Claims {
+		builder = builder.Claim(name, value)
+	}
+
+	token, err := builder.Build()
+	if err != nil {
+		return "", fmt.Errorf("failed to build token: %w", err)
+	}
+
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.RS256(), i.key))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return string(signed), nil
+}
+
+// Handler serves the discovery document, the JWKS and the token endpoint
+func (i *Issuer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", i.handleDiscovery)
+	mux.HandleFunc("GET /jwks.json", i.handleJWKS)
+	mux.HandleFunc("POST /token", i.handleToken)
+	return mux
+}
+
+// ListenAndServe serves the issuer on addr until the context is canceled.
+// It returns once the listener is bound, so tokens can be validated right away.
+func (i *Issuer) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	server := &http.Server{
+		Handler:           i.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Dev issuer stopped", "error", err)
+		}
+	}()
+	return nil
+}
+
+func (i *Issuer) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                i.issuer,
+		"jwks_uri":                              i.issuer + "/jwks.json",
+		"token_endpoint":                        i.issuer + "/token",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"subject_types_supported":               []string{"public"},
+		"response_types_supported":              []string{"token"},
+	})
+}
+
+func (i *Issuer) handleJWKS(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, i.keySet)
+}
+
+func (i *Issuer) handleToken(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		TokenOptions
+		ExpiresIn string `json:"exp_in,omitempty"` // duration like "15m"
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	opts := request.TokenOptions
+	if request.ExpiresIn != "" {
+		expiresIn, err := time.ParseDuration(request.ExpiresIn)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid exp_in"})
+			return
+		}
+		opts.ExpiresIn = expiresIn
+	}
+
+	token, err := i.Mint(opts)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   int(opts.ExpiresIn.Seconds()),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"com.tom-ludwig/go-server-template/internal/api/apikeys"
+	"com.tom-ludwig/go-server-template/internal/audit"
+	"com.tom-ludwig/go-server-template/internal/middleware"
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+// compile-time check
+var _ apikeys.StrictServerInterface = (*APIKeyHandler)(nil)
+
+type APIKeyHandler struct {
+	// DB is used to record audit events in the transaction of the mutation
+	DB      *pgxpool.Pool
+	Queries *repository.Queries
+}
+
+func NewAPIKeyHandler(db *pgxpool.Pool, queries *repository.Queries) *APIKeyHandler {
+	return &APIKeyHandler{
+		DB:      db,
+		Queries: queries,
+	}
+}
+
+func (a *APIKeyHandler) ListApiKeys(ctx context.Context, _ apikeys.ListApiKeysRequestObject) (apikeys.ListApiKeysResponseObject, error) {
+	dbKeys, err := a.Queries.ListAPIKeys(ctx)
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to list api keys",
+			"error", err,
+		)
+		return apikeys.ListApiKeys500JSONResponse{
+			InternalServerErrorJSONResponse: apikeys.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	apiKeys := []apikeys.ApiKey{}
+	for _, dbKey := range dbKeys {
+		apiKeys = append(apiKeys, toAPIKey(dbKey))
+	}
+
+	return apikeys.ListApiKeys200JSONResponse{
+		Data: apiKeys,
+	}, nil
+}
+
+func (a *APIKeyHandler) CreateApiKey(ctx context.Context, request apikeys.CreateApiKeyRequestObject) (apikeys.CreateApiKeyResponseObject, error) {
+	name := strings.TrimSpace(request.Body.Name)
+	if name == "" {
+		return apikeys.CreateApiKey400JSONResponse{
+			BadRequestJSONResponse: apikeys.BadRequestJSONResponse{
+				Message: "name must not be empty",
+			},
+		}, nil
+	}
+
+	// Default the owner to the caller
+	owner, _ := middleware.GetSubject(ctx)
+	if request.Body.Owner != nil {
+		owner = strings.TrimSpace(*request.Body.Owner)
+	}
+	if owner == "" {
+		return apikeys.CreateApiKey400JSONResponse{
+			BadRequestJSONResponse: apikeys.BadRequestJSONResponse{
+				Message: "owner must be set when the caller is not authenticated",
+			},
+		}, nil
+	}
+
+	var expiresAt pgtype.Timestamptz
+	if request.Body.ExpiresAt != nil {
+		if !request.Body.ExpiresAt.After(time.Now()) {
+			return apikeys.CreateApiKey400JSONResponse{
+				BadRequestJSONResponse: apikeys.BadRequestJSONResponse{
+					Message: "expires_at must be in the future",
+				},
+			}, nil
+		}
+		expiresAt = pgtype.Timestamptz{Time: *request.Body.ExpiresAt, Valid: true}
+	}
+
+	key, prefix, hash, err := middleware.GenerateAPIKey()
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to generate an api key",
+			"error", err,
+		)
+		return apikeys.CreateApiKey500JSONResponse{
+			InternalServerErrorJSONResponse: apikeys.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	scopes := request.Body.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	var dbKey repository.ApiKey
+	err = audit.Mutate(ctx, a.DB, func(q *repository.Queries) (audit.Event, error) {
+		var err error
+		dbKey, err = q.CreateAPIKey(ctx, repository.CreateAPIKeyParams{
+			Name:      name,
+			Prefix:    prefix,
+			KeyHash:   hash,
+			Owner:     owner,
+			Scopes:    scopes,
+			ExpiresAt: expiresAt,
+		})
+		return apiKeyEvent("api_key.create", nil, &dbKey), err
+	})
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to create an api key",
+			"error", err,
+		)
+		return apikeys.CreateApiKey500JSONResponse{
+			InternalServerErrorJSONResponse: apikeys.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	slog.Info("api key created", "key_id", dbKey.KeyID, "owner", dbKey.Owner)
+
+	apiKey := toAPIKey(dbKey)
+	return apikeys.CreateApiKey201JSONResponse{
+		Key:        key,
+		KeyId:      apiKey.KeyId,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Owner:      apiKey.Owner,
+		Scopes:     apiKey.Scopes,
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}, nil
+}
+
+func (a *APIKeyHandler) RevokeApiKey(ctx context.Context, request apikeys.RevokeApiKeyRequestObject) (apikeys.RevokeApiKeyResponseObject, error) {
+	var dbKey repository.ApiKey
+	err := audit.Mutate(ctx, a.DB, func(q *repository.Queries) (audit.Event, error) {
+		var err error
+		dbKey, err = q.RevokeAPIKey(ctx, request.KeyId)
+		// Only keys that were not revoked are revoked, nothing else changes
+		before := dbKey
+		before.RevokedAt = pgtype.Timestamptz{}
+		return apiKeyEvent("api_key.revoke", &before, &dbKey), err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apikeys.RevokeApiKey404JSONResponse{
+				NotFoundJSONResponse: apikeys.NotFoundJSONResponse{
+					Message: "api key not found or already revoked",
+				},
+			}, nil
+		}
+
+		slog.Error(
+			"An error occurred while trying to revoke an api key",
+			"error", err,
+		)
+		return apikeys.RevokeApiKey500JSONResponse{
+			InternalServerErrorJSONResponse: apikeys.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	slog.Info("api key revoked", "key_id", dbKey.KeyID, "owner", dbKey.Owner)
+
+	return apikeys.RevokeApiKey204Response{}, nil
+}
+
+// toAPIKey converts a database api key to the API representation, leaving out the hash
+func toAPIKey(dbKey repository.ApiKey) apikeys.ApiKey {
+	return apikeys.ApiKey{
+		KeyId:      dbKey.KeyID,
+		Name:       dbKey.Name,
+		Prefix:     dbKey.Prefix,
+		Owner:      dbKey.Owner,
+		Scopes:     dbKey.Scopes,
+		ExpiresAt:  timestamptzPtr(dbKey.ExpiresAt),
+		LastUsedAt: timestamptzPtr(dbKey.LastUsedAt),
+		RevokedAt:  timestamptzPtr(dbKey.RevokedAt),
+		CreatedAt:  dbKey.CreatedAt,
+	}
+}
+
+// apiKeyEvent builds the audit event of an api key mutation, the hash is never recorded
+func apiKeyEvent(action string, before, after *repository.ApiKey) audit.Event {
+	event := audit.Event{Action: action, ResourceType: "api_key"}
+	if before != nil {
+		event.ResourceID = before.KeyID.String()
+		event.Before = toAPIKey(*before)
+	}
+	if after != nil {
+		event.ResourceID = after.KeyID.String()
+		event.After = toAPIKey(*after)
+	}
+	return event
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"com.tom-ludwig/go-server-template/internal/api/audit"
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+// compile-time check
+var _ audit.StrictServerInterface = (*AuditHandler)(nil)
+
+type AuditHandler struct {
+	Queries *repository.Queries
+}
+
+func NewAuditHandler(queries *repository.Queries) *AuditHandler {
+	return &AuditHandler{
+		Queries: queries,
+	}
+}
+
+func (h *AuditHandler) ListAuditEvents(ctx context.Context, request audit.ListAuditEventsRequestObject) (audit.ListAuditEventsResponseObject, error) {
+	limit := 20
+	if request.Params.Limit != nil {
+		limit = *request.Params.Limit
+	}
+
+	var cursor pgtype.UUID
+	if request.Params.Cursor != nil {
+		eventID, err := uuid.Parse(*request.Params.Cursor)
+		if err != nil {
+			return audit.ListAuditEvents400JSONResponse{
+				BadRequestJSONResponse: audit.BadRequestJSONResponse{
+					Message: "invalid cursor",
+				},
+			}, nil
+		}
+		cursor = pgtype.UUID{Bytes: eventID, Valid: true}
+	}
+
+	// Fetch one more event to know whether there is a next page
+	dbEvents, err := h.Queries.ListAuditEvents(ctx, repository.ListAuditEventsParams{
+		Actor:        optionalText(request.Params.Actor),
+		ResourceType: optionalText(request.Params.ResourceType),
+		ResourceID:   optionalText(request.Params.ResourceId),
+		Since:        optionalTimestamptz(request.Params.Since),
+		Until:        optionalTimestamptz(request.Params.Until),
+		Cursor:       cursor,
+		MaxResults:   int32(limit + 1),
+	})
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to list audit events",
+			"error", err,
+		)
+		return audit.ListAuditEvents500JSONResponse{
+			InternalServerErrorJSONResponse: audit.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	var nextCursor *string
+	if len(dbEvents) > limit {
+		dbEvents = dbEvents[:limit]
+		nextCursor = ptr(dbEvents[limit-1].EventID.String())
+	}
+
+	events := []audit.AuditEvent{}
+	for _, dbEvent := range dbEvents {
+		event, err := toAuditEvent(dbEvent)
+		if err != nil {
+			slog.Error(
+				"An error occurred while trying to decode an audit event",
+				"event_id", dbEvent.EventID,
+				"error", err,
+			)
+			return audit.ListAuditEvents500JSONResponse{
+				InternalServerErrorJSONResponse: audit.InternalServerErrorJSONResponse{
+					Message: "An internal server error occurred",
+				},
+			}, nil
+		}
+		events = append(events, event)
+	}
+
+	return audit.ListAuditEvents200JSONResponse{
+		Data:       events,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func toAuditEvent(dbEvent repository.AuditEvent) (audit.AuditEvent, error) {
+	event := audit.AuditEvent{
+		EventId:      dbEvent.EventID,
+		Actor:        dbEvent.Actor,
+		ActorType:    audit.AuditEventActorType(dbEvent.ActorType),
+		Action:       dbEvent.Action,
+		ResourceType: dbEvent.ResourceType,
+		ResourceId:   dbEvent.ResourceID,
+		RequestId:    dbEvent.RequestID,
+		Ip:           dbEvent.Ip,
+		CreatedAt:    dbEvent.CreatedAt,
+	}
+	if dbEvent.Before != nil {
+		if err := json.Unmarshal(dbEvent.Before, &event.Before); err != nil {
+			return audit.AuditEvent{}, err
+		}
+	}
+	if dbEvent.After != nil {
+		if err := json.Unmarshal(dbEvent.After, &event.After); err != nil {
+			return audit.AuditEvent{}, err
+		}
+	}
+	return event, nil
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// CSPReportPath receives the violation reports of the Content-Security-Policy
+const CSPReportPath = "/csp-reports"
+
+// cspReportMaxSize bounds the body, browsers send one violation per report
+const cspReportMaxSize = 64 << 10
+
+// cspReportMaxFieldLength bounds each logged field, reports are public and their URLs long
+const cspReportMaxFieldLength = 256
+
+// cspReport is a report sent for report-uri as application/csp-report
+type cspReport struct {
+	Report struct {
+		DocumentURI        string `json:"document-uri"`
+		BlockedURI         string `json:"blocked-uri"`
+		ViolatedDirective  string `json:"violated-directive"`
+		EffectiveDirective string `json:"effective-directive"`
+		Disposition        string `json:"disposition"`
+		SourceFile         string `json:"source-file"`
+		LineNumber         int    `json:"line-number"`
+	} `json:"csp-report"`
+}
+
+// reportingAPIReport is a report of the Reporting API, sent as application/reports+json
+type reportingAPIReport struct {
+	Type string `json:"type"`
+	Body struct {
+		DocumentURL        string `json:"documentURL"`
+		BlockedURL         string `json:"blockedURL"`
+		EffectiveDirective string `json:"effectiveDirective"`
+		Disposition        string `json:"disposition"`
+		SourceFile         string `json:"sourceFile"`
+		LineNumber         int    `json:"lineNumber"`
+	} `json:"body"`
+}
+
+// CSPReportHandler logs the Content-Security-Policy violations reported by browsers
+type CSPReportHandler struct{}
+
+func NewCSPReportHandler() *CSPReportHandler {
+	return &CSPReportHandler{}
+}
+
+func (h *CSPReportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, cspReportMaxSize+1))
+	if err != nil || len(body) > cspReportMaxSize {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/reports+json":
+		var reports []reportingAPIReport
+		if err := json.Unmarshal(body, &reports); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, report := range reports {
+			if report.Type != "csp-violation" {
+				continue
+			}
+			slog.Info("Content-Security-Policy violation",
+				"document", truncateReportField(report.Body.DocumentURL),
+				"blocked", truncateReportField(report.Body.BlockedURL),
+				"directive", truncateReportField(report.Body.EffectiveDirective),
+				"disposition", truncateReportField(report.Body.Disposition),
+				"source", truncateReportField(report.Body.SourceFile),
+				"line", report.Body.LineNumber,
+				"user_agent", truncateReportField(r.UserAgent()),
+			)
+		}
+	case "application/csp-report", "application/json":
+		var report cspReport
+		if err := json.Unmarshal(body, &report); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		directive := report.Report.EffectiveDirective
+		if directive == "" {
+			directive = report.Report.ViolatedDirective
+		}
+		slog.Info("Content-Security-Policy violation",
+			"document", truncateReportField(report.Report.DocumentURI),
+			"blocked", truncateReportField(report.Report.BlockedURI),
+			"directive", truncateReportField(directive),
+			"disposition", truncateReportField(report.Report.Disposition),
+			"source", truncateReportField(report.Report.SourceFile),
+			"line", report.Report.LineNumber,
+			"user_agent", truncateReportField(r.UserAgent()),
+		)
+	default:
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// truncateReportField shortens s to cspReportMaxFieldLength bytes without splitting a character
+func truncateReportField(s string) string {
+	if len(s) <= cspReportMaxFieldLength {
+		return s
+	}
+	return strings.ToValidUTF8(s[:cspReportMaxFieldLength], "") + "..."
+}
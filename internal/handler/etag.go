@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"com.tom-ludwig/go-server-template/internal/middleware"
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+// userETag returns the strong entity tag of the current version of a user
+func userETag(user repository.User) string {
+	return fmt.Sprintf(`"%d"`, user.Version)
+}
+
+// etagListMatches reports whether an If-Match or If-None-Match header is * or lists the etag.
+// If-Match uses the strong comparison, where weak tags never match (RFC 9110 section 8.8.3.2).
+// The ETags of compressed responses carry the encoding as suffix and match the same version.
+func etagListMatches(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if middleware.UncompressedETag(candidate) == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package handler
+
+import "testing"
+
+func TestETagListMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		weak   bool
+		want   bool
+	}{
+		{"equal", `"3"`, false, true},
+		{"other version", `"2"`, false, false},
+		{"list", `"1", "3"`, false, true},
+		{"wildcard", `*`, false, true},
+		{"weak in strong comparison", `W/"3"`, false, false},
+		{"weak in weak comparison", `W/"3"`, true, true},
+		{"compressed", `"3-gzip"`, false, true},
+		{"compressed other version", `"2-zstd"`, false, false},
+		{"compressed weak", `W/"3-br"`, true, true},
+		{"unknown suffix", `"3-deflate"`, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := etagListMatches(tt.header, `"3"`, tt.weak); got != tt.want {
+				t.Errorf("etagListMatches(%q, %q, %v) = %v, want %v", tt.header, `"3"`, tt.weak, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package handler
 
 import (
 	"context"
+	"fmt"
 
 	"com.tom-ludwig/go-server-template/internal/api/health"
+	"com.tom-ludwig/go-server-template/internal/middleware"
 	"com.tom-ludwig/go-server-template/internal/repository"
 )
 
@@ -12,11 +14,14 @@ var _ health.StrictServerInterface = (*HealthHandler)(nil)
 
 type HealthHandler struct {
 	queries *repository.Queries
+	jwtAuth *middleware.JWTAuth
 }
 
-func NewHealthHandler(queries *repository.Queries) *HealthHandler {
+// NewHealthHandler creates the health handler, jwtAuth may be nil if OIDC is disabled
+func NewHealthHandler(queries *repository.Queries, jwtAuth *middleware.JWTAuth) *HealthHandler {
 	return &HealthHandler{
 		queries: queries,
+		jwtAuth: jwtAuth,
 	}
 }
 
@@ -35,15 +40,76 @@ func (s *HealthHandler) GetLivez(_ context.Context, _ health.GetLivezRequestObje
 
 // GetReadyz implements health.StrictServerInterface.
 func (s *HealthHandler) GetReadyz(ctx context.Context, _ health.GetReadyzRequestObject) (health.GetReadyzResponseObject, error) {
-	_, err := s.queries.Ping(ctx)
-	if err != nil {
+	checks := []health.ReadinessCheck{s.checkDatabase(ctx)}
+	if s.jwtAuth != nil {
+		checks = append(checks, s.checkSigningKeys())
+	}
+
+	successful, failed := []string{}, []string{}
+	status := "OK"
+	for _, check := range checks {
+		switch check.Status {
+		case health.Failed:
+			failed = append(failed, checkSummary(check))
+		case health.Degraded:
+			status = "DEGRADED"
+			successful = append(successful, check.Name)
+		default:
+			successful = append(successful, check.Name)
+		}
+	}
+
+	if len(failed) > 0 {
 		return health.GetReadyz503JSONResponse{
-			FailedChecks:      []string{"Database not reachable."},
-			SuccessfullChecks: []string{},
+			FailedChecks:      failed,
+			SuccessfullChecks: successful,
+			Checks:            &checks,
 		}, nil
 	}
 
 	return health.GetReadyz200JSONResponse{
-		Status: "OK",
+		Status: status,
+		Checks: checks,
 	}, nil
 }
+
+func (s *HealthHandler) checkDatabase(ctx context.Context) health.ReadinessCheck {
+	if _, err := s.queries.Ping(ctx); err != nil {
+		return health.ReadinessCheck{
+			Name:    "database",
+			Status:  health.Failed,
+			Message: ptr("Database not reachable."),
+		}
+	}
+	return health.ReadinessCheck{Name: "database", Status: health.Ok}
+}
+
+// checkSigningKeys reports the JWKS refresh status. Stale keys are degraded, no keys at all fail the probe.
+func (s *HealthHandler) checkSigningKeys() health.ReadinessCheck {
+	keyStatus := s.jwtAuth.KeyStatus()
+	check := health.ReadinessCheck{
+		Name:    "signing_keys",
+		Status:  health.Ok,
+		Message: ptr("keys from " + keyStatus.Source),
+	}
+	if !keyStatus.LastRefresh.IsZero() {
+		check.LastRefresh = &keyStatus.LastRefresh
+	}
+
+	switch {
+	case !keyStatus.Ready():
+		check.Status = health.Failed
+		check.Message = ptr("No signing keys available: " + keyStatus.LastError)
+	case keyStatus.Degraded:
+		check.Status = health.Degraded
+		check.Message = ptr(fmt.Sprintf("Issuer unreachable, using keys from %s: %s", keyStatus.Source, keyStatus.LastError))
+	}
+	return check
+}
+
+func checkSummary(check health.ReadinessCheck) string {
+	if check.Message == nil {
+		return check.Name
+	}
+	return *check.Message
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+
+	metricsapi "com.tom-ludwig/go-server-template/internal/api/metrics"
+)
+
+// compile-time check
+var _ metricsapi.StrictServerInterface = (*MetricsHandler)(nil)
+
+type MetricsHandler struct{}
+
+func NewMetricsHandler() *MetricsHandler {
+	return &MetricsHandler{}
+}
+
+func (h *MetricsHandler) GetMetrics(_ context.Context, _ metricsapi.GetMetricsRequestObject) (metricsapi.GetMetricsResponseObject, error) {
+	metrics := metricsapi.GetMetrics200JSONResponse{}
+	// expvar variables render themselves as JSON
+	expvar.Do(func(kv expvar.KeyValue) {
+		metrics[kv.Key] = json.RawMessage(kv.Value.String())
+	})
+	return metrics, nil
+}
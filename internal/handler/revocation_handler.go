@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"com.tom-ludwig/go-server-template/internal/api/revocations"
+	"com.tom-ludwig/go-server-template/internal/audit"
+	"com.tom-ludwig/go-server-template/internal/repository"
+	"com.tom-ludwig/go-server-template/internal/revocation"
+)
+
+// defaultRevokedTokenTTL is used when the caller doesn't know the exp of the revoked token
+const defaultRevokedTokenTTL = 24 * time.Hour
+
+// compile-time check
+var _ revocations.StrictServerInterface = (*RevocationHandler)(nil)
+
+type RevocationHandler struct {
+	// DB is used to record audit events in the transaction of the mutation
+	DB      *pgxpool.Pool
+	Queries *repository.Queries
+	// issuer is used for subjects revoked without an issuer
+	issuer string
+}
+
+func NewRevocationHandler(db *pgxpool.Pool, queries *repository.Queries, issuer string) *RevocationHandler {
+	return &RevocationHandler{
+		DB:      db,
+		Queries: queries,
+		issuer:  issuer,
+	}
+}
+
+func (h *RevocationHandler) ListRevokedTokens(ctx context.Context, _ revocations.ListRevokedTokensRequestObject) (revocations.ListRevokedTokensResponseObject, error) {
+	dbTokens, err := h.Queries.ListRevokedTokens(ctx)
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to list revoked tokens",
+			"error", err,
+		)
+		return revocations.ListRevokedTokens500JSONResponse{
+			InternalServerErrorJSONResponse: revocations.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	tokens := []revocations.RevokedToken{}
+	for _, dbToken := range dbTokens {
+		tokens = append(tokens, toRevokedToken(dbToken))
+	}
+
+	return revocations.ListRevokedTokens200JSONResponse{
+		Data: tokens,
+	}, nil
+}
+
+func (h *RevocationHandler) RevokeToken(ctx context.Context, request revocations.RevokeTokenRequestObject) (revocations.RevokeTokenResponseObject, error) {
+	expiresAt := time.Now().Add(defaultRevokedTokenTTL)
+	if request.Body.ExpiresAt != nil {
+		expiresAt = *request.Body.ExpiresAt
+	}
+	if !expiresAt.After(time.Now()) {
+		return revocations.RevokeToken400JSONResponse{
+			BadRequestJSONResponse: revocations.BadRequestJSONResponse{
+				Message: "expires_at must be in the future, expired tokens are rejected anyway",
+			},
+		}, nil
+	}
+
+	var dbToken repository.RevokedToken
+	err := audit.Mutate(ctx, h.DB, func(q *repository.Queries) (audit.Event, error) {
+		event := audit.Event{Action: "token.revoke", ResourceType: "token", ResourceID: request.Body.Jti}
+		previous, err := q.GetRevokedToken(ctx, request.Body.Jti)
+		if err == nil {
+			event.Before = toRevokedToken(previous)
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return audit.Event{}, err
+		}
+
+		dbToken, err = q.RevokeToken(ctx, repository.RevokeTokenParams{
+			Jti:       request.Body.Jti,
+			ExpiresAt: expiresAt,
+			Reason:    optionalText(request.Body.Reason),
+		})
+		event.After = toRevokedToken(dbToken)
+		return event, err
+	})
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to revoke a token",
+			"error", err,
+		)
+		return revocations.RevokeToken500JSONResponse{
+			InternalServerErrorJSONResponse: revocations.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	h.notify(ctx, revocation.TokenPayload(dbToken.Jti))
+	slog.Info("token revoked", "jti", dbToken.Jti)
+
+	return revocations.RevokeToken201JSONResponse(toRevokedToken(dbToken)), nil
+}
+
+func (h *RevocationHandler) ListRevokedSubjects(ctx context.Context, _ revocations.ListRevokedSubjectsRequestObject) (revocations.ListRevokedSubjectsResponseObject, error) {
+	dbSubjects, err := h.Queries.ListRevokedSubjects(ctx)
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to list revoked subjects",
+			"error", err,
+		)
+		return revocations.ListRevokedSubjects500JSONResponse{
+			InternalServerErrorJSONResponse: revocations.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	subjects := []revocations.RevokedSubject{}
+	for _, dbSubject := range dbSubjects {
+		subjects = append(subjects, toRevokedSubject(dbSubject))
+	}
+
+	return revocations.ListRevokedSubjects200JSONResponse{
+		Data: subjects,
+	}, nil
+}
+
+func (h *RevocationHandler) RevokeSubject(ctx context.Context, request revocations.RevokeSubjectRequestObject) (revocations.RevokeSubjectResponseObject, error) {
+	revokedBefore := time.Now()
+	if request.Body.RevokedBefore != nil {
+		revokedBefore = *request.Body.RevokedBefore
+	}
+	issuer := h.issuer
+	if request.Body.Issuer != nil {
+		issuer = *request.Body.Issuer
+	}
+	if issuer == "" {
+		return revocations.RevokeSubject400JSONResponse{
+			BadRequestJSONResponse: revocations.BadRequestJSONResponse{
+				Message: "issuer is required, no default issuer is configured",
+			},
+		}, nil
+	}
+
+	var dbSubject repository.RevokedSubject
+	err := audit.Mutate(ctx, h.DB, func(q *repository.Queries) (audit.Event, error) {
+		event := audit.Event{Action: "subject.revoke", ResourceType: "subject", ResourceID: revocation.SubjectID(issuer, request.Body.Subject)}
+		previous, err := q.GetRevokedSubject(ctx, repository.GetRevokedSubjectParams{Issuer: issuer, Subject: request.Body.Subject})
+		if err == nil {
+			event.Before = toRevokedSubject(previous)
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return audit.Event{}, err
+		}
+
+		dbSubject, err = q.RevokeSubject(ctx, repository.RevokeSubjectParams{
+			Issuer:        issuer,
+			Subject:       request.Body.Subject,
+			RevokedBefore: revokedBefore,
+			Reason:        optionalText(request.Body.Reason),
+		})
+		event.After = toRevokedSubject(dbSubject)
+		return event, err
+	})
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to revoke a subject",
+			"error", err,
+		)
+		return revocations.RevokeSubject500JSONResponse{
+			InternalServerErrorJSONResponse: revocations.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	h.notify(ctx, revocation.SubjectPayload(dbSubject.Issuer, dbSubject.Subject))
+	slog.Info("subject revoked", "issuer", dbSubject.Issuer, "subject", dbSubject.Subject, "revoked_before", dbSubject.RevokedBefore)
+
+	return revocations.RevokeSubject201JSONResponse(toRevokedSubject(dbSubject)), nil
+}
+
+func (h *RevocationHandler) DeleteRevokedSubject(ctx context.Context, request revocations.DeleteRevokedSubjectRequestObject) (revocations.DeleteRevokedSubjectResponseObject, error) {
+	issuer := h.issuer
+	if request.Params.Issuer != nil {
+		issuer = *request.Params.Issuer
+	}
+
+	err := audit.Mutate(ctx, h.DB, func(q *repository.Queries) (audit.Event, error) {
+		deleted, err := q.DeleteRevokedSubject(ctx, repository.DeleteRevokedSubjectParams{Issuer: issuer, Subject: request.Subject})
+		return audit.Event{
+			Action:       "subject.unrevoke",
+			ResourceType: "subject",
+			ResourceID:   revocation.SubjectID(issuer, request.Subject),
+			Before:       toRevokedSubject(deleted),
+		}, err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return revocations.DeleteRevokedSubject404JSONResponse{
+				NotFoundJSONResponse: revocations.NotFoundJSONResponse{
+					Message: "subject is not revoked",
+				},
+			}, nil
+		}
+
+		slog.Error(
+			"An error occurred while trying to delete a subject revocation",
+			"error", err,
+		)
+		return revocations.DeleteRevokedSubject500JSONResponse{
+			InternalServerErrorJSONResponse: revocations.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	h.notify(ctx, revocation.SubjectPayload(issuer, request.Subject))
+
+	return revocations.DeleteRevokedSubject204Response{}, nil
+}
+
+// notify tells all replicas to refresh the entry. A lost notification is repaired by the periodic reload.
+func (h *RevocationHandler) notify(ctx context.Context, payload string) {
+	err := h.Queries.Notify(ctx, repository.NotifyParams{
+		Channel: revocation.Channel,
+		Payload: payload,
+	})
+	if err != nil {
+		slog.Warn("Failed to notify replicas about revocation", "payload", payload, "error", err)
+	}
+}
+
+// revokeUser revokes the tokens issued to the user so far, in the transaction of q.
+// The replicas are notified when the transaction commits, so a rolled back change revokes nothing.
+func revokeUser(ctx context.Context, q *repository.Queries, dbUser repository.User, reason string) error {
+	if !dbUser.Issuer.Valid || !dbUser.ExternalSubject.Valid {
+		// Not provisioned from a token, nothing to revoke
+		return nil
+	}
+	_, err := q.RevokeSubject(ctx, repository.RevokeSubjectParams{
+		Issuer:        dbUser.Issuer.String,
+		Subject:       dbUser.ExternalSubject.String,
+		RevokedBefore: time.Now(),
+		Reason:        pgtype.Text{String: reason, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	return q.Notify(ctx, repository.NotifyParams{
+		Channel: revocation.Channel,
+		Payload: revocation.SubjectPayload(dbUser.Issuer.String, dbUser.ExternalSubject.String),
+	})
+}
+
+// revokesSessions reports whether a status change ends the sessions of the user
+func revokesSessions(before, after repository.User) bool {
+	return after.Status != "active" && after.Status != before.Status
+}
+
+func toRevokedToken(dbToken repository.RevokedToken) revocations.RevokedToken {
+	return revocations.RevokedToken{
+		Jti:       dbToken.Jti,
+		ExpiresAt: dbToken.ExpiresAt,
+		Reason:    textPtr(dbToken.Reason),
+		CreatedAt: dbToken.CreatedAt,
+	}
+}
+
+func toRevokedSubject(dbSubject repository.RevokedSubject) revocations.RevokedSubject {
+	return revocations.RevokedSubject{
+		Issuer:        dbSubject.Issuer,
+		Subject:       dbSubject.Subject,
+		RevokedBefore: dbSubject.RevokedBefore,
+		Reason:        textPtr(dbSubject.Reason),
+		CreatedAt:     dbSubject.CreatedAt,
+	}
+}
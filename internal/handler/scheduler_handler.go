@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	schedulerapi "com.tom-ludwig/go-server-template/internal/api/scheduler"
+	"com.tom-ludwig/go-server-template/internal/repository"
+	"com.tom-ludwig/go-server-template/internal/scheduler"
+)
+
+// compile-time check
+var _ schedulerapi.StrictServerInterface = (*SchedulerHandler)(nil)
+
+type SchedulerHandler struct {
+	Queries   *repository.Queries
+	Scheduler *scheduler.Scheduler
+}
+
+func NewSchedulerHandler(queries *repository.Queries, scheduler *scheduler.Scheduler) *SchedulerHandler {
+	return &SchedulerHandler{
+		Queries:   queries,
+		Scheduler: scheduler,
+	}
+}
+
+func (h *SchedulerHandler) ListScheduledTasks(ctx context.Context, _ schedulerapi.ListScheduledTasksRequestObject) (schedulerapi.ListScheduledTasksResponseObject, error) {
+	dbRuns, err := h.Queries.GetLatestScheduledTaskRuns(ctx)
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to get the latest scheduled task runs",
+			"error", err,
+		)
+		return schedulerapi.ListScheduledTasks500JSONResponse{
+			InternalServerErrorJSONResponse: schedulerapi.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+	lastRuns := map[string]repository.ScheduledTaskRun{}
+	for _, dbRun := range dbRuns {
+		lastRuns[dbRun.Task] = dbRun
+	}
+
+	tasks := []schedulerapi.ScheduledTask{}
+	for _, info := range h.Scheduler.Tasks() {
+		task := schedulerapi.ScheduledTask{
+			Name:     info.Name,
+			Schedule: info.Spec,
+		}
+		if !info.NextRunAt.IsZero() {
+			task.NextRunAt = &info.NextRunAt
+		}
+		if dbRun, ok := lastRuns[info.Name]; ok {
+			task.LastRun = ptr(toScheduledTaskRun(dbRun))
+		}
+		tasks = append(tasks, task)
+	}
+
+	return schedulerapi.ListScheduledTasks200JSONResponse{
+		Data:   tasks,
+		Leader: h.Scheduler.IsLeader(),
+	}, nil
+}
+
+func (h *SchedulerHandler) ListScheduledTaskRuns(ctx context.Context, request schedulerapi.ListScheduledTaskRunsRequestObject) (schedulerapi.ListScheduledTaskRunsResponseObject, error) {
+	limit := 20
+	if request.Params.Limit != nil {
+		limit = *request.Params.Limit
+	}
+
+	var cursor pgtype.UUID
+	if request.Params.Cursor != nil {
+		runID, err := uuid.Parse(*request.Params.Cursor)
+		if err != nil {
+			return schedulerapi.ListScheduledTaskRuns400JSONResponse{
+				BadRequestJSONResponse: schedulerapi.BadRequestJSONResponse{
+					Message: "invalid cursor",
+				},
+			}, nil
+		}
+		cursor = pgtype.UUID{Bytes: runID, Valid: true}
+	}
+
+	// Fetch one more run to know whether there is a next page
+	dbRuns, err := h.Queries.ListScheduledTaskRuns(ctx, repository.ListScheduledTaskRunsParams{
+		Task:       optionalText(request.Params.Task),
+		Cursor:     cursor,
+		MaxResults: int32(limit + 1),
+	})
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to list scheduled task runs",
+			"error", err,
+		)
+		return schedulerapi.ListScheduledTaskRuns500JSONResponse{
+			InternalServerErrorJSONResponse: schedulerapi.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	var nextCursor *string
+	if len(dbRuns) > limit {
+		dbRuns = dbRuns[:limit]
+		nextCursor = ptr(dbRuns[limit-1].RunID.String())
+	}
+
+	runs := []schedulerapi.ScheduledTaskRun{}
+	for _, dbRun := range dbRuns {
+		runs = append(runs, toScheduledTaskRun(dbRun))
+	}
+
+	return schedulerapi.ListScheduledTaskRuns200JSONResponse{
+		Data:       runs,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func toScheduledTaskRun(dbRun repository.ScheduledTaskRun) schedulerapi.ScheduledTaskRun {
+	status := schedulerapi.Succeeded
+	switch {
+	case !dbRun.FinishedAt.Valid:
+		status = schedulerapi.Running
+	case dbRun.Error.Valid:
+		status = schedulerapi.Failed
+	}
+	return schedulerapi.ScheduledTaskRun{
+		RunId:      dbRun.RunID,
+		Task:       dbRun.Task,
+		Replica:    dbRun.Replica,
+		Status:     status,
+		StartedAt:  dbRun.StartedAt,
+		FinishedAt: timestamptzPtr(dbRun.FinishedAt),
+		Error:      textPtr(dbRun.Error),
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// scimUserFilter holds the LIKE patterns of a parsed SCIM filter, unset fields match everything
+type scimUserFilter struct {
+	UserID          pgtype.UUID
+	Email           pgtype.Text
+	ExternalSubject pgtype.Text
+	FirstName       pgtype.Text
+	LastName        pgtype.Text
+}
+
+// parseSCIMFilter parses the subset of the SCIM filter grammar (RFC 7644 section 3.4.2.2) IdPs use:
+// comparisons with eq, sw, co and pr, combined with and. Each attribute can only be filtered once.
+func parseSCIMFilter(filter string) (scimUserFilter, error) {
+	var result scimUserFilter
+	if strings.TrimSpace(filter) == "" {
+		return result, nil
+	}
+
+	tokens, err := tokenizeSCIMFilter(filter)
+	if err != nil {
+		return result, err
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i < len(tokens); {
+		if i > 0 {
+			if !strings.EqualFold(tokens[i], "and") {
+				return result, fmt.Errorf("unsupported logical operator %q, only and is supported", tokens[i])
+			}
+			i++
+		}
+		if i+1 >= len(tokens) {
+			return result, fmt.Errorf("incomplete filter expression")
+		}
+
+		attr := scimAttribute(tokens[i])
+		op := strings.ToLower(tokens[i+1])
+		i += 2
+
+		var value string
+		if op != "pr" {
+			if i >= len(tokens) || !strings.HasPrefix(tokens[i], `"`) {
+				return result, fmt.Errorf("operator %s requires a string value", op)
+			}
+			if err := json.Unmarshal([]byte(tokens[i]), &value); err != nil {
+				return result, fmt.Errorf("invalid string value %s", tokens[i])
+			}
+			i++
+		}
+
+		if seen[attr] {
+			return result, fmt.Errorf("attribute %s can only be filtered once", attr)
+		}
+		seen[attr] = true
+
+		if attr == "id" {
+			if op != "eq" {
+				return result, fmt.Errorf("id only supports eq")
+			}
+			// An invalid id matches no user
+			id, _ := uuid.Parse(value)
+			result.UserID = pgtype.UUID{Bytes: id, Valid: true}
+			continue
+		}
+
+		pattern, err := likePattern(op, value)
+		if err != nil {
+			return result, err
+		}
+		switch attr {
+		case "username", "emails", "emails.value":
+			result.Email = pattern
+		case "externalid":
+			result.ExternalSubject = pattern
+		case "name.givenname":
+			result.FirstName = pattern
+		case "name.familyname":
+			result.LastName = pattern
+		default:
+			return result, fmt.Errorf("filtering by %s is not supported", attr)
+		}
+	}
+
+	return result, nil
+}
+
+// tokenizeSCIMFilter splits a filter into words and quoted strings, quotes are kept
+func tokenizeSCIMFilter(filter string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(filter); {
+		switch c := filter[i]; {
+		case c == ' ':
+			i++
+		case c == '"':
+			end := i + 1
+			for ; end < len(filter) && filter[end] != '"'; end++ {
+				if filter[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(filter) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, filter[i:end+1])
+			i = end + 1
+		case c == '(' || c == ')' || c == '[' || c == ']':
+			return nil, fmt.Errorf("grouping and complex attribute filters are not supported")
+		default:
+			end := i
+			for end < len(filter) && filter[end] != ' ' {
+				end++
+			}
+			tokens = append(tokens, filter[i:end])
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// scimAttribute normalizes an attribute path, removing the schema URN and case
+func scimAttribute(path string) string {
+	path = strings.TrimPrefix(path, scimUserSchema+":")
+	return strings.ToLower(path)
+}
+
+// likePattern converts a SCIM comparison to a LIKE pattern
+func likePattern(op, value string) (pgtype.Text, error) {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+	switch op {
+	case "eq":
+		return pgtype.Text{String: escaped, Valid: true}, nil
+	case "sw":
+		return pgtype.Text{String: escaped + "%", Valid: true}, nil
+	case "co":
+		return pgtype.Text{String: "%" + escaped + "%", Valid: true}, nil
+	case "pr":
+		return pgtype.Text{String: "%", Valid: true}, nil
+	default:
+		return pgtype.Text{}, fmt.Errorf("operator %s is not supported", op)
+	}
+}
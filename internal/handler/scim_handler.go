@@ -0,0 +1,740 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"com.tom-ludwig/go-server-template/internal/api/scim"
+	"com.tom-ludwig/go-server-template/internal/audit"
+	"com.tom-ludwig/go-server-template/internal/outbox"
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+const (
+	scimUserSchema      = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimListSchema      = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimPatchOpSchema   = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	scimErrorSchema     = "urn:ietf:params:scim:api:messages:2.0:Error"
+	scimSPConfigSchema  = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	scimSchemaSchema    = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+	scimResourceSchema  = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	scimUsersPath       = "/scim/v2/Users"
+	scimMaxResults      = 100
+	uniqueViolationCode = "23505"
+)
+
+// uniqueConstraintAttributes are the SCIM attributes of the unique indexes of the users table
+var uniqueConstraintAttributes = map[string]string{
+	"users_issuer_external_subject_idx": "externalId",
+	usersEmailIndex:                     "userName",
+}
+
+// compile-time check
+var _ scim.StrictServerInterface = (*SCIMHandler)(nil)
+
+// SCIMHandler implements the SCIM 2.0 Users resource on top of the users table.
+// userName and the primary email are both stored in the email column, externalId in external_subject.
+type SCIMHandler struct {
+	// DB is used to record audit events in the transaction of the mutation
+	DB      *pgxpool.Pool
+	Queries *repository.Queries
+	// issuer is stored with the externalId, so SCIM users are matched by /me when externalId is the token subject
+	issuer string
+}
+
+func NewSCIMHandler(db *pgxpool.Pool, queries *repository.Queries, issuer string) *SCIMHandler {
+	return &SCIMHandler{
+		DB:      db,
+		Queries: queries,
+		issuer:  issuer,
+	}
+}
+
+// scimUserFields are the writable attributes of a SCIM user
+type scimUserFields struct {
+	Email      pgtype.Text
+	FirstName  pgtype.Text
+	LastName   pgtype.Text
+	ExternalID pgtype.Text
+	Status     pgtype.Text // set by active, unchanged if not set
+}
+
+// scimFailure is a SCIM error detected while processing a request
+type scimFailure struct {
+	status   int
+	scimType string
+	detail   string
+}
+
+func (f scimFailure) Error() string {
+	return f.detail
+}
+
+func (h *SCIMHandler) ListScimUsers(ctx context.Context, request scim.ListScimUsersRequestObject) (scim.ListScimUsersResponseObject, error) {
+	filter := ""
+	if request.Params.Filter != nil {
+		filter = *request.Params.Filter
+	}
+	parsed, err := parseSCIMFilter(filter)
+	if err != nil {
+		return scim.ListScimUsers400ApplicationScimPlusJSONResponse{
+			BadRequestApplicationScimPlusJSONResponse: scim.BadRequestApplicationScimPlusJSONResponse(newSCIMError(http.StatusBadRequest, "invalidFilter", err.Error())),
+		}, nil
+	}
+
+	// startIndex and count are clamped instead of rejected, as required by RFC 7644
+	startIndex := 1
+	if request.Params.StartIndex != nil && *request.Params.StartIndex > 1 {
+		startIndex = *request.Params.StartIndex
+	}
+	count := scimMaxResults
+	if request.Params.Count != nil {
+		count = min(max(*request.Params.Count, 0), scimMaxResults)
+	}
+
+	total, err := h.Queries.CountUsersByFilter(ctx, repository.CountUsersByFilterParams(parsed))
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to count scim users",
+			"error", err,
+		)
+		return scim.ListScimUsers500ApplicationScimPlusJSONResponse{
+			InternalServerErrorApplicationScimPlusJSONResponse: scim.InternalServerErrorApplicationScimPlusJSONResponse(internalSCIMError()),
+		}, nil
+	}
+
+	resources := []scim.ScimUser{}
+	if count > 0 {
+		dbUsers, err := h.Queries.ListUsersByFilter(ctx, repository.ListUsersByFilterParams{
+			UserID:          parsed.UserID,
+			Email:           parsed.Email,
+			ExternalSubject: parsed.ExternalSubject,
+			FirstName:       parsed.FirstName,
+			LastName:        parsed.LastName,
+			Skip:            int32(startIndex - 1),
+			MaxResults:      int32(count),
+		})
+		if err != nil {
+			slog.Error(
+				"An error occurred while trying to list scim users",
+				"error", err,
+			)
+			return scim.ListScimUsers500ApplicationScimPlusJSONResponse{
+				InternalServerErrorApplicationScimPlusJSONResponse: scim.InternalServerErrorApplicationScimPlusJSONResponse(internalSCIMError()),
+			}, nil
+		}
+		for _, dbUser := range dbUsers {
+			resources = append(resources, toSCIMUser(dbUser))
+		}
+	}
+
+	return scim.ListScimUsers200ApplicationScimPlusJSONResponse{
+		Schemas:      []string{scimListSchema},
+		TotalResults: int(total),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}, nil
+}
+
+func (h *SCIMHandler) CreateScimUser(ctx context.Context, request scim.CreateScimUserRequestObject) (scim.CreateScimUserResponseObject, error) {
+	fields, err := scimUserFieldsOf(*request.Body)
+	if err == nil {
+		var dbUser repository.User
+		err = audit.Mutate(ctx, h.DB, func(q *repository.Queries) (audit.Event, error) {
+			var err error
+			dbUser, err = q.CreateExternalUser(ctx, repository.CreateExternalUserParams{
+				Email:           fields.Email,
+				FirstName:       fields.FirstName,
+				LastName:        fields.LastName,
+				Issuer:          h.externalIssuer(fields.ExternalID),
+				ExternalSubject: fields.ExternalID,
+			})
+			if err == nil {
+				err = enqueueUserEvent(ctx, q, outbox.UserCreated, dbUser.UserID)
+			}
+			return userEvent("user.create", nil, &dbUser), err
+		})
+		if err == nil {
+			slog.Info("scim user created", "user_id", dbUser.UserID)
+			return scim.CreateScimUser201ApplicationScimPlusJSONResponse(toSCIMUser(dbUser)), nil
+		}
+	}
+
+	failure := scimFailureOf(err)
+	switch failure.status {
+	case http.StatusBadRequest:
+		return scim.CreateScimUser400ApplicationScimPlusJSONResponse{
+			BadRequestApplicationScimPlusJSONResponse: scim.BadRequestApplicationScimPlusJSONResponse(failure.body()),
+		}, nil
+	case http.StatusConflict:
+		return scim.CreateScimUser409ApplicationScimPlusJSONResponse{
+			ConflictApplicationScimPlusJSONResponse: scim.ConflictApplicationScimPlusJSONResponse(failure.body()),
+		}, nil
+	}
+
+	slog.Error(
+		"An error occurred while trying to create a scim user",
+		"error", err,
+	)
+	return scim.CreateScimUser500ApplicationScimPlusJSONResponse{
+		InternalServerErrorApplicationScimPlusJSONResponse: scim.InternalServerErrorApplicationScimPlusJSONResponse(internalSCIMError()),
+	}, nil
+}
+
+func (h *SCIMHandler) GetScimUser(ctx context.Context, request scim.GetScimUserRequestObject) (scim.GetScimUserResponseObject, error) {
+	dbUser, err := h.getUser(ctx, request.Id)
+	if err != nil {
+		if scimFailureOf(err).status == http.StatusNotFound {
+			return scim.GetScimUser404ApplicationScimPlusJSONResponse{
+				NotFoundApplicationScimPlusJSONResponse: scim.NotFoundApplicationScimPlusJSONResponse(scimFailureOf(err).body()),
+			}, nil
+		}
+
+		slog.Error(
+			"An error occurred while trying to get a scim user",
+			"error", err,
+		)
+		return scim.GetScimUser500ApplicationScimPlusJSONResponse{
+			InternalServerErrorApplicationScimPlusJSONResponse: scim.InternalServerErrorApplicationScimPlusJSONResponse(internalSCIMError()),
+		}, nil
+	}
+
+	return scim.GetScimUser200ApplicationScimPlusJSONResponse(toSCIMUser(dbUser)), nil
+}
+
+func (h *SCIMHandler) ReplaceScimUser(ctx context.Context, request scim.ReplaceScimUserRequestObject) (scim.ReplaceScimUserResponseObject, error) {
+	dbUser, err := h.getUser(ctx, request.Id)
+	if err == nil {
+		var fields scimUserFields
+		fields, err = scimUserFieldsOf(*request.Body)
+		if err == nil {
+			dbUser, err = h.replaceUser(ctx, "user.replace", dbUser.UserID, fields)
+		}
+	}
+	if err == nil {
+		return scim.ReplaceScimUser200ApplicationScimPlusJSONResponse(toSCIMUser(dbUser)), nil
+	}
+
+	failure := scimFailureOf(err)
+	switch failure.status {
+	case http.StatusBadRequest:
+		return scim.ReplaceScimUser400ApplicationScimPlusJSONResponse{
+			BadRequestApplicationScimPlusJSONResponse: scim.BadRequestApplicationScimPlusJSONResponse(failure.body()),
+		}, nil
+	case http.StatusNotFound:
+		return scim.ReplaceScimUser404ApplicationScimPlusJSONResponse{
+			NotFoundApplicationScimPlusJSONResponse: scim.NotFoundApplicationScimPlusJSONResponse(failure.body()),
+		}, nil
+	case http.StatusConflict:
+		return scim.ReplaceScimUser409ApplicationScimPlusJSONResponse{
+			ConflictApplicationScimPlusJSONResponse: scim.ConflictApplicationScimPlusJSONResponse(failure.body()),
+		}, nil
+	}
+
+	slog.Error(
+		"An error occurred while trying to replace a scim user",
+		"error", err,
+	)
+	return scim.ReplaceScimUser500ApplicationScimPlusJSONResponse{
+		InternalServerErrorApplicationScimPlusJSONResponse: scim.InternalServerErrorApplicationScimPlusJSONResponse(internalSCIMError()),
+	}, nil
+}
+
+func (h *SCIMHandler) PatchScimUser(ctx context.Context, request scim.PatchScimUserRequestObject) (scim.PatchScimUserResponseObject, error) {
+	dbUser, err := h.getUser(ctx, request.Id)
+	if err == nil {
+		fields := scimUserFields{
+			Email:      dbUser.Email,
+			FirstName:  dbUser.FirstName,
+			LastName:   dbUser.LastName,
+			ExternalID: dbUser.ExternalSubject,
+		}
+		err = applySCIMPatch(&fields, *request.Body)
+		if err == nil {
+			dbUser, err = h.replaceUser(ctx, "user.patch", dbUser.UserID, fields)
+		}
+	}
+	if err == nil {
+		return scim.PatchScimUser200ApplicationScimPlusJSONResponse(toSCIMUser(dbUser)), nil
+	}
+
+	failure := scimFailureOf(err)
+	switch failure.status {
+	case http.StatusBadRequest:
+		return scim.PatchScimUser400ApplicationScimPlusJSONResponse{
+			BadRequestApplicationScimPlusJSONResponse: scim.BadRequestApplicationScimPlusJSONResponse(failure.body()),
+		}, nil
+	case http.StatusNotFound:
+		return scim.PatchScimUser404ApplicationScimPlusJSONResponse{
+			NotFoundApplicationScimPlusJSONResponse: scim.NotFoundApplicationScimPlusJSONResponse(failure.body()),
+		}, nil
+	case http.StatusConflict:
+		return scim.PatchScimUser409ApplicationScimPlusJSONResponse{
+			ConflictApplicationScimPlusJSONResponse: scim.ConflictApplicationScimPlusJSONResponse(failure.body()),
+		}, nil
+	}
+
+	slog.Error(
+		"An error occurred while trying to patch a scim user",
+		"error", err,
+	)
+	return scim.PatchScimUser500ApplicationScimPlusJSONResponse{
+		InternalServerErrorApplicationScimPlusJSONResponse: scim.InternalServerErrorApplicationScimPlusJSONResponse(internalSCIMError()),
+	}, nil
+}
+
+func (h *SCIMHandler) DeleteScimUser(ctx context.Context, request scim.DeleteScimUserRequestObject) (scim.DeleteScimUserResponseObject, error) {
+	notFound := scim.DeleteScimUser404ApplicationScimPlusJSONResponse{
+		NotFoundApplicationScimPlusJSONResponse: scim.NotFoundApplicationScimPlusJSONResponse(newSCIMError(http.StatusNotFound, "", "user not found")),
+	}
+
+	userID, err := uuid.Parse(request.Id)
+	if err != nil {
+		return notFound, nil
+	}
+
+	err = audit.Mutate(ctx, h.DB, func(q *repository.Queries) (audit.Event, error) {
+		before, err := q.LockUser(ctx, userID)
+		if err != nil {
+			return audit.Event{}, err
+		}
+		deleted, err := q.SoftDeleteUser(ctx, repository.SoftDeleteUserParams{UserID: userID})
+		if err == nil {
+			err = enqueueUserEvent(ctx, q, outbox.UserDeleted, userID)
+		}
+		if err == nil {
+			err = revokeUser(ctx, q, before, "user deleted")
+		}
+		return userEvent("user.delete", &before, &deleted), err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return notFound, nil
+		}
+
+		slog.Error(
+			"An error occurred while trying to delete a scim user",
+			"error", err,
+		)
+		return scim.DeleteScimUser500ApplicationScimPlusJSONResponse{
+			InternalServerErrorApplicationScimPlusJSONResponse: scim.InternalServerErrorApplicationScimPlusJSONResponse(internalSCIMError()),
+		}, nil
+	}
+
+	slog.Info("scim user deleted", "user_id", userID)
+	return scim.DeleteScimUser204Response{}, nil
+}
+
+func (h *SCIMHandler) GetScimServiceProviderConfig(_ context.Context, _ scim.GetScimServiceProviderConfigRequestObject) (scim.GetScimServiceProviderConfigResponseObject, error) {
+	return scim.GetScimServiceProviderConfig200ApplicationScimPlusJSONResponse(scimServiceProviderConfig), nil
+}
+
+func (h *SCIMHandler) ListScimSchemas(_ context.Context, _ scim.ListScimSchemasRequestObject) (scim.ListScimSchemasResponseObject, error) {
+	return scim.ListScimSchemas200ApplicationScimPlusJSONResponse(scimDocumentList(scimUserSchemaDocument)), nil
+}
+
+func (h *SCIMHandler) GetScimSchema(_ context.Context, request scim.GetScimSchemaRequestObject) (scim.GetScimSchemaResponseObject, error) {
+	if request.Id != scimUserSchema {
+		return scim.GetScimSchema404ApplicationScimPlusJSONResponse{
+			NotFoundApplicationScimPlusJSONResponse: scim.NotFoundApplicationScimPlusJSONResponse(newSCIMError(http.StatusNotFound, "", "schema not found")),
+		}, nil
+	}
+	return scim.GetScimSchema200ApplicationScimPlusJSONResponse(scimUserSchemaDocument), nil
+}
+
+func (h *SCIMHandler) ListScimResourceTypes(_ context.Context, _ scim.ListScimResourceTypesRequestObject) (scim.ListScimResourceTypesResponseObject, error) {
+	return scim.ListScimResourceTypes200ApplicationScimPlusJSONResponse(scimDocumentList(scimUserResourceType)), nil
+}
+
+func (h *SCIMHandler) GetScimResourceType(_ context.Context, request scim.GetScimResourceTypeRequestObject) (scim.GetScimResourceTypeResponseObject, error) {
+	if request.Id != "User" {
+		return scim.GetScimResourceType404ApplicationScimPlusJSONResponse{
+			NotFoundApplicationScimPlusJSONResponse: scim.NotFoundApplicationScimPlusJSONResponse(newSCIMError(http.StatusNotFound, "", "resource type not found")),
+		}, nil
+	}
+	return scim.GetScimResourceType200ApplicationScimPlusJSONResponse(scimUserResourceType), nil
+}
+
+func (h *SCIMHandler) getUser(ctx context.Context, id string) (repository.User, error) {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return repository.User{}, scimFailure{status: http.StatusNotFound, detail: "user not found"}
+	}
+	dbUser, err := h.Queries.GetUser(ctx, repository.GetUserParams{UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.User{}, scimFailure{status: http.StatusNotFound, detail: "user not found"}
+	}
+	return dbUser, err
+}
+
+// replaceUser writes all fields of the user and records the change as action
+func (h *SCIMHandler) replaceUser(ctx context.Context, action string, userID uuid.UUID, fields scimUserFields) (repository.User, error) {
+	var dbUser repository.User
+	err := audit.Mutate(ctx, h.DB, func(q *repository.Queries) (audit.Event, error) {
+		before, err := q.LockUser(ctx, userID)
+		if err != nil {
+			return audit.Event{}, err
+		}
+		dbUser, err = q.ReplaceUser(ctx, repository.ReplaceUserParams{
+			UserID:          userID,
+			Email:           fields.Email,
+			FirstName:       fields.FirstName,
+			LastName:        fields.LastName,
+			Issuer:          h.externalIssuer(fields.ExternalID),
+			ExternalSubject: fields.ExternalID,
+			Status:          fields.Status,
+		})
+		if err == nil {
+			err = enqueueUserEvent(ctx, q, outbox.UserUpdated, userID)
+		}
+		if err == nil && revokesSessions(before, dbUser) {
+			// The tokens were issued to the externalId before the change
+			err = revokeUser(ctx, q, before, "user "+dbUser.Status)
+		}
+		return userEvent(action, &before, &dbUser), err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Deleted since it was read
+		return repository.User{}, scimFailure{status: http.StatusNotFound, detail: "user not found"}
+	}
+	return dbUser, err
+}
+
+// externalIssuer returns the issuer stored with an externalId
+func (h *SCIMHandler) externalIssuer(externalID pgtype.Text) pgtype.Text {
+	if !externalID.Valid {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: h.issuer, Valid: true}
+}
+
+// scimUserFieldsOf validates a SCIM user for create and replace
+func scimUserFieldsOf(user scim.ScimUser) (scimUserFields, error) {
+	var fields scimUserFields
+
+	userName := strings.TrimSpace(user.UserName)
+	if userName == "" {
+		return fields, scimFailure{status: http.StatusBadRequest, scimType: "invalidValue", detail: "userName must not be empty"}
+	}
+
+	fields.Email = pgtype.Text{String: userName, Valid: true}
+	fields.ExternalID = optionalText(nonEmptyPtr(user.ExternalId))
+	if user.Active != nil {
+		fields.Status = scimStatus(*user.Active)
+	}
+	if user.Name != nil {
+		fields.FirstName = optionalText(nonEmptyPtr(user.Name.GivenName))
+		fields.LastName = optionalText(nonEmptyPtr(user.Name.FamilyName))
+	}
+	return fields, nil
+}
+
+// scimStatus maps the active attribute to the user status. Suspended users are reported as inactive.
+func scimStatus(active bool) pgtype.Text {
+	if active {
+		return pgtype.Text{String: "active", Valid: true}
+	}
+	return pgtype.Text{String: "deactivated", Valid: true}
+}
+
+// applySCIMPatch applies the operations of a PATCH request (RFC 7644 section 3.5.2)
+func applySCIMPatch(fields *scimUserFields, patch scim.ScimPatchRequest) error {
+	if !slices.Contains(patch.Schemas, scimPatchOpSchema) {
+		return scimFailure{status: http.StatusBadRequest, scimType: "invalidSyntax", detail: "schemas must contain " + scimPatchOpSchema}
+	}
+
+	for _, operation := range patch.Operations {
+		op := strings.ToLower(operation.Op)
+		if op != "add" && op != "replace" && op != "remove" {
+			return scimFailure{status: http.StatusBadRequest, scimType: "invalidSyntax", detail: fmt.Sprintf("unsupported op %q", operation.Op)}
+		}
+
+		if operation.Path == nil || *operation.Path == "" {
+			// Without a path the value is an object of attribute paths and values
+			values, ok := operation.Value.(map[string]any)
+			if op == "remove" || !ok {
+				return scimFailure{status: http.StatusBadRequest, scimType: "noTarget", detail: "operations without a path require an object value"}
+			}
+			for path, value := range values {
+				if err := applySCIMValue(fields, path, value, false); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := applySCIMValue(fields, *operation.Path, operation.Value, op == "remove"); err != nil {
+			return err
+		}
+	}
+
+	if !fields.Email.Valid || fields.Email.String == "" {
+		return scimFailure{status: http.StatusBadRequest, scimType: "invalidValue", detail: "userName must not be empty"}
+	}
+	return nil
+}
+
+func applySCIMValue(fields *scimUserFields, path string, value any, remove bool) error {
+	attr := scimAttribute(path)
+	// emails[type eq "work"].value and emails[primary eq true].value address the only email
+	if strings.HasPrefix(attr, "emails[") && strings.HasSuffix(attr, "].value") {
+		attr = "emails.value"
+	}
+
+	switch attr {
+	case "username", "emails.value":
+		return setSCIMText(&fields.Email, path, value, remove)
+	case "externalid":
+		return setSCIMText(&fields.ExternalID, path, value, remove)
+	case "name.givenname":
+		return setSCIMText(&fields.FirstName, path, value, remove)
+	case "name.familyname":
+		return setSCIMText(&fields.LastName, path, value, remove)
+	case "name":
+		if remove {
+			fields.FirstName, fields.LastName = pgtype.Text{}, pgtype.Text{}
+			return nil
+		}
+		name, ok := value.(map[string]any)
+		if !ok {
+			return invalidSCIMValue(path)
+		}
+		for subAttr, subValue := range name {
+			if err := applySCIMValue(fields, "name."+subAttr, subValue, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "emails":
+		if remove {
+			return invalidSCIMValue(path)
+		}
+		email, err := primarySCIMEmail(value)
+		if err != nil {
+			return invalidSCIMValue(path)
+		}
+		fields.Email = pgtype.Text{String: email, Valid: true}
+		return nil
+	case "active":
+		active, ok := value.(bool)
+		if s, isString := value.(string); isString {
+			// Entra ID sends booleans as strings
+			parsed, err := strconv.ParseBool(s)
+			active, ok = parsed, err == nil
+		}
+		if remove || !ok {
+			return invalidSCIMValue(path)
+		}
+		fields.Status = scimStatus(active)
+		return nil
+	default:
+		return scimFailure{status: http.StatusBadRequest, scimType: "invalidPath", detail: fmt.Sprintf("attribute %s is not supported", path)}
+	}
+}
+
+func setSCIMText(field *pgtype.Text, path string, value any, remove bool) error {
+	if remove {
+		*field = pgtype.Text{}
+		return nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return invalidSCIMValue(path)
+	}
+	*field = optionalText(nonEmpty(strings.TrimSpace(s)))
+	return nil
+}
+
+// primarySCIMEmail returns the primary or first email of an emails value
+func primarySCIMEmail(value any) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	var emails []scim.ScimEmail
+	if err := json.Unmarshal(data, &emails); err != nil || len(emails) == 0 {
+		return "", errors.New("invalid emails")
+	}
+	for _, email := range emails {
+		if email.Primary != nil && *email.Primary {
+			return email.Value, nil
+		}
+	}
+	return emails[0].Value, nil
+}
+
+func invalidSCIMValue(path string) error {
+	return scimFailure{status: http.StatusBadRequest, scimType: "invalidValue", detail: fmt.Sprintf("invalid value for %s", path)}
+}
+
+// scimFailureOf converts errors to SCIM failures, unknown errors have status 500
+func scimFailureOf(err error) scimFailure {
+	var failure scimFailure
+	if errors.As(err, &failure) {
+		return failure
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		if attribute, ok := uniqueConstraintAttributes[pgErr.ConstraintName]; ok {
+			return scimFailure{status: http.StatusConflict, scimType: "uniqueness", detail: attribute + " is already taken"}
+		}
+	}
+	return scimFailure{status: http.StatusInternalServerError}
+}
+
+func (f scimFailure) body() scim.ScimError {
+	return newSCIMError(f.status, f.scimType, f.detail)
+}
+
+func newSCIMError(status int, scimType, detail string) scim.ScimError {
+	scimError := scim.ScimError{
+		Schemas: []string{scimErrorSchema},
+		Status:  strconv.Itoa(status),
+		Detail:  nonEmpty(detail),
+	}
+	if scimType != "" {
+		scimError.ScimType = &scimType
+	}
+	return scimError
+}
+
+func internalSCIMError() scim.ScimError {
+	return newSCIMError(http.StatusInternalServerError, "", "An internal server error occurred")
+}
+
+// WriteSCIMError writes an error in the SCIM format, it is used for errors raised outside the handler
+func WriteSCIMError(w http.ResponseWriter, _ *http.Request, status int, message string) {
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(newSCIMError(status, "", message)); err != nil {
+		slog.Warn("Failed to write scim error", "error", err)
+	}
+}
+
+func toSCIMUser(dbUser repository.User) scim.ScimUser {
+	id := dbUser.UserID.String()
+	location := scimUsersPath + "/" + id
+	user := scim.ScimUser{
+		Schemas:    []string{scimUserSchema},
+		Id:         &id,
+		UserName:   dbUser.Email.String,
+		ExternalId: textPtr(dbUser.ExternalSubject),
+		Active:     ptr(dbUser.Status == "active"),
+		Meta: &scim.ScimMeta{
+			ResourceType: "User",
+			Created:      &dbUser.CreatedAt,
+			Location:     &location,
+		},
+	}
+	if dbUser.FirstName.Valid || dbUser.LastName.Valid {
+		user.Name = &scim.ScimName{
+			GivenName:  textPtr(dbUser.FirstName),
+			FamilyName: textPtr(dbUser.LastName),
+		}
+	}
+	if dbUser.Email.Valid {
+		user.Emails = &[]scim.ScimEmail{{Value: dbUser.Email.String, Type: ptr("work"), Primary: ptr(true)}}
+	}
+	return user
+}
+
+func nonEmptyPtr(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	return nonEmpty(strings.TrimSpace(*s))
+}
+
+func scimDocumentList(documents ...scim.ScimDocument) scim.ScimDocumentList {
+	return scim.ScimDocumentList{
+		Schemas:      []string{scimListSchema},
+		TotalResults: len(documents),
+		StartIndex:   1,
+		ItemsPerPage: len(documents),
+		Resources:    documents,
+	}
+}
+
+var scimServiceProviderConfig = scim.ScimDocument{
+	"schemas":        []string{scimSPConfigSchema},
+	"patch":          map[string]any{"supported": true},
+	"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+	"filter":         map[string]any{"supported": true, "maxResults": scimMaxResults},
+	"changePassword": map[string]any{"supported": false},
+	"sort":           map[string]any{"supported": false},
+	"etag":           map[string]any{"supported": false},
+	"authenticationSchemes": []map[string]any{{
+		"type":        "oauthbearertoken",
+		"name":        "OAuth Bearer Token",
+		"description": "The SCIM token or an access token with the SCIM scope",
+		"primary":     true,
+	}},
+	"meta": map[string]any{"resourceType": "ServiceProviderConfig", "location": "/scim/v2/ServiceProviderConfig"},
+}
+
+var scimUserResourceType = scim.ScimDocument{
+	"schemas":     []string{scimResourceSchema},
+	"id":          "User",
+	"name":        "User",
+	"endpoint":    "/Users",
+	"description": "User Account",
+	"schema":      scimUserSchema,
+	"meta":        map[string]any{"resourceType": "ResourceType", "location": "/scim/v2/ResourceTypes/User"},
+}
+
+var scimUserSchemaDocument = scim.ScimDocument{
+	"schemas":     []string{scimSchemaSchema},
+	"id":          scimUserSchema,
+	"name":        "User",
+	"description": "User Account",
+	"attributes": []map[string]any{
+		scimStringAttribute("userName", true, "server"),
+		scimStringAttribute("externalId", false, "none"),
+		{
+			"name": "name", "type": "complex", "multiValued": false, "required": false,
+			"mutability": "readWrite", "returned": "default",
+			"subAttributes": []map[string]any{
+				scimStringAttribute("givenName", false, "none"),
+				scimStringAttribute("familyName", false, "none"),
+			},
+		},
+		{
+			"name": "emails", "type": "complex", "multiValued": true, "required": false,
+			"mutability": "readWrite", "returned": "default",
+			"subAttributes": []map[string]any{
+				scimStringAttribute("value", false, "none"),
+				scimStringAttribute("type", false, "none"),
+				{"name": "primary", "type": "boolean", "multiValued": false, "required": false, "mutability": "readWrite", "returned": "default"},
+			},
+		},
+		{"name": "active", "type": "boolean", "multiValued": false, "required": false, "mutability": "readWrite", "returned": "default"},
+	},
+	"meta": map[string]any{"resourceType": "Schema", "location": "/scim/v2/Schemas/" + scimUserSchema},
+}
+
+func scimStringAttribute(name string, required bool, uniqueness string) map[string]any {
+	return map[string]any{
+		"name":        name,
+		"type":        "string",
+		"multiValued": false,
+		"required":    required,
+		"caseExact":   name == "externalId",
+		"mutability":  "readWrite",
+		"returned":    "default",
+		"uniqueness":  uniqueness,
+	}
+}
@@ -0,0 +1,291 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"com.tom-ludwig/go-server-template/internal/api/useradmin"
+	"com.tom-ludwig/go-server-template/internal/audit"
+	"com.tom-ludwig/go-server-template/internal/outbox"
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+// userStatusTransitions maps each status to the statuses it can be reached from.
+// Setting the current status again is allowed so retries are idempotent.
+var userStatusTransitions = map[useradmin.UserStatus][]string{
+	"active":      {"active", "suspended", "deactivated"},
+	"suspended":   {"suspended", "active"},
+	"deactivated": {"deactivated", "active", "suspended"},
+}
+
+// compile-time check
+var _ useradmin.StrictServerInterface = (*UserAdminHandler)(nil)
+
+type UserAdminHandler struct {
+	// DB is used to record audit events in the transaction of the mutation
+	DB      *pgxpool.Pool
+	Queries *repository.Queries
+	// retention is how long deleted users can be restored before they are purged
+	retention time.Duration
+}
+
+func NewUserAdminHandler(db *pgxpool.Pool, queries *repository.Queries, retention time.Duration) *UserAdminHandler {
+	return &UserAdminHandler{
+		DB:        db,
+		Queries:   queries,
+		retention: retention,
+	}
+}
+
+// adminFailure is an expected error of a user admin operation, mapped to the response with its status
+type adminFailure struct {
+	status  int
+	message string
+}
+
+func (f adminFailure) Error() string {
+	return f.message
+}
+
+var (
+	errUserNotFound    = adminFailure{status: http.StatusNotFound, message: "user not found"}
+	errUserModified    = adminFailure{status: http.StatusPreconditionFailed, message: "user was modified, fetch it again for the current ETag"}
+	errIfMatchRequired = adminFailure{status: http.StatusPreconditionRequired, message: "If-Match with the ETag of the user is required"}
+)
+
+func (h *UserAdminHandler) DeleteUser(ctx context.Context, request useradmin.DeleteUserRequestObject) (useradmin.DeleteUserResponseObject, error) {
+	dbUser, err := h.currentUser(ctx, request.UserId, request.Params.IfMatch)
+	if err == nil && dbUser.DeletedAt.Valid {
+		err = errUserNotFound
+	}
+	if err == nil {
+		err = audit.Mutate(ctx, h.DB, func(q *repository.Queries) (audit.Event, error) {
+			deleted, err := q.SoftDeleteUser(ctx, repository.SoftDeleteUserParams{
+				UserID:  request.UserId,
+				Version: pgtype.Int8{Int64: dbUser.Version, Valid: true},
+			})
+			if err == nil {
+				err = enqueueUserEvent(ctx, q, outbox.UserDeleted, deleted.UserID)
+			}
+			if err == nil {
+				err = revokeUser(ctx, q, deleted, "user deleted")
+			}
+			return userEvent("user.delete", &dbUser, &deleted), err
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = errUserModified
+		}
+	}
+	if err != nil {
+		var failure adminFailure
+		if errors.As(err, &failure) {
+			switch failure.status {
+			case http.StatusNotFound:
+				return useradmin.DeleteUser404JSONResponse{NotFoundJSONResponse: useradmin.NotFoundJSONResponse{Message: failure.message}}, nil
+			case http.StatusPreconditionFailed:
+				return useradmin.DeleteUser412JSONResponse{PreconditionFailedJSONResponse: useradmin.PreconditionFailedJSONResponse{Message: failure.message}}, nil
+			case http.StatusPreconditionRequired:
+				return useradmin.DeleteUser428JSONResponse{PreconditionRequiredJSONResponse: useradmin.PreconditionRequiredJSONResponse{Message: failure.message}}, nil
+			}
+		}
+
+		slog.Error(
+			"An error occurred while trying to delete a user",
+			"error", err,
+		)
+		return useradmin.DeleteUser500JSONResponse{
+			InternalServerErrorJSONResponse: useradmin.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	slog.Info("user deleted", "user_id", request.UserId)
+
+	return useradmin.DeleteUser204Response{}, nil
+}
+
+func (h *UserAdminHandler) UpdateUserStatus(ctx context.Context, request useradmin.UpdateUserStatusRequestObject) (useradmin.UpdateUserStatusResponseObject, error) {
+	fromStatuses, ok := userStatusTransitions[request.Body.Status]
+	if !ok {
+		return useradmin.UpdateUserStatus400JSONResponse{
+			BadRequestJSONResponse: useradmin.BadRequestJSONResponse{
+				Message: "unknown status",
+			},
+		}, nil
+	}
+
+	dbUser, err := h.currentUser(ctx, request.UserId, request.Params.IfMatch)
+	if err == nil {
+		switch {
+		case dbUser.DeletedAt.Valid:
+			err = adminFailure{status: http.StatusConflict, message: "user is deleted, restore it first"}
+		case !slices.Contains(fromStatuses, dbUser.Status):
+			err = adminFailure{status: http.StatusConflict, message: "cannot change status from " + dbUser.Status + " to " + string(request.Body.Status)}
+		}
+	}
+	if err == nil {
+		before := dbUser
+		err = audit.Mutate(ctx, h.DB, func(q *repository.Queries) (audit.Event, error) {
+			var err error
+			dbUser, err = q.UpdateUserStatus(ctx, repository.UpdateUserStatusParams{
+				Status:       string(request.Body.Status),
+				UserID:       request.UserId,
+				FromStatuses: fromStatuses,
+				Version:      before.Version,
+			})
+			if err == nil {
+				err = enqueueUserEvent(ctx, q, outbox.UserUpdated, dbUser.UserID)
+			}
+			if err == nil && revokesSessions(before, dbUser) {
+				err = revokeUser(ctx, q, dbUser, "user "+dbUser.Status)
+			}
+			return userEvent("user.update_status", &before, &dbUser), err
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = errUserModified
+		}
+	}
+	if err != nil {
+		var failure adminFailure
+		if errors.As(err, &failure) {
+			switch failure.status {
+			case http.StatusNotFound:
+				return useradmin.UpdateUserStatus404JSONResponse{NotFoundJSONResponse: useradmin.NotFoundJSONResponse{Message: failure.message}}, nil
+			case http.StatusConflict:
+				return useradmin.UpdateUserStatus409JSONResponse{ConflictJSONResponse: useradmin.ConflictJSONResponse{Message: failure.message}}, nil
+			case http.StatusPreconditionFailed:
+				return useradmin.UpdateUserStatus412JSONResponse{PreconditionFailedJSONResponse: useradmin.PreconditionFailedJSONResponse{Message: failure.message}}, nil
+			case http.StatusPreconditionRequired:
+				return useradmin.UpdateUserStatus428JSONResponse{PreconditionRequiredJSONResponse: useradmin.PreconditionRequiredJSONResponse{Message: failure.message}}, nil
+			}
+		}
+
+		slog.Error(
+			"An error occurred while trying to update a user status",
+			"error", err,
+		)
+		return useradmin.UpdateUserStatus500JSONResponse{
+			InternalServerErrorJSONResponse: useradmin.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	slog.Info("user status updated", "user_id", request.UserId, "status", dbUser.Status)
+
+	return useradmin.UpdateUserStatus200JSONResponse{
+		Body:    toAdminUser(dbUser),
+		Headers: useradmin.UpdateUserStatus200ResponseHeaders{ETag: userETag(dbUser)},
+	}, nil
+}
+
+func (h *UserAdminHandler) RestoreUser(ctx context.Context, request useradmin.RestoreUserRequestObject) (useradmin.RestoreUserResponseObject, error) {
+	deletedAfter := time.Now().Add(-h.retention)
+
+	dbUser, err := h.currentUser(ctx, request.UserId, request.Params.IfMatch)
+	if err == nil {
+		switch {
+		case !dbUser.DeletedAt.Valid:
+			err = adminFailure{status: http.StatusConflict, message: "user is not deleted"}
+		case !dbUser.DeletedAt.Time.After(deletedAfter):
+			err = adminFailure{status: http.StatusGone, message: "the retention window has passed, the user will be purged"}
+		}
+	}
+	if err == nil {
+		before := dbUser
+		err = audit.Mutate(ctx, h.DB, func(q *repository.Queries) (audit.Event, error) {
+			var err error
+			dbUser, err = q.RestoreUser(ctx, repository.RestoreUserParams{
+				UserID:       request.UserId,
+				DeletedAfter: pgtype.Timestamptz{Time: deletedAfter, Valid: true},
+				Version:      before.Version,
+			})
+			if err == nil {
+				err = enqueueUserEvent(ctx, q, outbox.UserUpdated, dbUser.UserID)
+			}
+			return userEvent("user.restore", &before, &dbUser), err
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = errUserModified
+		}
+		if isUniqueViolation(err, usersEmailIndex) {
+			err = adminFailure{status: http.StatusConflict, message: "another user has the email of the user"}
+		}
+	}
+	if err != nil {
+		var failure adminFailure
+		if errors.As(err, &failure) {
+			switch failure.status {
+			case http.StatusNotFound:
+				return useradmin.RestoreUser404JSONResponse{NotFoundJSONResponse: useradmin.NotFoundJSONResponse{Message: failure.message}}, nil
+			case http.StatusConflict:
+				return useradmin.RestoreUser409JSONResponse{ConflictJSONResponse: useradmin.ConflictJSONResponse{Message: failure.message}}, nil
+			case http.StatusGone:
+				return useradmin.RestoreUser410JSONResponse{GoneJSONResponse: useradmin.GoneJSONResponse{Message: failure.message}}, nil
+			case http.StatusPreconditionFailed:
+				return useradmin.RestoreUser412JSONResponse{PreconditionFailedJSONResponse: useradmin.PreconditionFailedJSONResponse{Message: failure.message}}, nil
+			case http.StatusPreconditionRequired:
+				return useradmin.RestoreUser428JSONResponse{PreconditionRequiredJSONResponse: useradmin.PreconditionRequiredJSONResponse{Message: failure.message}}, nil
+			}
+		}
+
+		slog.Error(
+			"An error occurred while trying to restore a user",
+			"error", err,
+		)
+		return useradmin.RestoreUser500JSONResponse{
+			InternalServerErrorJSONResponse: useradmin.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	slog.Info("user restored", "user_id", request.UserId)
+
+	return useradmin.RestoreUser200JSONResponse{
+		Body:    toAdminUser(dbUser),
+		Headers: useradmin.RestoreUser200ResponseHeaders{ETag: userETag(dbUser)},
+	}, nil
+}
+
+// currentUser returns the user, including deleted ones, if If-Match is its current ETag.
+// The update must still be conditional on the version, the user can change until it runs.
+func (h *UserAdminHandler) currentUser(ctx context.Context, userID uuid.UUID, ifMatch *string) (repository.User, error) {
+	if ifMatch == nil {
+		return repository.User{}, errIfMatchRequired
+	}
+
+	dbUser, err := h.Queries.GetUser(ctx, repository.GetUserParams{UserID: userID, IncludeDeleted: true})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.User{}, errUserNotFound
+	}
+	if err != nil {
+		return repository.User{}, err
+	}
+
+	if !etagListMatches(*ifMatch, userETag(dbUser), false) {
+		return repository.User{}, errUserModified
+	}
+	return dbUser, nil
+}
+
+func toAdminUser(dbUser repository.User) useradmin.User {
+	return useradmin.User{
+		UserId:    dbUser.UserID.String(),
+		FirstName: dbUser.FirstName.String,
+		LastName:  dbUser.LastName.String,
+		Email:     dbUser.Email.String,
+		Status:    useradmin.UserStatus(dbUser.Status),
+		DeletedAt: timestamptzPtr(dbUser.DeletedAt),
+	}
+}
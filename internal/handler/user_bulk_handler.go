@@ -0,0 +1,459 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"com.tom-ludwig/go-server-template/internal/api/users"
+	"com.tom-ludwig/go-server-template/internal/audit"
+	"com.tom-ludwig/go-server-template/internal/outbox"
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+const (
+	// importBatchSize is the number of rows sent per COPY
+	importBatchSize = 1000
+	// maxImportErrors bounds the error report, further invalid rows are only counted
+	maxImportErrors = 1000
+	// maxImportLineSize bounds a single NDJSON line
+	maxImportLineSize = 1 << 20
+)
+
+// fetchUserExport reads the next rows of the cursor opened by DeclareUserExportCursor.
+// It is not generated because sqlc cannot infer the columns of FETCH.
+const fetchUserExport = "FETCH FORWARD 500 FROM user_export"
+
+// userExportColumns is the header of CSV exports, imports accept the UserCreate properties
+var userExportColumns = []string{"user_id", "email", "first_name", "last_name", "status", "deleted_at"}
+
+// userCreateSchema returns the schema every imported row is validated against
+var userCreateSchema = sync.OnceValues(func() (*openapi3.Schema, error) {
+	swagger, err := users.GetSwagger()
+	if err != nil {
+		return nil, err
+	}
+	ref := swagger.Components.Schemas["UserCreate"]
+	if ref == nil || ref.Value == nil {
+		return nil, errors.New("schema UserCreate not found")
+	}
+	return ref.Value, nil
+})
+
+func (u *UserHandler) ImportUsers(ctx context.Context, request users.ImportUsersRequestObject) (users.ImportUsersResponseObject, error) {
+	schema, err := userCreateSchema()
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to load the UserCreate schema",
+			"error", err,
+		)
+		return users.ImportUsers500JSONResponse{
+			InternalServerErrorJSONResponse: users.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	var rows importRowReader
+	switch mediaType, _, _ := mime.ParseMediaType(request.ContentType); mediaType {
+	case "application/x-ndjson":
+		rows = newNDJSONRowReader(request.Body)
+	case "text/csv":
+		rows, err = newCSVRowReader(request.Body, schema)
+		if err != nil {
+			return users.ImportUsers400JSONResponse{
+				BadRequestJSONResponse: users.BadRequestJSONResponse{
+					Message: err.Error(),
+				},
+			}, nil
+		}
+	default:
+		return users.ImportUsers415JSONResponse{
+			UnsupportedMediaTypeJSONResponse: users.UnsupportedMediaTypeJSONResponse{
+				Message: "Content-Type must be application/x-ndjson or text/csv",
+			},
+		}, nil
+	}
+
+	report := users.UserImportReport{Errors: []users.UserImportError{}}
+	addError := func(line int64, err error) {
+		report.Failed++
+		if len(report.Errors) < maxImportErrors {
+			report.Errors = append(report.Errors, users.UserImportError{Row: line, Message: err.Error()})
+		}
+	}
+
+	// Valid rows are imported together, a database error imports none of them
+	tx, err := u.DB.Begin(ctx)
+	if err == nil {
+		defer tx.Rollback(context.WithoutCancel(ctx))
+
+		queries := u.Queries.WithTx(tx)
+		batch := make([]repository.CopyUsersParams, 0, importBatchSize)
+		for err == nil {
+			var row importRow
+			row, err = rows.next()
+			if err != nil {
+				break
+			}
+			if row.err != nil {
+				addError(row.line, row.err)
+				continue
+			}
+
+			user, rowErr := validateImportRow(schema, row.values)
+			if rowErr != nil {
+				addError(row.line, rowErr)
+				continue
+			}
+
+			batch = append(batch, user)
+			if len(batch) == importBatchSize {
+				report.Imported, err = copyUsers(ctx, queries, batch, report.Imported)
+				batch = batch[:0]
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			report.Imported, err = copyUsers(ctx, queries, batch, report.Imported)
+		}
+		if err == nil {
+			// COPY doesn't return the new users, the import is recorded as one event
+			err = audit.Record(ctx, queries, audit.Event{
+				Action:       "user.import",
+				ResourceType: "user",
+				After:        map[string]int64{"imported": report.Imported, "failed": report.Failed},
+			})
+		}
+		if err == nil {
+			err = tx.Commit(ctx)
+		}
+	}
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return users.ImportUsers413JSONResponse{
+				ContentTooLargeJSONResponse: users.ContentTooLargeJSONResponse{
+					Error: fmt.Sprintf("request body must not be larger than %d bytes.", tooLarge.Limit),
+				},
+			}, nil
+		}
+		if isUniqueViolation(err, usersEmailIndex) {
+			return users.ImportUsers400JSONResponse{
+				BadRequestJSONResponse: users.BadRequestJSONResponse{
+					Message: "an email is already taken or used by several rows, no users were imported",
+				},
+			}, nil
+		}
+		var readErr importReadError
+		if errors.As(err, &readErr) {
+			return users.ImportUsers400JSONResponse{
+				BadRequestJSONResponse: users.BadRequestJSONResponse{
+					Message: readErr.Error(),
+				},
+			}, nil
+		}
+
+		slog.Error(
+			"An error occurred while trying to import users",
+			"error", err,
+		)
+		return users.ImportUsers500JSONResponse{
+			InternalServerErrorJSONResponse: users.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	slog.Info("users imported", "imported", report.Imported, "failed", report.Failed)
+
+	return users.ImportUsers200JSONResponse(report), nil
+}
+
+// copyUsers loads a batch with COPY, publishes the new users and returns the new number of imported users
+func copyUsers(ctx context.Context, queries *repository.Queries, batch []repository.CopyUsersParams, imported int64) (int64, error) {
+	if len(batch) == 0 {
+		return imported, nil
+	}
+	// COPY cannot return the generated IDs, so they are generated here
+	userIDs := make([]uuid.UUID, len(batch))
+	for i := range batch {
+		userID, err := uuid.NewV7()
+		if err != nil {
+			return imported, err
+		}
+		batch[i].UserID = userID
+		userIDs[i] = userID
+	}
+
+	copied, err := queries.CopyUsers(ctx, batch)
+	if err != nil {
+		return imported, err
+	}
+	return imported + copied, enqueueUserEvent(ctx, queries, outbox.UserCreated, userIDs...)
+}
+
+// validateImportRow checks a row against UserCreate and converts it
+func validateImportRow(schema *openapi3.Schema, values map[string]any) (repository.CopyUsersParams, error) {
+	if err := schema.VisitJSON(values); err != nil {
+		var schemaErr *openapi3.SchemaError
+		if !errors.As(err, &schemaErr) {
+			return repository.CopyUsersParams{}, err
+		}
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+			return repository.CopyUsersParams{}, fmt.Errorf("%s: %s", strings.Join(pointer, "."), schemaErr.Reason)
+		}
+		return repository.CopyUsersParams{}, errors.New(schemaErr.Reason)
+	}
+
+	// The schema guarantees the required properties are strings
+	return repository.CopyUsersParams{
+		Email:     pgtype.Text{String: values["email"].(string), Valid: true},
+		FirstName: pgtype.Text{String: values["first_name"].(string), Valid: true},
+		LastName:  pgtype.Text{String: values["last_name"].(string), Valid: true},
+	}, nil
+}
+
+// importRow is a parsed row of an import, values are keyed by UserCreate property
+type importRow struct {
+	line   int64
+	values map[string]any
+	err    error // the row could not be parsed, the import continues with the next row
+}
+
+// importRowReader reads the rows of an import stream. It returns io.EOF at the end
+// and an importReadError if the stream cannot be read any further.
+type importRowReader interface {
+	next() (importRow, error)
+}
+
+// importReadError aborts an import because of a malformed stream
+type importReadError struct {
+	err error
+}
+
+func (e importReadError) Error() string {
+	return "failed to read import: " + e.err.Error()
+}
+
+func (e importReadError) Unwrap() error {
+	return e.err
+}
+
+// ndjsonRowReader reads one JSON object per line, empty lines are skipped
+type ndjsonRowReader struct {
+	scanner *bufio.Scanner
+	line    int64
+}
+
+func newNDJSONRowReader(body io.Reader) *ndjsonRowReader {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+	return &ndjsonRowReader{scanner: scanner}
+}
+
+func (r *ndjsonRowReader) next() (importRow, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		row := importRow{line: r.line}
+		if err := json.Unmarshal(line, &row.values); err != nil || row.values == nil {
+			row.err = errors.New("line is not a JSON object")
+		}
+		return row, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return importRow{}, importReadError{err: err}
+	}
+	return importRow{}, io.EOF
+}
+
+// csvRowReader reads CSV records, the header row names the UserCreate property of each column
+type csvRowReader struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+// newCSVRowReader reads the header and checks that it has every required column
+func newCSVRowReader(body io.Reader, schema *openapi3.Schema) (*csvRowReader, error) {
+	reader := csv.NewReader(body)
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("CSV file has no header row")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	columns := slices.Clone(header)
+	for _, column := range columns {
+		if _, ok := schema.Properties[column]; !ok {
+			return nil, fmt.Errorf("unknown CSV column %q", column)
+		}
+	}
+	for _, required := range schema.Required {
+		if !slices.Contains(columns, required) {
+			return nil, fmt.Errorf("CSV column %q is missing", required)
+		}
+	}
+
+	return &csvRowReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvRowReader) next() (importRow, error) {
+	record, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return importRow{}, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return importRow{line: int64(parseErr.StartLine), err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return importRow{}, importReadError{err: err}
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	row := importRow{line: int64(line), values: map[string]any{}}
+	for i, column := range r.columns {
+		// Empty cells are missing values
+		if record[i] != "" {
+			row.values[column] = record[i]
+		}
+	}
+	return row, nil
+}
+
+func (u *UserHandler) ExportUsers(ctx context.Context, request users.ExportUsersRequestObject) (users.ExportUsersResponseObject, error) {
+	if !u.canIncludeDeleted(ctx, request.Params.IncludeDeleted) {
+		return users.ExportUsers403JSONResponse{
+			ForbiddenJSONResponse: users.ForbiddenJSONResponse{
+				Message: "include_deleted requires the admin scope",
+			},
+		}, nil
+	}
+	includeDeleted := request.Params.IncludeDeleted != nil && *request.Params.IncludeDeleted
+
+	// The cursor reads a consistent snapshot, no matter how long the client takes to read the export
+	tx, err := u.DB.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err == nil {
+		err = u.Queries.WithTx(tx).DeclareUserExportCursor(ctx, includeDeleted)
+		if err != nil {
+			_ = tx.Rollback(context.WithoutCancel(ctx))
+		}
+	}
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to open the user export cursor",
+			"error", err,
+		)
+		return users.ExportUsers500JSONResponse{
+			InternalServerErrorJSONResponse: users.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	// The response body is written while the client reads it, so only one batch of rows is held in memory.
+	// If the client goes away the generated response closes the reader, which stops the writer.
+	body, writer := io.Pipe()
+	csvFormat := request.Params.Format != nil && *request.Params.Format == users.Csv
+	go func() {
+		err := streamUserExport(ctx, tx, writer, csvFormat)
+		if err != nil && !errors.Is(err, io.ErrClosedPipe) {
+			slog.Error("Failed to stream user export", "error", err)
+		}
+		writer.CloseWithError(err)
+	}()
+
+	if csvFormat {
+		return users.ExportUsers200TextcsvResponse{Body: body}, nil
+	}
+	return users.ExportUsers200ApplicationxNdjsonResponse{Body: body}, nil
+}
+
+// streamUserExport writes the rows of the export cursor and ends its transaction
+func streamUserExport(ctx context.Context, tx pgx.Tx, w io.Writer, csvFormat bool) error {
+	// The transaction is read-only, there is nothing to commit
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+	csvWriter := csv.NewWriter(buffered)
+	if csvFormat {
+		if err := csvWriter.Write(userExportColumns); err != nil {
+			return err
+		}
+	}
+
+	for {
+		rows, err := tx.Query(ctx, fetchUserExport)
+		if err != nil {
+			return err
+		}
+		dbUsers, err := pgx.CollectRows(rows, pgx.RowToStructByPos[repository.User])
+		if err != nil {
+			return err
+		}
+
+		for _, dbUser := range dbUsers {
+			if csvFormat {
+				err = csvWriter.Write(userExportRecord(dbUser))
+			} else {
+				err = encoder.Encode(toUser(dbUser))
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		// Send every batch to the client
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return err
+		}
+		if err := buffered.Flush(); err != nil {
+			return err
+		}
+
+		if len(dbUsers) == 0 {
+			return nil
+		}
+	}
+}
+
+// userExportRecord returns the CSV record of a user, matching userExportColumns
+func userExportRecord(dbUser repository.User) []string {
+	deletedAt := ""
+	if dbUser.DeletedAt.Valid {
+		deletedAt = dbUser.DeletedAt.Time.Format(time.RFC3339)
+	}
+	return []string{
+		dbUser.UserID.String(),
+		dbUser.Email.String,
+		dbUser.FirstName.String,
+		dbUser.LastName.String,
+		dbUser.Status,
+		deletedAt,
+	}
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"com.tom-ludwig/go-server-template/internal/api/users"
+	"com.tom-ludwig/go-server-template/internal/middleware"
+	"com.tom-ludwig/go-server-template/internal/outbox"
+	"com.tom-ludwig/go-server-template/internal/userevents"
+)
+
+// sseRetry is the reconnection delay clients are asked to use, in milliseconds
+const sseRetry = 3000
+
+func (u *UserHandler) StreamUserEvents(ctx context.Context, request users.StreamUserEventsRequestObject) (users.StreamUserEventsResponseObject, error) {
+	// Subscribe before the replay, events committed meanwhile are then in both and sent once
+	events, cancel := u.events.Listen()
+
+	var replay []userevents.Event
+	reset := false
+	if request.Params.LastEventID != nil {
+		// An unknown ID is treated like none, the client only receives new events
+		if after, err := uuid.Parse(*request.Params.LastEventID); err == nil {
+			replay, err = u.events.Replay(ctx, after)
+			if errors.Is(err, userevents.ErrReplayTooLong) {
+				reset = true
+			} else if err != nil {
+				cancel()
+				slog.Error(
+					"An error occurred while trying to replay user events",
+					"error", err,
+				)
+				return users.StreamUserEvents500JSONResponse{
+					InternalServerErrorJSONResponse: users.InternalServerErrorJSONResponse{
+						Message: "An internal server error occurred",
+					},
+				}, nil
+			}
+		}
+	}
+
+	stream := &userEventStream{
+		ctx:       ctx,
+		broker:    u.events,
+		events:    events,
+		cancel:    cancel,
+		replay:    replay,
+		reset:     reset,
+		heartbeat: u.heartbeatInterval,
+		admin:     u.adminScope == "" || middleware.HasScope(ctx, u.adminScope),
+	}
+	// The stream ends when the token expires, the client reconnects with a fresh one
+	if token, ok := middleware.GetToken(ctx); ok {
+		if expiration, ok := token.Expiration(); ok {
+			stream.expiration = expiration
+		}
+	}
+	return stream, nil
+}
+
+// userEventStream writes user events as Server-Sent Events until the client goes away,
+// the token expires or the server shuts down
+type userEventStream struct {
+	ctx    context.Context
+	broker *userevents.Broker
+	events <-chan userevents.Event
+	cancel func()
+	replay []userevents.Event
+	// reset tells the client it missed too many events to replay, it refetches the users
+	reset      bool
+	heartbeat  time.Duration
+	expiration time.Time
+	// admin callers receive the events of deleted users unredacted
+	admin bool
+}
+
+func (s *userEventStream) VisitStreamUserEventsResponse(w http.ResponseWriter) error {
+	defer s.cancel()
+
+	// The stream outlives the server's write timeout
+	controller := http.NewResponseController(w)
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil {
+		return fmt.Errorf("failed to disable the write deadline: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Reverse proxies must pass events through as they are written
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	buffered := bufio.NewWriter(w)
+	flush := func() error {
+		if err := buffered.Flush(); err != nil {
+			return err
+		}
+		return controller.Flush()
+	}
+
+	if _, err := fmt.Fprintf(buffered, "retry: %d\n\n", sseRetry); err != nil {
+		return err
+	}
+	if s.reset {
+		// The empty ID clears the client's Last-Event-ID, so reconnecting does not ask for the replay again
+		if _, err := buffered.WriteString("id\nevent: reset\ndata: {}\n\n"); err != nil {
+			return err
+		}
+	}
+	sent := map[uuid.UUID]struct{}{}
+	for _, event := range s.replay {
+		sent[event.ID] = struct{}{}
+		if err := s.write(buffered, event); err != nil {
+			return err
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	heartbeat := time.NewTicker(s.heartbeat)
+	defer heartbeat.Stop()
+	var expired <-chan time.Time
+	if !s.expiration.IsZero() {
+		timer := time.NewTimer(time.Until(s.expiration))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return nil
+		case <-s.broker.Done():
+			return nil
+		case <-expired:
+			return nil
+		case <-heartbeat.C:
+			if _, err := buffered.WriteString(": heartbeat\n\n"); err != nil {
+				return err
+			}
+		case event, ok := <-s.events:
+			if !ok {
+				// Too slow, the client resumes with Last-Event-ID
+				return nil
+			}
+			if _, ok := sent[event.ID]; ok {
+				continue
+			}
+			if err := s.write(buffered, event); err != nil {
+				return err
+			}
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *userEventStream) write(w *bufio.Writer, event userevents.Event) error {
+	data, err := userEventData(event, s.admin)
+	if err != nil {
+		return err
+	}
+	// The payload is compact JSON, it never contains a newline
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// userEventData returns the data of an event visible to the caller, only the ID of deleted users for non-admins
+func userEventData(event userevents.Event, admin bool) (json.RawMessage, error) {
+	if admin || !isDeletedUserEvent(event) {
+		return event.Data, nil
+	}
+	return json.Marshal(map[string]string{"user_id": event.UserID})
+}
+
+func isDeletedUserEvent(event userevents.Event) bool {
+	if event.Type == outbox.UserDeleted {
+		return true
+	}
+	var payload struct {
+		DeletedAt *time.Time `json:"deleted_at"`
+	}
+	return json.Unmarshal(event.Data, &payload) == nil && payload.DeletedAt != nil
+}
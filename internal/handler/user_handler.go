@@ -2,73 +2,128 @@ package handler
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"log/slog"
+	"net/http"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"com.tom-ludwig/go-server-template/internal/api/users"
+	"com.tom-ludwig/go-server-template/internal/audit"
+	"com.tom-ludwig/go-server-template/internal/middleware"
+	"com.tom-ludwig/go-server-template/internal/outbox"
 	"com.tom-ludwig/go-server-template/internal/repository"
+	"com.tom-ludwig/go-server-template/internal/userevents"
 )
 
 // compile-time check
 var _ users.StrictServerInterface = (*UserHandler)(nil)
 
 type UserHandler struct {
+	// DB is used for transactions, e.g. to record audit events with the mutation
+	DB      *pgxpool.Pool
 	Queries *repository.Queries
+	// adminScope is required for include_deleted. It is empty if authentication is disabled,
+	// then everyone may see deleted users, like the unprotected /admin endpoints.
+	adminScope string
+	// events streams user events, a heartbeat is sent every heartbeatInterval
+	events            *userevents.Broker
+	heartbeatInterval time.Duration
 }
 
-func NewUserHandler(queries *repository.Queries) *UserHandler {
+func NewUserHandler(db *pgxpool.Pool, queries *repository.Queries, adminScope string, events *userevents.Broker, heartbeatInterval time.Duration) *UserHandler {
 	return &UserHandler{
-		Queries: queries,
+		DB:                db,
+		Queries:           queries,
+		adminScope:        adminScope,
+		events:            events,
+		heartbeatInterval: heartbeatInterval,
 	}
 }
 
+// canIncludeDeleted reports whether the caller may request deleted users
+func (u *UserHandler) canIncludeDeleted(ctx context.Context, includeDeleted *bool) bool {
+	if includeDeleted == nil || !*includeDeleted || u.adminScope == "" {
+		return true
+	}
+	return middleware.HasScope(ctx, u.adminScope)
+}
+
 func (u *UserHandler) GetUser(ctx context.Context, request users.GetUserRequestObject) (users.GetUserResponseObject, error) {
 	userUUID, err := uuid.Parse(request.Params.UserId)
 	if err != nil {
 		return users.GetUser400JSONResponse{}, nil
 	}
-	user, err := u.Queries.GetUser(ctx, userUUID)
+	if !u.canIncludeDeleted(ctx, request.Params.IncludeDeleted) {
+		return users.GetUser403JSONResponse{
+			ForbiddenJSONResponse: users.ForbiddenJSONResponse{
+				Message: "include_deleted requires the admin scope",
+			},
+		}, nil
+	}
+
+	user, err := u.Queries.GetUser(ctx, repository.GetUserParams{
+		UserID:         userUUID,
+		IncludeDeleted: request.Params.IncludeDeleted != nil && *request.Params.IncludeDeleted,
+	})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return users.GetUser404JSONResponse{}, nil
+		if errors.Is(err, pgx.ErrNoRows) {
+			return users.GetUser404JSONResponse{Message: "user not found"}, nil
 		}
 
-		return users.GetUser404JSONResponse{}, nil
-		// return users.GetUser500JSONResponse{}, nil
+		slog.Error(
+			"An error occurred while trying to get a user",
+			"error", err,
+		)
+		return users.GetUser500JSONResponse{
+			InternalServerErrorJSONResponse: users.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+	etag := userETag(user)
+	if request.Params.IfNoneMatch != nil && etagListMatches(*request.Params.IfNoneMatch, etag, true) {
+		return users.GetUser304Response{Headers: users.GetUser304ResponseHeaders{ETag: etag}}, nil
 	}
+
 	return users.GetUser200JSONResponse{
-		UserId:    user.UserID.String(),
-		FirstName: user.FirstName.String,
-		LastName:  user.LastName.String,
-		Email:     user.Email.String,
+		Body:    toUser(user),
+		Headers: users.GetUser200ResponseHeaders{ETag: etag},
 	}, nil
 }
 
 func (u *UserHandler) CreateUser(ctx context.Context, request users.CreateUserRequestObject) (users.CreateUserResponseObject, error) {
-	newUser, err := u.Queries.CreateUser(ctx, repository.CreateUserParams{
-		FirstName: pgtype.Text{String: request.Body.FirstName, Valid: true},
-		LastName:  pgtype.Text{String: request.Body.LastName, Valid: true},
-		Email:     pgtype.Text{String: request.Body.Email, Valid: true},
+	var newUser repository.User
+	err := audit.Mutate(ctx, u.DB, func(q *repository.Queries) (audit.Event, error) {
+		var err error
+		newUser, err = q.CreateUser(ctx, repository.CreateUserParams{
+			FirstName: pgtype.Text{String: request.Body.FirstName, Valid: true},
+			LastName:  pgtype.Text{String: request.Body.LastName, Valid: true},
+			Email:     pgtype.Text{String: request.Body.Email, Valid: true},
+		})
+		if err == nil {
+			err = enqueueUserEvent(ctx, q, outbox.UserCreated, newUser.UserID)
+		}
+		return userEvent("user.create", nil, &newUser), err
 	})
 
 	if err != nil {
+		if isUniqueViolation(err, usersEmailIndex) {
+			return users.CreateUser409JSONResponse{Error: "email is already taken"}, nil
+		}
 		slog.Error(
 			"An error occurred while trying to create a user",
-			"Error: ", err,
+			"error", err,
 		)
-		return users.CreateUser500JSONResponse{}, nil
+		return users.CreateUser500JSONResponse{Message: "An internal server error occurred"}, nil
 	}
 
-	return users.CreateUser201JSONResponse{
-		UserId:    newUser.UserID.String(),
-		FirstName: newUser.FirstName.String,
-		LastName:  newUser.LastName.String,
-		Email:     newUser.Email.String,
-	}, nil
+	return users.CreateUser201JSONResponse(toUser(newUser)), nil
 }
 
 func (u *UserHandler) GetUsers(ctx context.Context, request users.GetUsersRequestObject) (users.GetUsersResponseObject, error) {
@@ -92,8 +147,17 @@ func (u *UserHandler) GetUsers(ctx context.Context, request users.GetUsersReques
 		}, nil
 	}
 
+	if !u.canIncludeDeleted(ctx, request.Params.IncludeDeleted) {
+		return users.GetUsers403JSONResponse{
+			ForbiddenJSONResponse: users.ForbiddenJSONResponse{
+				Message: "include_deleted requires the admin scope",
+			},
+		}, nil
+	}
+	includeDeleted := request.Params.IncludeDeleted != nil && *request.Params.IncludeDeleted
+
 	// Get total count of users
-	totalRecords, err := u.Queries.CountUsers(ctx)
+	totalRecords, err := u.Queries.CountUsers(ctx, includeDeleted)
 	if err != nil {
 		slog.Error(
 			"An error occurred while trying to count users",
@@ -129,8 +193,9 @@ func (u *UserHandler) GetUsers(ctx context.Context, request users.GetUsersReques
 
 	// Fetch users with pagination
 	queryParameters := repository.GetUsersParams{
-		Limit:  limit,
-		Offset: offset,
+		IncludeDeleted: includeDeleted,
+		MaxResults:     limit,
+		Skip:           offset,
 	}
 
 	dbUsers, err := u.Queries.GetUsers(ctx, queryParameters)
@@ -154,6 +219,8 @@ func (u *UserHandler) GetUsers(ctx context.Context, request users.GetUsersReques
 			FirstName: dbUser.FirstName.String,
 			LastName:  dbUser.LastName.String,
 			Email:     dbUser.Email.String,
+			Status:    users.UserStatus(dbUser.Status),
+			DeletedAt: timestamptzPtr(dbUser.DeletedAt),
 		})
 	}
 
@@ -169,3 +236,220 @@ func (u *UserHandler) GetUsers(ctx context.Context, request users.GetUsersReques
 		},
 	}, nil
 }
+
+func (u *UserHandler) GetMe(ctx context.Context, _ users.GetMeRequestObject) (users.GetMeResponseObject, error) {
+	user, err := u.activeUser(ctx)
+	if err != nil {
+		var failure meFailure
+		if errors.As(err, &failure) {
+			if failure.status == http.StatusUnauthorized {
+				return users.GetMe401JSONResponse{UnauthorizedJSONResponse: users.UnauthorizedJSONResponse{Message: failure.message}}, nil
+			}
+			return users.GetMe403JSONResponse{ForbiddenJSONResponse: users.ForbiddenJSONResponse{Message: failure.message}}, nil
+		}
+
+		slog.Error(
+			"An error occurred while trying to provision the current user",
+			"error", err,
+		)
+		return users.GetMe500JSONResponse{
+			InternalServerErrorJSONResponse: users.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	return users.GetMe200JSONResponse(toUser(user)), nil
+}
+
+func (u *UserHandler) UpdateMe(ctx context.Context, request users.UpdateMeRequestObject) (users.UpdateMeResponseObject, error) {
+	if request.Body.FirstName == nil && request.Body.LastName == nil {
+		return users.UpdateMe400JSONResponse{
+			BadRequestJSONResponse: users.BadRequestJSONResponse{
+				Message: "at least one of first_name and last_name must be set",
+			},
+		}, nil
+	}
+
+	user, err := u.activeUser(ctx)
+	if err == nil {
+		before := user
+		err = audit.Mutate(ctx, u.DB, func(q *repository.Queries) (audit.Event, error) {
+			var err error
+			user, err = q.UpdateUserProfile(ctx, repository.UpdateUserProfileParams{
+				UserID:    before.UserID,
+				FirstName: optionalText(request.Body.FirstName),
+				LastName:  optionalText(request.Body.LastName),
+			})
+			if err == nil {
+				err = enqueueUserEvent(ctx, q, outbox.UserUpdated, user.UserID)
+			}
+			return userEvent("user.update_profile", &before, &user), err
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Suspended, deactivated or deleted since it was read
+			err = meFailure{status: http.StatusForbidden, message: "user is no longer active"}
+		}
+	}
+	if err != nil {
+		var failure meFailure
+		if errors.As(err, &failure) {
+			if failure.status == http.StatusUnauthorized {
+				return users.UpdateMe401JSONResponse{UnauthorizedJSONResponse: users.UnauthorizedJSONResponse{Message: failure.message}}, nil
+			}
+			return users.UpdateMe403JSONResponse{ForbiddenJSONResponse: users.ForbiddenJSONResponse{Message: failure.message}}, nil
+		}
+
+		slog.Error(
+			"An error occurred while trying to update the current user",
+			"error", err,
+		)
+		return users.UpdateMe500JSONResponse{
+			InternalServerErrorJSONResponse: users.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	return users.UpdateMe200JSONResponse(toUser(user)), nil
+}
+
+// meFailure is returned by provisionUser when the caller cannot have a user row
+type meFailure struct {
+	status  int
+	message string
+}
+
+func (f meFailure) Error() string {
+	return f.message
+}
+
+// provisionUser returns the user of the calling token, creating it on the first request.
+// Concurrent first requests are safe, the upsert resolves the conflict on (issuer, external_subject).
+func (u *UserHandler) provisionUser(ctx context.Context) (repository.User, error) {
+	token, ok := middleware.GetToken(ctx)
+	if !ok {
+		return repository.User{}, meFailure{status: http.StatusUnauthorized, message: "not authenticated"}
+	}
+	subject, _ := token.Subject()
+	issuer, _ := token.Issuer()
+	if subject == "" || issuer == "" {
+		// API keys and client certificates are not users
+		return repository.User{}, meFailure{status: http.StatusForbidden, message: "only available to users signed in with the identity provider"}
+	}
+
+	email, _ := middleware.GetClaim[string](ctx, "email")
+	givenName, _ := middleware.GetClaim[string](ctx, "given_name")
+	familyName, _ := middleware.GetClaim[string](ctx, "family_name")
+
+	var user repository.User
+	err := audit.Mutate(ctx, u.DB, func(q *repository.Queries) (audit.Event, error) {
+		key := repository.GetUserBySubjectParams{
+			Issuer:          pgtype.Text{String: issuer, Valid: true},
+			ExternalSubject: pgtype.Text{String: subject, Valid: true},
+		}
+		var before *repository.User
+		previous, err := q.GetUserBySubject(ctx, key)
+		if err == nil {
+			before = &previous
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return audit.Event{}, err
+		}
+
+		user, err = q.UpsertUserBySubject(ctx, repository.UpsertUserBySubjectParams{
+			Issuer:          key.Issuer,
+			ExternalSubject: key.ExternalSubject,
+			Email:           optionalText(nonEmpty(email)),
+			FirstName:       optionalText(nonEmpty(givenName)),
+			LastName:        optionalText(nonEmpty(familyName)),
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Nothing changed, the upsert doesn't return unchanged rows. Read again, a concurrent
+			// first request may have created the user after the lookup above.
+			user, err = q.GetUserBySubject(ctx, key)
+			return audit.Event{}, err
+		}
+		if err != nil {
+			return audit.Event{}, err
+		}
+		eventType := outbox.UserUpdated
+		if before == nil {
+			eventType = outbox.UserCreated
+		}
+		return userEvent("user.provision", before, &user), enqueueUserEvent(ctx, q, eventType, user.UserID)
+	})
+	if isUniqueViolation(err, usersEmailIndex) {
+		return repository.User{}, meFailure{status: http.StatusForbidden, message: "the email of the token belongs to another user"}
+	}
+	if err != nil {
+		return repository.User{}, err
+	}
+	return user, nil
+}
+
+// activeUser returns the provisioned user of the caller, deleted and inactive users are forbidden
+func (u *UserHandler) activeUser(ctx context.Context) (repository.User, error) {
+	user, err := u.provisionUser(ctx)
+	if err != nil {
+		return repository.User{}, err
+	}
+	if user.DeletedAt.Valid || user.Status != string(users.Active) {
+		return repository.User{}, meFailure{status: http.StatusForbidden, message: "user is " + userState(user)}
+	}
+	return user, nil
+}
+
+// userState describes the lifecycle state of a user, e.g. for error messages
+func userState(user repository.User) string {
+	if user.DeletedAt.Valid {
+		return "deleted"
+	}
+	return user.Status
+}
+
+func toUser(dbUser repository.User) users.User {
+	return users.User{
+		UserId:    dbUser.UserID.String(),
+		FirstName: dbUser.FirstName.String,
+		LastName:  dbUser.LastName.String,
+		Email:     dbUser.Email.String,
+		Status:    users.UserStatus(dbUser.Status),
+		DeletedAt: timestamptzPtr(dbUser.DeletedAt),
+	}
+}
+
+// usersEmailIndex is the unique index on the email of users that are not deleted
+const usersEmailIndex = "users_email_idx"
+
+// isUniqueViolation reports whether err violates the unique index or constraint
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode && pgErr.ConstraintName == constraint
+}
+
+// enqueueUserEvent publishes the current state of the users through the outbox and notifies
+// the event streams of all replicas on commit. queries must use the transaction of the mutation.
+func enqueueUserEvent(ctx context.Context, queries *repository.Queries, eventType string, userIDs ...uuid.UUID) error {
+	err := queries.EnqueueUserEvents(ctx, repository.EnqueueUserEventsParams{
+		EventType: eventType,
+		UserIds:   userIDs,
+	})
+	if err != nil {
+		return err
+	}
+	return queries.Notify(ctx, repository.NotifyParams{Channel: userevents.Channel})
+}
+
+// userEvent builds the audit event of a user mutation, nil before or after for creations and deletions
+func userEvent(action string, before, after *repository.User) audit.Event {
+	event := audit.Event{Action: action, ResourceType: "user"}
+	if before != nil {
+		event.ResourceID = before.UserID.String()
+		event.Before = toUser(*before)
+	}
+	if after != nil {
+		event.ResourceID = after.UserID.String()
+		event.After = toUser(*after)
+	}
+	return event
+}
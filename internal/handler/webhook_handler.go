@@ -0,0 +1,425 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"com.tom-ludwig/go-server-template/internal/api/webhooks"
+	"com.tom-ludwig/go-server-template/internal/audit"
+	"com.tom-ludwig/go-server-template/internal/repository"
+	webhookdispatch "com.tom-ludwig/go-server-template/internal/webhooks"
+)
+
+// compile-time check
+var _ webhooks.StrictServerInterface = (*WebhookHandler)(nil)
+
+// errDeliveryInFlight is returned when a delivery that is being sent is redelivered
+var errDeliveryInFlight = errors.New("delivery is in flight")
+
+type WebhookHandler struct {
+	// DB is used to record audit events in the transaction of the mutation
+	DB      *pgxpool.Pool
+	Queries *repository.Queries
+}
+
+func NewWebhookHandler(db *pgxpool.Pool, queries *repository.Queries) *WebhookHandler {
+	return &WebhookHandler{
+		DB:      db,
+		Queries: queries,
+	}
+}
+
+func (h *WebhookHandler) ListWebhookSubscriptions(ctx context.Context, _ webhooks.ListWebhookSubscriptionsRequestObject) (webhooks.ListWebhookSubscriptionsResponseObject, error) {
+	dbSubscriptions, err := h.Queries.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to list webhook subscriptions",
+			"error", err,
+		)
+		return webhooks.ListWebhookSubscriptions500JSONResponse{
+			InternalServerErrorJSONResponse: webhooks.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	subscriptions := []webhooks.WebhookSubscription{}
+	for _, dbSubscription := range dbSubscriptions {
+		subscriptions = append(subscriptions, toWebhookSubscription(dbSubscription))
+	}
+
+	return webhooks.ListWebhookSubscriptions200JSONResponse{
+		Data: subscriptions,
+	}, nil
+}
+
+func (h *WebhookHandler) CreateWebhookSubscription(ctx context.Context, request webhooks.CreateWebhookSubscriptionRequestObject) (webhooks.CreateWebhookSubscriptionResponseObject, error) {
+	endpoint, err := url.Parse(request.Body.Url)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return webhooks.CreateWebhookSubscription400JSONResponse{
+			BadRequestJSONResponse: webhooks.BadRequestJSONResponse{
+				Message: "url must be an absolute http or https URL",
+			},
+		}, nil
+	}
+
+	secret := ""
+	if request.Body.Secret != nil {
+		secret = *request.Body.Secret
+		if err := webhookdispatch.ValidateSecret(secret); err != nil {
+			return webhooks.CreateWebhookSubscription400JSONResponse{
+				BadRequestJSONResponse: webhooks.BadRequestJSONResponse{
+					Message: err.Error(),
+				},
+			}, nil
+		}
+	} else if secret, err = webhookdispatch.GenerateSecret(); err != nil {
+		slog.Error(
+			"An error occurred while trying to generate a webhook secret",
+			"error", err,
+		)
+		return webhooks.CreateWebhookSubscription500JSONResponse{
+			InternalServerErrorJSONResponse: webhooks.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	// No event types subscribes to all events
+	eventTypes := []string{}
+	if request.Body.EventTypes != nil {
+		for _, eventType := range *request.Body.EventTypes {
+			eventTypes = append(eventTypes, string(eventType))
+		}
+	}
+
+	maxConcurrency := 4
+	if request.Body.MaxConcurrency != nil {
+		maxConcurrency = *request.Body.MaxConcurrency
+	}
+
+	var dbSubscription repository.WebhookSubscription
+	err = audit.Mutate(ctx, h.DB, func(q *repository.Queries) (audit.Event, error) {
+		var err error
+		dbSubscription, err = q.CreateWebhookSubscription(ctx, repository.CreateWebhookSubscriptionParams{
+			Url:            endpoint.String(),
+			EventTypes:     eventTypes,
+			Secret:         secret,
+			MaxConcurrency: int32(maxConcurrency),
+		})
+		return webhookSubscriptionEvent("webhook.create", nil, &dbSubscription), err
+	})
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to create a webhook subscription",
+			"error", err,
+		)
+		return webhooks.CreateWebhookSubscription500JSONResponse{
+			InternalServerErrorJSONResponse: webhooks.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	slog.Info("webhook subscription created", "subscription_id", dbSubscription.SubscriptionID, "url", dbSubscription.Url)
+
+	subscription := toWebhookSubscription(dbSubscription)
+	return webhooks.CreateWebhookSubscription201JSONResponse{
+		SubscriptionId: subscription.SubscriptionId,
+		Url:            subscription.Url,
+		EventTypes:     subscription.EventTypes,
+		MaxConcurrency: subscription.MaxConcurrency,
+		Secret:         dbSubscription.Secret,
+		CreatedAt:      subscription.CreatedAt,
+	}, nil
+}
+
+func (h *WebhookHandler) DeleteWebhookSubscription(ctx context.Context, request webhooks.DeleteWebhookSubscriptionRequestObject) (webhooks.DeleteWebhookSubscriptionResponseObject, error) {
+	err := audit.Mutate(ctx, h.DB, func(q *repository.Queries) (audit.Event, error) {
+		dbSubscription, err := q.DeleteWebhookSubscription(ctx, request.SubscriptionId)
+		return webhookSubscriptionEvent("webhook.delete", &dbSubscription, nil), err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return webhooks.DeleteWebhookSubscription404JSONResponse{
+				NotFoundJSONResponse: webhooks.NotFoundJSONResponse{
+					Message: "webhook subscription not found",
+				},
+			}, nil
+		}
+
+		slog.Error(
+			"An error occurred while trying to delete a webhook subscription",
+			"error", err,
+		)
+		return webhooks.DeleteWebhookSubscription500JSONResponse{
+			InternalServerErrorJSONResponse: webhooks.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	slog.Info("webhook subscription deleted", "subscription_id", request.SubscriptionId)
+
+	return webhooks.DeleteWebhookSubscription204Response{}, nil
+}
+
+func (h *WebhookHandler) ListWebhookDeliveries(ctx context.Context, request webhooks.ListWebhookDeliveriesRequestObject) (webhooks.ListWebhookDeliveriesResponseObject, error) {
+	limit := 20
+	if request.Params.Limit != nil {
+		limit = *request.Params.Limit
+	}
+
+	var cursor pgtype.UUID
+	if request.Params.Cursor != nil {
+		deliveryID, err := uuid.Parse(*request.Params.Cursor)
+		if err != nil {
+			return webhooks.ListWebhookDeliveries400JSONResponse{
+				BadRequestJSONResponse: webhooks.BadRequestJSONResponse{
+					Message: "invalid cursor",
+				},
+			}, nil
+		}
+		cursor = pgtype.UUID{Bytes: deliveryID, Valid: true}
+	}
+
+	var status pgtype.Text
+	if request.Params.Status != nil {
+		status = pgtype.Text{String: string(*request.Params.Status), Valid: true}
+	}
+
+	if _, err := h.Queries.GetWebhookSubscription(ctx, request.SubscriptionId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return webhooks.ListWebhookDeliveries404JSONResponse{
+				NotFoundJSONResponse: webhooks.NotFoundJSONResponse{
+					Message: "webhook subscription not found",
+				},
+			}, nil
+		}
+
+		slog.Error(
+			"An error occurred while trying to get a webhook subscription",
+			"error", err,
+		)
+		return webhooks.ListWebhookDeliveries500JSONResponse{
+			InternalServerErrorJSONResponse: webhooks.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	// Fetch one more delivery to know whether there is a next page
+	dbDeliveries, err := h.Queries.ListWebhookDeliveries(ctx, repository.ListWebhookDeliveriesParams{
+		SubscriptionID: request.SubscriptionId,
+		Status:         status,
+		Cursor:         cursor,
+		MaxResults:     int32(limit + 1),
+	})
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to list webhook deliveries",
+			"error", err,
+		)
+		return webhooks.ListWebhookDeliveries500JSONResponse{
+			InternalServerErrorJSONResponse: webhooks.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	var nextCursor *string
+	if len(dbDeliveries) > limit {
+		dbDeliveries = dbDeliveries[:limit]
+		nextCursor = ptr(dbDeliveries[limit-1].DeliveryID.String())
+	}
+
+	deliveries := []webhooks.WebhookDelivery{}
+	for _, dbDelivery := range dbDeliveries {
+		deliveries = append(deliveries, toWebhookDelivery(dbDelivery))
+	}
+
+	return webhooks.ListWebhookDeliveries200JSONResponse{
+		Data:       deliveries,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func (h *WebhookHandler) GetWebhookDelivery(ctx context.Context, request webhooks.GetWebhookDeliveryRequestObject) (webhooks.GetWebhookDeliveryResponseObject, error) {
+	dbDelivery, err := h.Queries.GetWebhookDelivery(ctx, request.DeliveryId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return webhooks.GetWebhookDelivery404JSONResponse{
+				NotFoundJSONResponse: webhooks.NotFoundJSONResponse{
+					Message: "webhook delivery not found",
+				},
+			}, nil
+		}
+
+		slog.Error(
+			"An error occurred while trying to get a webhook delivery",
+			"error", err,
+		)
+		return webhooks.GetWebhookDelivery500JSONResponse{
+			InternalServerErrorJSONResponse: webhooks.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	dbAttempts, err := h.Queries.ListWebhookDeliveryAttempts(ctx, request.DeliveryId)
+	if err != nil {
+		slog.Error(
+			"An error occurred while trying to list webhook delivery attempts",
+			"error", err,
+		)
+		return webhooks.GetWebhookDelivery500JSONResponse{
+			InternalServerErrorJSONResponse: webhooks.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(dbDelivery.Payload, &payload); err != nil {
+		slog.Error(
+			"An error occurred while trying to decode a webhook payload",
+			"delivery_id", dbDelivery.DeliveryID,
+			"error", err,
+		)
+		return webhooks.GetWebhookDelivery500JSONResponse{
+			InternalServerErrorJSONResponse: webhooks.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	attempts := []webhooks.WebhookDeliveryAttempt{}
+	for _, dbAttempt := range dbAttempts {
+		attempt := webhooks.WebhookDeliveryAttempt{
+			Error:       textPtr(dbAttempt.Error),
+			DurationMs:  int(dbAttempt.DurationMs),
+			AttemptedAt: dbAttempt.AttemptedAt,
+		}
+		if dbAttempt.StatusCode.Valid {
+			attempt.StatusCode = ptr(int(dbAttempt.StatusCode.Int32))
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	delivery := toWebhookDelivery(dbDelivery)
+	return webhooks.GetWebhookDelivery200JSONResponse{
+		DeliveryId:     delivery.DeliveryId,
+		SubscriptionId: delivery.SubscriptionId,
+		EventId:        delivery.EventId,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+		Payload:        payload,
+		AttemptsLog:    attempts,
+	}, nil
+}
+
+func (h *WebhookHandler) RedeliverWebhookDelivery(ctx context.Context, request webhooks.RedeliverWebhookDeliveryRequestObject) (webhooks.RedeliverWebhookDeliveryResponseObject, error) {
+	var dbDelivery repository.WebhookDelivery
+	err := audit.Mutate(ctx, h.DB, func(q *repository.Queries) (audit.Event, error) {
+		before, err := q.GetWebhookDelivery(ctx, request.DeliveryId)
+		if err != nil {
+			return audit.Event{}, err
+		}
+		dbDelivery, err = q.RedeliverWebhookDelivery(ctx, request.DeliveryId)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return audit.Event{}, errDeliveryInFlight
+		}
+		return audit.Event{
+			Action:       "webhook.redeliver",
+			ResourceType: "webhook_delivery",
+			ResourceID:   dbDelivery.DeliveryID.String(),
+			Before:       toWebhookDelivery(before),
+			After:        toWebhookDelivery(dbDelivery),
+		}, err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return webhooks.RedeliverWebhookDelivery404JSONResponse{
+				NotFoundJSONResponse: webhooks.NotFoundJSONResponse{
+					Message: "webhook delivery not found",
+				},
+			}, nil
+		}
+		if errors.Is(err, errDeliveryInFlight) {
+			return webhooks.RedeliverWebhookDelivery409JSONResponse{
+				ConflictJSONResponse: webhooks.ConflictJSONResponse{
+					Message: "webhook delivery is being sent, try again later",
+				},
+			}, nil
+		}
+
+		slog.Error(
+			"An error occurred while trying to redeliver a webhook delivery",
+			"error", err,
+		)
+		return webhooks.RedeliverWebhookDelivery500JSONResponse{
+			InternalServerErrorJSONResponse: webhooks.InternalServerErrorJSONResponse{
+				Message: "An internal server error occurred",
+			},
+		}, nil
+	}
+
+	slog.Info("webhook delivery scheduled for redelivery", "delivery_id", dbDelivery.DeliveryID)
+
+	return webhooks.RedeliverWebhookDelivery202JSONResponse(toWebhookDelivery(dbDelivery)), nil
+}
+
+// toWebhookSubscription converts a database subscription to the API representation, leaving out the secret
+func toWebhookSubscription(dbSubscription repository.WebhookSubscription) webhooks.WebhookSubscription {
+	eventTypes := []webhooks.WebhookEventType{}
+	for _, eventType := range dbSubscription.EventTypes {
+		eventTypes = append(eventTypes, webhooks.WebhookEventType(eventType))
+	}
+	return webhooks.WebhookSubscription{
+		SubscriptionId: dbSubscription.SubscriptionID,
+		Url:            dbSubscription.Url,
+		EventTypes:     eventTypes,
+		MaxConcurrency: int(dbSubscription.MaxConcurrency),
+		CreatedAt:      dbSubscription.CreatedAt,
+	}
+}
+
+func toWebhookDelivery(dbDelivery repository.WebhookDelivery) webhooks.WebhookDelivery {
+	return webhooks.WebhookDelivery{
+		DeliveryId:     dbDelivery.DeliveryID,
+		SubscriptionId: dbDelivery.SubscriptionID,
+		EventId:        dbDelivery.EventID,
+		EventType:      dbDelivery.EventType,
+		Status:         webhooks.WebhookDeliveryStatus(dbDelivery.Status),
+		Attempts:       int(dbDelivery.Attempts),
+		NextAttemptAt:  dbDelivery.NextAttemptAt,
+		LastError:      textPtr(dbDelivery.LastError),
+		CreatedAt:      dbDelivery.CreatedAt,
+	}
+}
+
+// webhookSubscriptionEvent builds the audit event of a subscription mutation, the secret is never recorded
+func webhookSubscriptionEvent(action string, before, after *repository.WebhookSubscription) audit.Event {
+	event := audit.Event{Action: action, ResourceType: "webhook_subscription"}
+	if before != nil {
+		event.ResourceID = before.SubscriptionID.String()
+		event.Before = toWebhookSubscription(*before)
+	}
+	if after != nil {
+		event.ResourceID = after.SubscriptionID.String()
+		event.After = toWebhookSubscription(*after)
+	}
+	return event
+}
@@ -0,0 +1,383 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+
+	"com.tom-ludwig/go-server-template/internal/metrics"
+	"com.tom-ludwig/go-server-template/internal/middleware"
+	"com.tom-ludwig/go-server-template/internal/userevents"
+)
+
+const (
+	// WebSocketSubprotocol is the message protocol of the endpoint
+	WebSocketSubprotocol = "user-events.v1"
+	// webSocketTokenPrefix marks the subprotocol carrying the access token, e.g. "bearer.eyJ..."
+	webSocketTokenPrefix = "bearer."
+
+	webSocketReadLimit        = 4096
+	webSocketWriteTimeout     = 10 * time.Second
+	webSocketMaxSubscriptions = 100
+
+	// Topics clients subscribe to, all users or one user as users/<user_id>
+	topicUsers       = "users"
+	topicUsersPrefix = "users/"
+)
+
+// webSocketRequest is a message of the client
+type webSocketRequest struct {
+	Type  string `json:"type"`
+	ID    string `json:"id,omitempty"`
+	Topic string `json:"topic,omitempty"`
+	Token string `json:"token,omitempty"`
+}
+
+// webSocketMessage is a message of the server, replies carry the ID of the request
+type webSocketMessage struct {
+	Type    string          `json:"type"`
+	ID      string          `json:"id,omitempty"`
+	Topic   string          `json:"topic,omitempty"`
+	Message string          `json:"message,omitempty"`
+	EventID string          `json:"event_id,omitempty"`
+	Event   string          `json:"event,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// WebSocketHandler serves subscriptions to user events over a WebSocket. Clients authenticate like
+// any request, with the access token as "bearer.<token>" subprotocol, or with an auth message first.
+type WebSocketHandler struct {
+	authenticators []middleware.Authenticator
+	// jwtAuth validates tokens of the subprotocol and the auth message, they are rejected if it is nil
+	jwtAuth *middleware.JWTAuth
+	events  *userevents.Broker
+	// adminScope is required to receive the events of deleted users unredacted, empty if authentication is disabled
+	adminScope     string
+	originPatterns []string
+
+	sendBuffer   int
+	authTimeout  time.Duration
+	pingInterval time.Duration
+}
+
+func NewWebSocketHandler(authenticators []middleware.Authenticator, jwtAuth *middleware.JWTAuth, events *userevents.Broker, adminScope string, allowedOrigins []string, sendBuffer int, authTimeout, pingInterval time.Duration) *WebSocketHandler {
+	return &WebSocketHandler{
+		authenticators: authenticators,
+		jwtAuth:        jwtAuth,
+		events:         events,
+		adminScope:     adminScope,
+		originPatterns: originPatterns(allowedOrigins),
+		sendBuffer:     sendBuffer,
+		authTimeout:    authTimeout,
+		pingInterval:   pingInterval,
+	}
+}
+
+// originPatterns turns the allowed CORS origins into the host patterns checked by Accept
+func originPatterns(allowedOrigins []string) []string {
+	patterns := make([]string, 0, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if parsed, err := url.Parse(origin); err == nil && parsed.Host != "" {
+			origin = parsed.Host
+		}
+		patterns = append(patterns, origin)
+	}
+	return patterns
+}
+
+func (h *WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, authenticated, err := h.authenticate(r)
+	if err != nil {
+		middleware.WriteAuthError(w, r, err)
+		return
+	}
+	if !authenticated && h.jwtAuth == nil {
+		middleware.WriteAuthError(w, r, &middleware.AuthError{Status: http.StatusUnauthorized, Message: "missing authorization header"})
+		return
+	}
+
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		Subprotocols:   []string{WebSocketSubprotocol},
+		OriginPatterns: h.originPatterns,
+	})
+	if err != nil {
+		// Accept wrote the response
+		slog.Debug("Failed to accept WebSocket connection", "error", err)
+		return
+	}
+	conn.SetReadLimit(webSocketReadLimit)
+
+	// Without credentials in the request, the first message must authenticate
+	if !authenticated {
+		ctx, err = h.authenticateMessage(ctx, conn)
+		if err != nil {
+			_ = conn.Close(websocket.StatusPolicyViolation, err.Error())
+			return
+		}
+	}
+
+	metrics.WebSocketConnections.Add(1)
+	metrics.WebSocketConnectionsTotal.Add(1)
+	defer metrics.WebSocketConnections.Add(-1)
+
+	session := &webSocketSession{
+		conn:   conn,
+		admin:  h.adminScope == "" || middleware.HasScope(ctx, h.adminScope),
+		send:   make(chan webSocketMessage, h.sendBuffer),
+		topics: map[string]struct{}{},
+		closed: make(chan struct{}),
+	}
+	status, reason := session.run(ctx, h.events, h.pingInterval)
+	_ = conn.Close(status, reason)
+}
+
+// authenticate tries the authenticators and the token of the subprotocol. It reports false without error
+// if the request has no credentials, which is fine if authentication is disabled.
+func (h *WebSocketHandler) authenticate(r *http.Request) (context.Context, bool, error) {
+	if len(h.authenticators) == 0 {
+		return r.Context(), true, nil
+	}
+	for _, authenticator := range h.authenticators {
+		ctx, err := authenticator.Authenticate(r)
+		if errors.Is(err, middleware.ErrNoCredentials) {
+			continue
+		}
+		return ctx, err == nil, err
+	}
+
+	if token, ok := subprotocolToken(r); ok && h.jwtAuth != nil {
+		ctx, err := h.jwtAuth.AuthenticateToken(r.Context(), token)
+		return ctx, err == nil, err
+	}
+	return r.Context(), false, nil
+}
+
+// subprotocolToken returns the access token offered as "bearer.<token>" subprotocol,
+// the only request header browsers let WebSocket clients set
+func subprotocolToken(r *http.Request) (string, bool) {
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for protocol := range strings.SplitSeq(header, ",") {
+			if token, ok := strings.CutPrefix(strings.TrimSpace(protocol), webSocketTokenPrefix); ok {
+				return token, true
+			}
+		}
+	}
+	return "", false
+}
+
+// authenticateMessage reads the auth message the client must send within the auth timeout
+func (h *WebSocketHandler) authenticateMessage(ctx context.Context, conn *websocket.Conn) (context.Context, error) {
+	readCtx, cancel := context.WithTimeout(ctx, h.authTimeout)
+	defer cancel()
+
+	_, data, err := conn.Read(readCtx)
+	if err != nil {
+		return nil, errors.New("authentication required")
+	}
+	var request webSocketRequest
+	if err := json.Unmarshal(data, &request); err != nil || request.Type != "auth" || request.Token == "" {
+		return nil, errors.New("the first message must be an auth message with a token")
+	}
+
+	authCtx, err := h.jwtAuth.AuthenticateToken(ctx, request.Token)
+	if err != nil {
+		var authErr *middleware.AuthError
+		if errors.As(err, &authErr) {
+			return nil, authErr
+		}
+		slog.Error("WebSocket authentication failed", "error", err)
+		return nil, errors.New("authentication failed")
+	}
+
+	if err := writeWebSocketMessage(ctx, conn, webSocketMessage{Type: "ack", ID: request.ID}); err != nil {
+		return nil, err
+	}
+	return authCtx, nil
+}
+
+// webSocketSession is an authenticated connection. Messages to the client are queued in a bounded buffer,
+// a client that does not read them fast enough is disconnected.
+type webSocketSession struct {
+	conn  *websocket.Conn
+	admin bool
+	send  chan webSocketMessage
+
+	mu     sync.Mutex
+	topics map[string]struct{}
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	status    websocket.StatusCode
+	reason    string
+}
+
+// run serves the session until it is closed and returns the close status
+func (s *webSocketSession) run(ctx context.Context, broker *userevents.Broker, pingInterval time.Duration) (websocket.StatusCode, string) {
+	events, cancel := broker.Listen()
+	defer cancel()
+
+	go s.read(ctx)
+
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+	// The session ends when the token expires, the client reconnects with a fresh one
+	var expired <-chan time.Time
+	if token, ok := middleware.GetToken(ctx); ok {
+		if expiration, ok := token.Expiration(); ok {
+			timer := time.NewTimer(time.Until(expiration))
+			defer timer.Stop()
+			expired = timer.C
+		}
+	}
+
+	for {
+		select {
+		case <-s.closed:
+			return s.status, s.reason
+		case <-ctx.Done():
+			return websocket.StatusGoingAway, "request canceled"
+		case <-broker.Done():
+			return websocket.StatusGoingAway, "server shutting down"
+		case <-expired:
+			return websocket.StatusPolicyViolation, "token expired"
+		case <-ping.C:
+			// Ping waits for the pong, a client that does not answer is gone
+			pingCtx, cancelPing := context.WithTimeout(ctx, webSocketWriteTimeout)
+			err := s.conn.Ping(pingCtx)
+			cancelPing()
+			if err != nil {
+				return websocket.StatusGoingAway, "ping timed out"
+			}
+		case event, ok := <-events:
+			if !ok {
+				// The broker dropped the connection, it fell behind the events
+				metrics.WebSocketSlowClosed.Add(1)
+				return websocket.StatusTryAgainLater, "too slow"
+			}
+			s.publish(event)
+		case message := <-s.send:
+			if err := writeWebSocketMessage(ctx, s.conn, message); err != nil {
+				return websocket.StatusGoingAway, "write failed"
+			}
+		}
+	}
+}
+
+// read handles the messages of the client until the connection fails
+func (s *webSocketSession) read(ctx context.Context) {
+	for {
+		_, data, err := s.conn.Read(ctx)
+		if err != nil {
+			s.close(websocket.StatusNormalClosure, "")
+			return
+		}
+
+		var request webSocketRequest
+		if err := json.Unmarshal(data, &request); err != nil {
+			s.queue(webSocketMessage{Type: "error", Message: "invalid message"})
+			continue
+		}
+		s.handle(request)
+	}
+}
+
+func (s *webSocketSession) handle(request webSocketRequest) {
+	switch request.Type {
+	case "ping":
+		s.queue(webSocketMessage{Type: "pong", ID: request.ID})
+	case "subscribe", "unsubscribe":
+		topic, ok := parseTopic(request.Topic)
+		if !ok {
+			s.queue(webSocketMessage{Type: "error", ID: request.ID, Message: "unknown topic, use users or users/{user_id}"})
+			return
+		}
+
+		s.mu.Lock()
+		if request.Type == "unsubscribe" {
+			delete(s.topics, topic)
+		} else if len(s.topics) < webSocketMaxSubscriptions {
+			s.topics[topic] = struct{}{}
+		} else {
+			s.mu.Unlock()
+			s.queue(webSocketMessage{Type: "error", ID: request.ID, Message: "too many subscriptions"})
+			return
+		}
+		s.mu.Unlock()
+		s.queue(webSocketMessage{Type: "ack", ID: request.ID, Topic: topic})
+	default:
+		s.queue(webSocketMessage{Type: "error", ID: request.ID, Message: "unknown message type"})
+	}
+}
+
+// parseTopic validates a topic and returns it in canonical form
+func parseTopic(topic string) (string, bool) {
+	if topic == topicUsers {
+		return topic, true
+	}
+	if userID, ok := strings.CutPrefix(topic, topicUsersPrefix); ok {
+		if parsed, err := uuid.Parse(userID); err == nil {
+			return topicUsersPrefix + parsed.String(), true
+		}
+	}
+	return "", false
+}
+
+// publish queues the event for each subscribed topic it belongs to
+func (s *webSocketSession) publish(event userevents.Event) {
+	s.mu.Lock()
+	var topics []string
+	for _, topic := range []string{topicUsers, topicUsersPrefix + event.UserID} {
+		if _, ok := s.topics[topic]; ok {
+			topics = append(topics, topic)
+		}
+	}
+	s.mu.Unlock()
+	if len(topics) == 0 {
+		return
+	}
+
+	data, err := userEventData(event, s.admin)
+	if err != nil {
+		slog.Error("Failed to encode user event", "event_id", event.ID, "error", err)
+		return
+	}
+	for _, topic := range topics {
+		s.queue(webSocketMessage{Type: "event", Topic: topic, EventID: event.ID.String(), Event: event.Type, Data: data})
+	}
+}
+
+// queue adds a message to the send buffer, the session is closed if the buffer is full
+func (s *webSocketSession) queue(message webSocketMessage) {
+	select {
+	case s.send <- message:
+	default:
+		metrics.WebSocketSlowClosed.Add(1)
+		s.close(websocket.StatusTryAgainLater, "send buffer full")
+	}
+}
+
+func (s *webSocketSession) close(status websocket.StatusCode, reason string) {
+	s.closeOnce.Do(func() {
+		s.status, s.reason = status, reason
+		close(s.closed)
+	})
+}
+
+func writeWebSocketMessage(ctx context.Context, conn *websocket.Conn, message webSocketMessage) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	writeCtx, cancel := context.WithTimeout(ctx, webSocketWriteTimeout)
+	defer cancel()
+	return conn.Write(writeCtx, websocket.MessageText, data)
+}
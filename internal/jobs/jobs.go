@@ -0,0 +1,291 @@
+// Package jobs runs background jobs stored in the jobs table. Jobs are enqueued in the caller's transaction,
+// so a job exists if and only if the change that needs it was committed, and run at least once by a worker.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+const (
+	defaultMaxAttempts = 10
+	defaultTimeout     = time.Minute
+
+	minRetryDelay = time.Second
+	maxRetryDelay = time.Hour
+)
+
+// Kind is a job type, T is the type of its arguments. Declare it once and use it to register and enqueue:
+//
+//	var SendWelcomeMail = jobs.Kind[WelcomeMailArgs]("mail.welcome")
+type Kind[T any] string
+
+// Handler runs a job. A returned error fails the attempt, the job is retried until it reaches its max attempts.
+type Handler[T any] func(ctx context.Context, args T) error
+
+// Options configure how jobs of a kind run
+type Options struct {
+	// Timeout is the deadline of an attempt, defaults to one minute
+	Timeout time.Duration
+}
+
+// EnqueueOptions configure a single job
+type EnqueueOptions struct {
+	// RunAt schedules the job, it runs right away if zero
+	RunAt time.Time
+	// UniqueKey skips the job if an available or running job of the kind has the same key
+	UniqueKey string
+	// MaxAttempts defaults to 10
+	MaxAttempts int32
+}
+
+// Enqueue adds a job and reports whether it was added, false if a job with the same unique key exists.
+// Pass the queries of the caller's transaction to enqueue the job with the change.
+func Enqueue[T any](ctx context.Context, queries *repository.Queries, kind Kind[T], args T, options EnqueueOptions) (bool, error) {
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode args of job %s: %w", kind, err)
+	}
+
+	params := repository.InsertJobParams{
+		Kind:        string(kind),
+		Args:        encoded,
+		MaxAttempts: options.MaxAttempts,
+		RunAt:       options.RunAt,
+	}
+	if params.MaxAttempts <= 0 {
+		params.MaxAttempts = defaultMaxAttempts
+	}
+	if params.RunAt.IsZero() {
+		params.RunAt = time.Now()
+	}
+	if options.UniqueKey != "" {
+		params.UniqueKey = pgtype.Text{String: options.UniqueKey, Valid: true}
+	}
+
+	inserted, err := queries.InsertJob(ctx, params)
+	if err != nil {
+		return false, fmt.Errorf("failed to enqueue job %s: %w", kind, err)
+	}
+	return inserted > 0, nil
+}
+
+type registration struct {
+	run     func(ctx context.Context, args []byte) error
+	timeout time.Duration
+}
+
+// Worker runs the jobs of its registered kinds. Every replica and worker process can run a worker,
+// jobs are claimed with FOR UPDATE SKIP LOCKED.
+type Worker struct {
+	queries         *repository.Queries
+	concurrency     int
+	shutdownTimeout time.Duration
+	retention       time.Duration
+
+	kinds map[string]registration
+	slots chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewWorker creates a worker running up to concurrency jobs at once. On shutdown, running jobs get
+// shutdownTimeout to finish before they are canceled. Finished jobs are kept for retention.
+func NewWorker(queries *repository.Queries, concurrency int, shutdownTimeout, retention time.Duration) *Worker {
+	return &Worker{
+		queries:         queries,
+		concurrency:     concurrency,
+		shutdownTimeout: shutdownTimeout,
+		retention:       retention,
+		kinds:           map[string]registration{},
+		slots:           make(chan struct{}, concurrency),
+	}
+}
+
+// Register makes the worker run jobs of kind with handler. It must be called before Run.
+func Register[T any](w *Worker, kind Kind[T], handler Handler[T], options Options) {
+	if options.Timeout <= 0 {
+		options.Timeout = defaultTimeout
+	}
+	w.kinds[string(kind)] = registration{
+		run: func(ctx context.Context, encoded []byte) error {
+			var args T
+			if err := json.Unmarshal(encoded, &args); err != nil {
+				return fmt.Errorf("failed to decode args: %w", err)
+			}
+			return handler(ctx, args)
+		},
+		timeout: options.Timeout,
+	}
+}
+
+// Run claims due jobs every interval until ctx is done, then waits for the running jobs.
+// Jobs still running after the shutdown timeout are canceled and released without counting the attempt.
+func (w *Worker) Run(ctx context.Context, interval time.Duration) {
+	if len(w.kinds) == 0 {
+		return
+	}
+
+	// Jobs outlive ctx by the shutdown timeout
+	jobCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+	stopped := context.AfterFunc(ctx, func() {
+		time.AfterFunc(w.shutdownTimeout, cancelJobs)
+	})
+	defer stopped()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Waiting for running jobs", "timeout", w.shutdownTimeout)
+			w.wg.Wait()
+			return
+		case <-ticker.C:
+			if err := w.claim(ctx, jobCtx); err != nil && ctx.Err() == nil {
+				slog.Error("Failed to claim jobs", "error", err)
+			}
+
+			if time.Since(lastCleanup) >= time.Hour {
+				lastCleanup = time.Now()
+				w.cleanup(ctx)
+			}
+		}
+	}
+}
+
+// claim leases as many due jobs as the worker has free slots and starts them
+func (w *Worker) claim(ctx, jobCtx context.Context) error {
+	free := w.concurrency - len(w.slots)
+	if free <= 0 {
+		return nil
+	}
+
+	// The lease outlives the longest timeout, an expired lease means the worker stopped
+	kinds := make([]string, 0, len(w.kinds))
+	var lease time.Duration
+	for kind, registration := range w.kinds {
+		kinds = append(kinds, kind)
+		lease = max(lease, registration.timeout)
+	}
+	slices.Sort(kinds)
+
+	jobs, err := w.queries.ClaimJobs(ctx, repository.ClaimJobsParams{
+		LockedUntil: pgtype.Timestamptz{Time: time.Now().Add(lease + w.shutdownTimeout + time.Minute), Valid: true},
+		Kinds:       kinds,
+		MaxResults:  int32(free),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		w.slots <- struct{}{}
+		w.wg.Add(1)
+		go func() {
+			defer func() {
+				<-w.slots
+				w.wg.Done()
+			}()
+			w.work(jobCtx, job)
+		}()
+	}
+	return nil
+}
+
+// work runs a job once and records the outcome
+func (w *Worker) work(ctx context.Context, job repository.Job) {
+	registration := w.kinds[job.Kind]
+	start := time.Now()
+
+	attemptCtx, cancel := context.WithTimeout(ctx, registration.timeout)
+	runErr := run(attemptCtx, registration, job.Args)
+	cancel()
+
+	if runErr != nil && ctx.Err() != nil {
+		// Canceled by the shutdown, another worker runs it again
+		if err := w.queries.ReleaseJob(context.WithoutCancel(ctx), job.JobID); err != nil {
+			slog.Error("Failed to release job", "job_id", job.JobID, "kind", job.Kind, "error", err)
+		}
+		return
+	}
+	// Record the outcome even if the shutdown timeout passes meanwhile
+	ctx = context.WithoutCancel(ctx)
+
+	var err error
+	switch {
+	case runErr == nil:
+		slog.Debug("Job completed", "job_id", job.JobID, "kind", job.Kind, "duration", time.Since(start))
+		err = w.queries.CompleteJob(ctx, job.JobID)
+	case job.Attempts >= job.MaxAttempts:
+		slog.Error("Job failed permanently", "job_id", job.JobID, "kind", job.Kind, "attempts", job.Attempts, "error", runErr)
+		err = w.queries.DiscardJob(ctx, repository.DiscardJobParams{
+			LastError: pgtype.Text{String: runErr.Error(), Valid: true},
+			JobID:     job.JobID,
+		})
+	default:
+		delay := retryDelay(job.Attempts)
+		slog.Warn("Job failed", "job_id", job.JobID, "kind", job.Kind, "attempts", job.Attempts, "retry_in", delay, "error", runErr)
+		err = w.queries.RetryJob(ctx, repository.RetryJobParams{
+			LastError: pgtype.Text{String: runErr.Error(), Valid: true},
+			RunAt:     time.Now().Add(delay),
+			JobID:     job.JobID,
+		})
+	}
+	if err != nil {
+		slog.Error("Failed to update job", "job_id", job.JobID, "kind", job.Kind, "error", err)
+	}
+}
+
+// run calls the handler, turning a panic into an error so it fails only the attempt
+func run(ctx context.Context, registration registration, args []byte) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			slog.Error("Job panicked", "panic", recovered, "stack", string(debug.Stack()))
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	err = registration.run(ctx, args)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("job timed out after %s: %w", registration.timeout, err)
+	}
+	return err
+}
+
+// cleanup deletes finished jobs older than the retention
+func (w *Worker) cleanup(ctx context.Context) {
+	deleted, err := w.queries.DeleteFinishedJobs(ctx, pgtype.Timestamptz{Time: time.Now().Add(-w.retention), Valid: true})
+	if err != nil {
+		slog.Error("Failed to delete finished jobs", "error", err)
+		return
+	}
+	if deleted > 0 {
+		slog.Debug("Deleted finished jobs", "count", deleted)
+	}
+}
+
+// retryDelay doubles the delay with every failed attempt, up to maxRetryDelay
+func retryDelay(attempts int32) time.Duration {
+	delay := minRetryDelay
+	for range attempts - 1 {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,25 @@
+// Package metrics publishes the application's metrics with expvar, they are served by GET /admin/metrics
+// together with the runtime's memstats and cmdline.
+package metrics
+
+import "expvar"
+
+var (
+	// WebSocketConnections is the number of open WebSocket connections
+	WebSocketConnections = expvar.NewInt("websocket_connections")
+	// WebSocketConnectionsTotal counts the accepted WebSocket connections
+	WebSocketConnectionsTotal = expvar.NewInt("websocket_connections_total")
+	// WebSocketSlowClosed counts connections closed because the client did not keep up with its events
+	WebSocketSlowClosed = expvar.NewInt("websocket_slow_closed_total")
+
+	// ConcurrencyLimit is the current adaptive limit of requests processed at once
+	ConcurrencyLimit = expvar.NewInt("concurrency_limit")
+	// ConcurrencyInflight is the number of requests counted against the limit
+	ConcurrencyInflight = expvar.NewInt("concurrency_inflight")
+	// ConcurrencyQueued is the number of requests waiting for a slot
+	ConcurrencyQueued = expvar.NewInt("concurrency_queued")
+	// ConcurrencyLongRunningInflight is the number of long-running requests, they have a pool of their own
+	ConcurrencyLongRunningInflight = expvar.NewInt("concurrency_long_running_inflight")
+	// ConcurrencyRejectedTotal counts the requests shed with 503
+	ConcurrencyRejectedTotal = expvar.NewInt("concurrency_rejected_total")
+)
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+const (
+	// APIKeyHeader is the header machine clients can use instead of "Authorization: ApiKey <key>"
+	APIKeyHeader = "X-API-Key"
+	// APIKeyIDClaim is the claim holding the key ID on tokens created for API key callers
+	APIKeyIDClaim = "api_key_id"
+
+	// apiKeyTag is the first segment of every key, it makes leaked keys easy to find with secret scanners
+	apiKeyTag = "gst"
+)
+
+// APIKeyAuth authenticates machine clients with API keys stored in the api_keys table
+type APIKeyAuth struct {
+	queries *repository.Queries
+}
+
+// NewAPIKeyAuth creates a new API key authenticator
+func NewAPIKeyAuth(queries *repository.Queries) *APIKeyAuth {
+	return &APIKeyAuth{
+		queries: queries,
+	}
+}
+
+// GenerateAPIKey creates a new random API key.
+// It returns the plaintext key, which must only be shown once, the public prefix used for lookups and the hash to store.
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	prefixBytes := make([]byte, 8)
+	if _, err = rand.Read(prefixBytes); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate key prefix: %w", err)
+	}
+	secretBytes := make([]byte, 32)
+	if _, err = rand.Read(secretBytes); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate key secret: %w", err)
+	}
+
+	prefix = hex.EncodeToString(prefixBytes)
+	secret := hex.EncodeToString(secretBytes)
+	return fmt.Sprintf("%s_%s_%s", apiKeyTag, prefix, secret), prefix, hashAPIKeySecret(secret), nil
+}
+
+// hashAPIKeySecret hashes the secret part of a key. The secret has 256 bits of entropy, so a fast hash is sufficient.
+func hashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// splitAPIKey splits a key into its prefix and secret
+func splitAPIKey(key string) (prefix, secret string, ok bool) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || parts[0] != apiKeyTag || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// Middleware returns the HTTP middleware handler
+func (a *APIKeyAuth) Middleware(next http.Handler) http.Handler {
+	return Authenticate(a)(next)
+}
+
+// Authenticate implements Authenticator for "Authorization: ApiKey <key>" and the X-API-Key header
+func (a *APIKeyAuth) Authenticate(r *http.Request) (context.Context, error) {
+	key, ok := authorizationCredentials(r, "apikey")
+	if !ok {
+		key = strings.TrimSpace(r.Header.Get(APIKeyHeader))
+	}
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+
+	invalid := &AuthError{Status: http.StatusUnauthorized, Message: "invalid api key."}
+
+	prefix, secret, ok := splitAPIKey(key)
+	if !ok {
+		return nil, invalid
+	}
+
+	apiKey, err := a.queries.GetAPIKeyByPrefix(r.Context(), prefix)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, invalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up api key: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashAPIKeySecret(secret)), []byte(apiKey.KeyHash)) != 1 {
+		return nil, invalid
+	}
+	if apiKey.RevokedAt.Valid {
+		return nil, invalid
+	}
+	if apiKey.ExpiresAt.Valid && !apiKey.ExpiresAt.Time.After(time.Now()) {
+		return nil, &AuthError{Status: http.StatusUnauthorized, Message: "api key expired."}
+	}
+
+	if err := a.queries.TouchAPIKey(r.Context(), apiKey.KeyID); err != nil {
+		slog.Warn("failed to update api key last_used_at", "key_id", apiKey.KeyID, "error", err)
+	}
+
+	token, err := newPrincipalToken(apiKey.Owner, map[string]any{
+		"scope":       strings.Join(apiKey.Scopes, " "),
+		APIKeyIDClaim: apiKey.KeyID.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build api key principal: %w", err)
+	}
+
+	return contextWithToken(r.Context(), token), nil
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+)
+
+// ErrNoCredentials is returned by an Authenticator when the request carries no credentials it understands
+var ErrNoCredentials = errors.New("no credentials")
+
+// AuthError is returned by an Authenticator when credentials are present but rejected
+type AuthError struct {
+	Status  int
+	Message string
+	// Challenge is sent as WWW-Authenticate header if set
+	Challenge string
+}
+
+func (e *AuthError) Error() string {
+	return e.Message
+}
+
+// Authenticator validates the credentials of a request
+type Authenticator interface {
+	// Authenticate returns a context carrying the caller's identity (see GetToken and GetSubject).
+	// It returns ErrNoCredentials if the request has no credentials for this authenticator.
+	Authenticate(r *http.Request) (context.Context, error)
+}
+
+// Authenticate returns a middleware that tries each authenticator in order.
+// The first authenticator that finds credentials decides whether the request is accepted.
+func Authenticate(authenticators ...Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, authenticator := range authenticators {
+				ctx, err := authenticator.Authenticate(r)
+				if errors.Is(err, ErrNoCredentials) {
+					continue
+				}
+				if err != nil {
+					WriteAuthError(w, r, err)
+					return
+				}
+
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			writeError(w, r, http.StatusUnauthorized, "missing authorization header")
+		})
+	}
+}
+
+// WriteAuthError writes the response of credentials an Authenticator rejected
+func WriteAuthError(w http.ResponseWriter, r *http.Request, err error) {
+	var authErr *AuthError
+	if errors.As(err, &authErr) {
+		if authErr.Challenge != "" {
+			w.Header().Set("WWW-Authenticate", authErr.Challenge)
+		}
+		writeError(w, r, authErr.Status, authErr.Message)
+		return
+	}
+
+	slog.Error("authentication failed", "error", err)
+	writeError(w, r, http.StatusInternalServerError, "Internal Server Error while Authorizing")
+}
+
+// contextWithToken stores the token and its subject in the context the same way for every authenticator,
+// so GetToken, GetSubject, RequireScope and RequireRole work regardless of how the caller authenticated
+func contextWithToken(ctx context.Context, token jwt.Token) context.Context {
+	ctx = context.WithValue(ctx, ClaimsContextKey, token)
+	if sub, ok := token.Subject(); ok {
+		ctx = context.WithValue(ctx, SubjectContextKey, sub)
+	}
+	return ctx
+}
+
+// principalKey identifies the authenticated caller as "key:<id>" for API keys or "sub:<issuer>|<subject>" otherwise,
+// so the same subject from different issuers or credential types never shares state. It is false without a caller.
+func principalKey(ctx context.Context) (string, bool) {
+	if keyID, ok := GetClaim[string](ctx, APIKeyIDClaim); ok && keyID != "" {
+		return "key:" + keyID, true
+	}
+	token, ok := GetToken(ctx)
+	if !ok {
+		return "", false
+	}
+	subject, ok := token.Subject()
+	if !ok || subject == "" {
+		return "", false
+	}
+	issuer, _ := token.Issuer()
+	return "sub:" + issuer + "|" + subject, true
+}
+
+// newPrincipalToken builds an unsigned token for callers that did not authenticate with a JWT
+func newPrincipalToken(subject string, claims map[string]any) (jwt.Token, error) {
+	token := jwt.New()
+	if err := token.Set(jwt.SubjectKey, subject); err != nil {
+		return nil, err
+	}
+	for name, value := range claims {
+		if err := token.Set(name, value); err != nil {
+			return nil, fmt.Errorf("failed to set claim %s: %w", name, err)
+		}
+	}
+	return token, nil
+}
+
+// authorizationCredentials returns the credentials of the Authorization header if it uses the given scheme
+func authorizationCredentials(r *http.Request, scheme string) (string, bool) {
+	parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], scheme) {
+		return "", false
+	}
+	return strings.TrimSpace(parts[1]), true
+}
+
+// HasCredentials reports whether the request carries credentials for any authenticator, without checking them
+func HasCredentials(r *http.Request) bool {
+	if r.Header.Get("Authorization") != "" || r.Header.Get(APIKeyHeader) != "" {
+		return true
+	}
+	return r.TLS != nil && len(r.TLS.VerifiedChains) > 0
+}
+
+// OAPIAuthenticationFunc returns an authentication function for the oapi-codegen request validator.
+// The biggest downside to this approach is that you cannot easily chain additional middlewares like RequireScope or RequireRole after it,
+// so instead of authenticating it only checks that the credentials of the security scheme are present.
+// This just ensures oapi doesn't reject the request, the Authenticate middleware does the actual validation.
+func OAPIAuthenticationFunc() openapi3filter.AuthenticationFunc {
+	return func(_ context.Context, input *openapi3filter.AuthenticationInput) error {
+		req := input.RequestValidationInput.Request
+		scheme := input.SecurityScheme
+
+		if scheme != nil && scheme.Type == "apiKey" && scheme.In == "header" {
+			if req.Header.Get(scheme.Name) == "" {
+				return fmt.Errorf("missing %s header", scheme.Name)
+			}
+			return nil
+		}
+
+		if req.Header.Get("Authorization") == "" {
+			return errors.New("missing authorization header")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,252 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+)
+
+// BodyRule is the request body policy of an operation
+type BodyRule struct {
+	// Limit is the maximum size in bytes, zero uses the default limit
+	Limit int64
+	// MediaTypes are the accepted Content-Types, "*/*" accepts any. Without media types any body is accepted.
+	MediaTypes []string
+	// Schemas are the schemas of the JSON media types. Objects reject unknown fields unless their schema
+	// allows additionalProperties or declares no properties at all.
+	Schemas map[string]*openapi3.Schema
+	// Stream passes the body to the handler without reading it, only its size is limited
+	Stream bool
+}
+
+// BodyLimitOptions configure BodyLimit
+type BodyLimitOptions struct {
+	DefaultLimit int64
+	// MaxDepth is the maximum nesting of JSON objects and arrays
+	MaxDepth int
+	// Routes are the rules of single routes, written as "METHOD /pattern" like they are registered
+	Routes map[string]BodyRule
+}
+
+// BodyLimit limits the size of request bodies and checks them before the request validator reads them:
+// 413 for bodies larger than the limit, 415 for Content-Types the operation does not accept, and 400 for
+// JSON bodies with duplicate fields, unknown fields or too deep nesting.
+func BodyLimit(opts BodyLimitOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rule := opts.Routes[r.Method+" "+chi.RouteContext(r.Context()).RoutePattern()]
+			limit := rule.Limit
+			if limit <= 0 {
+				limit = opts.DefaultLimit
+			}
+			if r.ContentLength > limit {
+				writeError(w, r, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body must not be larger than %d bytes.", limit))
+				return
+			}
+
+			mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if len(rule.MediaTypes) > 0 && !slices.Contains(rule.MediaTypes, "*/*") && !slices.Contains(rule.MediaTypes, mediaType) {
+				writeError(w, r, http.StatusUnsupportedMediaType, "Content-Type must be "+strings.Join(rule.MediaTypes, " or ")+".")
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			if rule.Stream || !isJSONMediaType(mediaType) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeError(w, r, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body must not be larger than %d bytes.", limit))
+				return
+			}
+			if err != nil {
+				writeError(w, r, http.StatusBadRequest, "failed to read request body.")
+				return
+			}
+			if err := checkJSON(body, rule.Schemas[mediaType], opts.MaxDepth); err != nil {
+				writeError(w, r, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// jsonFrame is an object or array being decoded
+type jsonFrame struct {
+	object bool
+	path   string
+	// schema is nil if the content is not checked
+	schema *openapi3.Schema
+	// keys are the fields seen so far, key and value are the name and schema of the current one
+	keys      map[string]struct{}
+	expectKey bool
+	key       string
+	value     *openapi3.Schema
+}
+
+// checkJSON reads the tokens of body and reports duplicate fields, unknown fields and nesting beyond maxDepth.
+// Syntax errors are left to the request validator, which reports them with more context.
+func checkJSON(body []byte, schema *openapi3.Schema, maxDepth int) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var stack []*jsonFrame
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			// io.EOF at the end of the value, other errors are syntax errors
+			return nil
+		}
+
+		var parent *jsonFrame
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1]
+		}
+
+		if parent != nil && parent.object && parent.expectKey {
+			if token == json.Delim('}') {
+				stack = closeFrame(stack)
+				continue
+			}
+			key, _ := token.(string)
+			if _, ok := parent.keys[key]; ok {
+				return fmt.Errorf("duplicate field %q in request body", joinPath(parent.path, key))
+			}
+			parent.keys[key] = struct{}{}
+			parent.expectKey = false
+			parent.key = key
+
+			value, known := objectField(parent.schema, key)
+			if !known {
+				return fmt.Errorf("unknown field %q in request body", joinPath(parent.path, key))
+			}
+			parent.value = value
+			continue
+		}
+
+		if token == json.Delim(']') {
+			stack = closeFrame(stack)
+			continue
+		}
+
+		// A value, of the root, an array or after a key
+		path, valueSchema := "", schema
+		if parent != nil {
+			path, valueSchema = parent.path, parent.value
+			if parent.object {
+				path = joinPath(parent.path, parent.key)
+				parent.expectKey = true
+			} else {
+				path += "[]"
+			}
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			if len(stack) >= maxDepth {
+				return fmt.Errorf("request body must not be nested deeper than %d levels", maxDepth)
+			}
+			frame := &jsonFrame{object: token == json.Delim('{'), path: path, schema: valueSchema}
+			if frame.object {
+				frame.keys = map[string]struct{}{}
+				frame.expectKey = true
+			} else if valueSchema != nil && valueSchema.Items != nil {
+				frame.value = valueSchema.Items.Value
+			}
+			if parent != nil && parent.object {
+				// The parent expects the next key once this value is closed
+				parent.expectKey = false
+			}
+			stack = append(stack, frame)
+		}
+	}
+}
+
+// closeFrame pops the innermost frame, its parent expects the next key if it is an object
+func closeFrame(stack []*jsonFrame) []*jsonFrame {
+	stack = stack[:len(stack)-1]
+	if len(stack) > 0 && stack[len(stack)-1].object {
+		stack[len(stack)-1].expectKey = true
+	}
+	return stack
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// objectField returns the schema of a field of an object and whether the field is allowed.
+// Fields of allOf, anyOf and oneOf members are allowed as well.
+func objectField(schema *openapi3.Schema, key string) (*openapi3.Schema, bool) {
+	if schema == nil {
+		return nil, true
+	}
+	value, allowed, declared := lookupField(schema, key)
+	if !declared {
+		// A free-form object
+		return nil, true
+	}
+	return value, allowed
+}
+
+// lookupField reports the schema of the field, whether it is allowed, and whether the schema declares its fields
+func lookupField(schema *openapi3.Schema, key string) (value *openapi3.Schema, allowed, declared bool) {
+	if property, ok := schema.Properties[key]; ok && property != nil {
+		return property.Value, true, true
+	}
+	declared = len(schema.Properties) > 0
+	additional := schema.AdditionalProperties
+	if additional.Schema != nil && additional.Schema.Value != nil {
+		return additional.Schema.Value, true, true
+	}
+	if additional.Has != nil {
+		if *additional.Has {
+			return nil, true, true
+		}
+		declared = true
+	}
+
+	for _, refs := range []openapi3.SchemaRefs{schema.AllOf, schema.AnyOf, schema.OneOf} {
+		for _, ref := range refs {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			memberValue, memberAllowed, memberDeclared := lookupField(ref.Value, key)
+			if !memberDeclared {
+				continue
+			}
+			declared = true
+			if memberAllowed {
+				return memberValue, true, true
+			}
+		}
+	}
+	return nil, false, declared
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+)
+
+// closedObject is an object schema that rejects undeclared fields
+func closedObject(properties map[string]*openapi3.Schema) *openapi3.Schema {
+	schema := openapi3.NewObjectSchema()
+	for name, property := range properties {
+		schema.WithProperty(name, property)
+	}
+	schema.WithoutAdditionalProperties()
+	return schema
+}
+
+func TestCheckJSON(t *testing.T) {
+	user := closedObject(map[string]*openapi3.Schema{
+		"email":   openapi3.NewStringSchema(),
+		"address": closedObject(map[string]*openapi3.Schema{"city": openapi3.NewStringSchema()}),
+	})
+	users := openapi3.NewArraySchema().WithItems(user)
+	operations := closedObject(map[string]*openapi3.Schema{
+		"Operations": openapi3.NewArraySchema().WithItems(closedObject(map[string]*openapi3.Schema{
+			"op": openapi3.NewStringSchema(),
+		})),
+	})
+
+	allOf := openapi3.NewAllOfSchema(
+		closedObject(map[string]*openapi3.Schema{"email": openapi3.NewStringSchema()}),
+		closedObject(map[string]*openapi3.Schema{"status": openapi3.NewStringSchema()}),
+	)
+	oneOf := openapi3.NewOneOfSchema(
+		closedObject(map[string]*openapi3.Schema{"email": openapi3.NewStringSchema()}),
+		closedObject(map[string]*openapi3.Schema{"phone": openapi3.NewStringSchema()}),
+	)
+	extensible := openapi3.NewObjectSchema().
+		WithProperty("email", openapi3.NewStringSchema()).
+		WithAdditionalProperties(openapi3.NewStringSchema())
+	freeForm := closedObject(map[string]*openapi3.Schema{
+		"email":    openapi3.NewStringSchema(),
+		"metadata": openapi3.NewObjectSchema(),
+	})
+
+	tests := []struct {
+		name     string
+		body     string
+		schema   *openapi3.Schema
+		maxDepth int
+		wantErr  string
+	}{
+		{"valid", `{"email": "a@example.com", "address": {"city": "Berlin"}}`, user, 8, ""},
+		{"duplicate at root", `{"email": "a", "email": "b"}`, user, 8, `duplicate field "email"`},
+		{"duplicate at depth", `{"address": {"city": "a", "city": "b"}}`, user, 8, `duplicate field "address.city"`},
+		{"same key in siblings", `[{"email": "a"}, {"email": "b"}]`, users, 8, ""},
+		{"unknown at root", `{"email": "a", "bogus": 1}`, user, 8, `unknown field "bogus"`},
+		{"unknown at depth", `{"address": {"zip": "10115"}}`, user, 8, `unknown field "address.zip"`},
+		{"unknown in array of objects", `{"Operations": [{"op": "add"}, {"op": "add", "bogus": 1}]}`, operations, 8, `unknown field "Operations[].bogus"`},
+		{"unknown in top-level array", `[{"email": "a"}, {"emial": "b"}]`, users, 8, `unknown field "[].emial"`},
+		{"allOf member field", `{"email": "a", "status": "active"}`, allOf, 8, ""},
+		{"allOf unknown", `{"email": "a", "bogus": 1}`, allOf, 8, `unknown field "bogus"`},
+		{"oneOf member field", `{"phone": "+49"}`, oneOf, 8, ""},
+		{"oneOf unknown", `{"bogus": 1}`, oneOf, 8, `unknown field "bogus"`},
+		{"additionalProperties schema", `{"email": "a", "nickname": "b"}`, extensible, 8, ""},
+		{"free-form object", `{"metadata": {"anything": {"goes": [1, 2]}}}`, freeForm, 8, ""},
+		{"free-form object duplicate", `{"metadata": {"a": 1, "a": 2}}`, freeForm, 8, `duplicate field "metadata.a"`},
+		{"without schema", `{"anything": {"goes": true}}`, nil, 8, ""},
+		{"exactly at max depth", `{"a": {"b": [1]}}`, nil, 3, ""},
+		{"over max depth", `{"a": {"b": [[1]]}}`, nil, 3, "nested deeper than 3 levels"},
+		{"arrays over max depth", `[[[]]]`, nil, 2, "nested deeper than 2 levels"},
+		{"truncated body", `{"email": "a", "address": {"city"`, user, 8, ""},
+		{"invalid body", `{"email": "a",, "bogus": 1}`, user, 8, ""},
+		{"trailing garbage", `{"email": "a"} }`, user, 8, ""},
+		{"error before truncation", `{"bogus": 1, "email": `, user, 8, `unknown field "bogus"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkJSON([]byte(tt.body), tt.schema, tt.maxDepth)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkJSON(%s) error = %v, want nil", tt.body, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkJSON(%s) error = %v, want %q", tt.body, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	schema := closedObject(map[string]*openapi3.Schema{"email": openapi3.NewStringSchema()})
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		// Reading the body reaches the limit of streamed bodies
+		if _, err := io.ReadAll(r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+	router := chi.NewRouter()
+	router.Group(func(r chi.Router) {
+		r.Use(BodyLimit(BodyLimitOptions{
+			DefaultLimit: 64,
+			MaxDepth:     8,
+			Routes: map[string]BodyRule{
+				"POST /users": {
+					MediaTypes: []string{"application/json"},
+					Schemas:    map[string]*openapi3.Schema{"application/json": schema},
+				},
+				"POST /users:import": {
+					Limit:      1024,
+					MediaTypes: []string{"text/csv"},
+					Stream:     true,
+				},
+			},
+		}))
+		r.Post("/users", handler)
+		r.Post("/users:import", handler)
+	})
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+		// chunked sends the body without Content-Length
+		chunked    bool
+		wantStatus int
+	}{
+		{"valid", "/users", "application/json", `{"email": "a@example.com"}`, false, http.StatusNoContent},
+		{"over limit", "/users", "application/json", `{"email": "` + strings.Repeat("a", 64) + `"}`, false, http.StatusRequestEntityTooLarge},
+		{"over limit chunked", "/users", "application/json", `{"email": "` + strings.Repeat("a", 64) + `"}`, true, http.StatusRequestEntityTooLarge},
+		{"within limit chunked", "/users", "application/json", `{"email": "a"}`, true, http.StatusNoContent},
+		{"unsupported media type", "/users", "text/plain", `hello`, false, http.StatusUnsupportedMediaType},
+		{"media type parameters", "/users", "application/json; charset=utf-8", `{"email": "a"}`, false, http.StatusNoContent},
+		{"unknown field", "/users", "application/json", `{"bogus": 1}`, false, http.StatusBadRequest},
+		{"duplicate field", "/users", "application/json", `{"email": "a", "email": "b"}`, false, http.StatusBadRequest},
+		{"invalid JSON passes to the validator", "/users", "application/json", `{"email": `, false, http.StatusNoContent},
+		{"route limit", "/users:import", "text/csv", strings.Repeat("a,b\n", 100), false, http.StatusNoContent},
+		{"streamed over route limit chunked", "/users:import", "text/csv", strings.Repeat("a,b\n", 300), true, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+			if tt.chunked {
+				r.ContentLength = -1
+				r.TransferEncoding = []string{"chunked"}
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ClientCertAuth authenticates callers by the verified TLS client certificate.
+// The certificate identity (URI, DNS and email SANs, then the subject common name) is mapped to roles via config.
+type ClientCertAuth struct {
+	roles      map[string][]string
+	rolesClaim string
+}
+
+// NewClientCertAuth creates a new client certificate authenticator
+// roles: maps a certificate identity to its roles
+// rolesClaim: the claim the roles are stored in, use the same name with RequireRole
+func NewClientCertAuth(roles map[string][]string, rolesClaim string) *ClientCertAuth {
+	return &ClientCertAuth{
+		roles:      roles,
+		rolesClaim: rolesClaim,
+	}
+}
+
+// Middleware returns the HTTP middleware handler
+func (c *ClientCertAuth) Middleware(next http.Handler) http.Handler {
+	return Authenticate(c)(next)
+}
+
+// Authenticate implements Authenticator for verified client certificates.
+// Certificates that are valid but not mapped authenticate a principal without roles.
+func (c *ClientCertAuth) Authenticate(r *http.Request) (context.Context, error) {
+	// Only trust certificates the TLS stack verified against the client CA bundle
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, ErrNoCredentials
+	}
+	cert := r.TLS.VerifiedChains[0][0]
+
+	identities := certIdentities(cert)
+	if len(identities) == 0 {
+		return nil, &AuthError{Status: http.StatusUnauthorized, Message: "client certificate has no identity."}
+	}
+
+	// The first mapped identity wins, otherwise the most specific identity becomes the subject
+	subject := identities[0]
+	roles := []string{}
+	for _, identity := range identities {
+		if mapped, ok := c.roles[identity]; ok {
+			subject = identity
+			roles = mapped
+			break
+		}
+	}
+
+	// Roles are exposed as scopes too, so RequireScope works for certificate callers
+	token, err := newPrincipalToken(subject, map[string]any{
+		c.rolesClaim: roles,
+		"scope":      strings.Join(roles, " "),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build client certificate principal: %w", err)
+	}
+
+	return contextWithToken(r.Context(), token), nil
+}
+
+// certIdentities returns the identities of a certificate, most specific first
+func certIdentities(cert *x509.Certificate) []string {
+	var identities []string
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+	identities = append(identities, cert.DNSNames...)
+	identities = append(identities, cert.EmailAddresses...)
+	if cert.Subject.CommonName != "" {
+		identities = append(identities, cert.Subject.CommonName)
+	}
+	return identities
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		want           string
+	}{
+		{"empty", "", ""},
+		{"single", "gzip", "gzip"},
+		{"unsupported", "deflate, compress", ""},
+		{"tie prefers zstd", "gzip, br, zstd", "zstd"},
+		{"tie prefers br over gzip", "gzip, br", "br"},
+		{"highest q wins", "zstd;q=0.5, gzip;q=0.8", "gzip"},
+		{"q=0 excludes", "zstd;q=0, br;q=0, gzip", "gzip"},
+		{"all excluded", "gzip;q=0", ""},
+		{"wildcard", "*", "zstd"},
+		{"wildcard with exclusion", "*, zstd;q=0", "br"},
+		{"wildcard below explicit", "*;q=0.1, gzip;q=0.5", "gzip"},
+		{"wildcard excluded", "*;q=0", ""},
+		{"case insensitive", "GZIP;Q=0.5", "gzip"},
+		{"spaces", " br ; q=0.9 , gzip ; q=0.8 ", "br"},
+		{"malformed q keeps 1", "gzip;q=high, br;q=0.5", "gzip"},
+		{"empty q keeps 1", "br;q=", "br"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := negotiateEncoding(tt.acceptEncoding); got != tt.want {
+				t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.acceptEncoding, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompressedETag(t *testing.T) {
+	tests := []struct {
+		name     string
+		etag     string
+		encoding string
+		want     string
+	}{
+		{"strong", `"3"`, "gzip", `"3-gzip"`},
+		{"weak", `W/"3"`, "br", `W/"3"`},
+		{"malformed", `3`, "zstd", `3`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := compressedETag(tt.etag, tt.encoding)
+			if got != tt.want {
+				t.Errorf("compressedETag(%q, %q) = %q, want %q", tt.etag, tt.encoding, got, tt.want)
+			}
+			if base := UncompressedETag(got); base != tt.etag {
+				t.Errorf("UncompressedETag(%q) = %q, want %q", got, base, tt.etag)
+			}
+		})
+	}
+}
+
+func TestCompressETag(t *testing.T) {
+	body := bytes.Repeat([]byte(`{"user_id":"0198f3a2-7c4e-7b1a-9d3e-5f6a7b8c9d0e"},`), 100)
+	handler := Compress(CompressOptions{MinSize: 1024, ContentTypes: []string{"application/json"}})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("ETag", `"3"`)
+			_, _ = w.Write(body)
+		}),
+	)
+
+	tests := []struct {
+		acceptEncoding string
+		wantEncoding   string
+		wantETag       string
+	}{
+		{"", "", `"3"`},
+		{"gzip", "gzip", `"3-gzip"`},
+		{"br, zstd", "zstd", `"3-zstd"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/user", nil)
+			r.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if got := w.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if got := w.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", got, tt.wantETag)
+			}
+		})
+	}
+}
+
+func BenchmarkCompress(b *testing.B) {
+	for _, encoding := range compressEncodings {
+		for _, size := range []int{1 << 10, 16 << 10, 256 << 10} {
+			b.Run(fmt.Sprintf("%s/%dKiB", encoding, size>>10), func(b *testing.B) {
+				chunk := []byte(`{"user_id":"0198f3a2-7c4e-7b1a-9d3e-5f6a7b8c9d0e","email":"jane@example.com","status":"active"},`)
+				body := bytes.Repeat(chunk, size/len(chunk)+1)[:size]
+				handler := Compress(CompressOptions{MinSize: 512, ContentTypes: []string{"application/json"}})(
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						w.Header().Set("Content-Type", "application/json")
+						_, _ = w.Write(body)
+					}),
+				)
+				r := httptest.NewRequest(http.MethodGet, "/users", nil)
+				r.Header.Set("Accept-Encoding", encoding)
+
+				b.ReportAllocs()
+				b.SetBytes(int64(size))
+				for b.Loop() {
+					w := httptest.NewRecorder()
+					handler.ServeHTTP(w, r)
+					if w.Header().Get("Content-Encoding") != encoding {
+						b.Fatalf("Content-Encoding = %q, want %q", w.Header().Get("Content-Encoding"), encoding)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package middleware
+
+import (
+	"container/list"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"com.tom-ludwig/go-server-template/internal/metrics"
+)
+
+// Priority decides which requests are shed first when the server is overloaded
+type Priority int
+
+const (
+	// PriorityCritical requests are never queued nor shed and do not count against the limit,
+	// e.g. health checks, admin endpoints and long-lived streams
+	PriorityCritical Priority = iota
+	// PriorityNormal requests are queued when the limit is reached
+	PriorityNormal
+	// PriorityLow requests are queued behind normal ones and shed first when the queue is full
+	PriorityLow
+	// PriorityLongRunning requests, e.g. bulk operations with a long timeout, run in a pool of their own.
+	// They neither count against the adaptive limit nor adapt it with their latency, and are shed at once
+	// when the pool is full.
+	PriorityLongRunning
+)
+
+const (
+	// concurrencyBackoff is the factor the limit is multiplied with when requests are too slow
+	concurrencyBackoff = 0.9
+	// concurrencyRetryAfter is sent with shed requests, in seconds
+	concurrencyRetryAfter = 1
+)
+
+// ConcurrencyLimitOptions configure a ConcurrencyLimiter
+type ConcurrencyLimitOptions struct {
+	InitialLimit, MinLimit, MaxLimit int
+	// LatencyTarget is the latency above which the server is considered overloaded
+	LatencyTarget time.Duration
+	// QueueSize requests wait up to QueueTimeout for a slot when the limit is reached
+	QueueSize    int
+	QueueTimeout time.Duration
+	// LongRunningLimit is the fixed number of PriorityLongRunning requests processed at once
+	LongRunningLimit int
+}
+
+// ConcurrencyLimiter limits the requests processed at once. The limit adapts to the observed latency
+// with additive increase and multiplicative decrease: it grows by one for every limit requests that finish
+// within the latency target while the limit is in use, and shrinks by 10% when requests exceed the target,
+// e.g. because the database slowed down.
+type ConcurrencyLimiter struct {
+	opts     ConcurrencyLimitOptions
+	classify func(r *http.Request) Priority
+
+	mu           sync.Mutex
+	limit        float64
+	inflight     int
+	lastDecrease time.Time
+	// queue holds the waiters, normal ones in front of low ones
+	queue *list.List
+	// longRunning are the PriorityLongRunning requests in flight
+	longRunning int
+}
+
+type concurrencyWaiter struct {
+	priority Priority
+	// ready receives true when the waiter got a slot, false when it was evicted by a normal request
+	ready chan bool
+}
+
+// NewConcurrencyLimiter creates a limiter, classify assigns the priority of each request
+func NewConcurrencyLimiter(opts ConcurrencyLimitOptions, classify func(r *http.Request) Priority) *ConcurrencyLimiter {
+	l := &ConcurrencyLimiter{
+		opts:     opts,
+		classify: classify,
+		limit:    float64(opts.InitialLimit),
+		queue:    list.New(),
+	}
+	metrics.ConcurrencyLimit.Set(int64(opts.InitialLimit))
+	return l
+}
+
+// Middleware sheds requests with 503 and Retry-After when the limit is reached and the queue is full,
+// or when a queued request did not get a slot within the queue timeout
+func (l *ConcurrencyLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		priority := l.classify(r)
+		if priority == PriorityCritical {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if priority == PriorityLongRunning {
+			if !l.acquireLongRunning() {
+				metrics.ConcurrencyRejectedTotal.Add(1)
+				w.Header().Set("Retry-After", strconv.Itoa(concurrencyRetryAfter))
+				writeError(w, r, http.StatusServiceUnavailable, "server overloaded, retry later.")
+				return
+			}
+			defer l.releaseLongRunning()
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !l.acquire(r, priority) {
+			metrics.ConcurrencyRejectedTotal.Add(1)
+			w.Header().Set("Retry-After", strconv.Itoa(concurrencyRetryAfter))
+			writeError(w, r, http.StatusServiceUnavailable, "server overloaded, retry later.")
+			return
+		}
+
+		start := time.Now()
+		defer func() {
+			l.release(time.Since(start))
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// acquire takes a slot, waiting in the queue if the limit is reached. It reports false if the request is shed.
+func (l *ConcurrencyLimiter) acquire(r *http.Request, priority Priority) bool {
+	l.mu.Lock()
+	if l.inflight < int(l.limit) && l.queue.Len() == 0 {
+		l.inflight++
+		l.updateMetrics()
+		l.mu.Unlock()
+		return true
+	}
+
+	if l.queue.Len() >= l.opts.QueueSize {
+		// A normal request takes the place of the last low one
+		last := l.queue.Back()
+		if priority == PriorityLow || last == nil || last.Value.(*concurrencyWaiter).priority != PriorityLow {
+			l.mu.Unlock()
+			return false
+		}
+		l.queue.Remove(last)
+		last.Value.(*concurrencyWaiter).ready <- false
+	}
+
+	waiter := &concurrencyWaiter{priority: priority, ready: make(chan bool, 1)}
+	var element *list.Element
+	if priority == PriorityLow {
+		element = l.queue.PushBack(waiter)
+	} else {
+		// Behind the last normal request that is waiting
+		mark := l.queue.Back()
+		for mark != nil && mark.Value.(*concurrencyWaiter).priority == PriorityLow {
+			mark = mark.Prev()
+		}
+		if mark == nil {
+			element = l.queue.PushFront(waiter)
+		} else {
+			element = l.queue.InsertAfter(waiter, mark)
+		}
+	}
+	l.updateMetrics()
+	l.mu.Unlock()
+
+	timer := time.NewTimer(l.opts.QueueTimeout)
+	defer timer.Stop()
+	select {
+	case acquired := <-waiter.ready:
+		return acquired
+	case <-timer.C:
+	case <-r.Context().Done():
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case acquired := <-waiter.ready:
+		// The slot was handed over meanwhile
+		if acquired {
+			l.inflight--
+			l.dispatch()
+		}
+	default:
+		l.queue.Remove(element)
+	}
+	l.updateMetrics()
+	return false
+}
+
+// release frees the slot of a request and adapts the limit to its latency
+func (l *ConcurrencyLimiter) release(latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	switch {
+	case latency > l.opts.LatencyTarget:
+		// Requests that started before the last decrease saw the old limit, they must not decrease it again
+		if now.Sub(l.lastDecrease) > latency {
+			l.limit = max(float64(l.opts.MinLimit), l.limit*concurrencyBackoff)
+			l.lastDecrease = now
+		}
+	case float64(l.inflight) >= l.limit/2:
+		l.limit = min(float64(l.opts.MaxLimit), l.limit+1/l.limit)
+	}
+
+	l.inflight--
+	l.dispatch()
+	l.updateMetrics()
+}
+
+// acquireLongRunning takes a slot of the long-running pool, it reports false if the pool is full
+func (l *ConcurrencyLimiter) acquireLongRunning() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.longRunning >= l.opts.LongRunningLimit {
+		return false
+	}
+	l.longRunning++
+	l.updateMetrics()
+	return true
+}
+
+func (l *ConcurrencyLimiter) releaseLongRunning() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.longRunning--
+	l.updateMetrics()
+}
+
+// dispatch hands free slots to the waiters in queue order, l.mu must be held
+func (l *ConcurrencyLimiter) dispatch() {
+	for l.inflight < int(l.limit) && l.queue.Len() > 0 {
+		waiter := l.queue.Remove(l.queue.Front()).(*concurrencyWaiter)
+		l.inflight++
+		waiter.ready <- true
+	}
+}
+
+// updateMetrics publishes the state, l.mu must be held
+func (l *ConcurrencyLimiter) updateMetrics() {
+	metrics.ConcurrencyLimit.Set(int64(l.limit))
+	metrics.ConcurrencyInflight.Set(int64(l.inflight))
+	metrics.ConcurrencyQueued.Set(int64(l.queue.Len()))
+	metrics.ConcurrencyLongRunningInflight.Set(int64(l.longRunning))
+}
@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitForLimiter polls until cond holds for the state of the limiter, it reports false after a few seconds
+func waitForLimiter(l *ConcurrencyLimiter, cond func() bool) bool {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		l.mu.Lock()
+		done := cond()
+		l.mu.Unlock()
+		if done {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
+
+func TestConcurrencyLimiterShedding(t *testing.T) {
+	const (
+		normal      = PriorityNormal
+		low         = PriorityLow
+		critical    = PriorityCritical
+		longRunning = PriorityLongRunning
+	)
+
+	tests := []struct {
+		name         string
+		queueTimeout time.Duration
+		// before are held in flight or in the queue until the request is answered
+		before   []Priority
+		priority Priority
+		// release frees the held slots once the request is queued
+		release bool
+		// clientTimeout cancels the request while it waits
+		clientTimeout time.Duration
+		wantStatus    int
+		wantBefore    []int
+	}{
+		{"free slot", time.Minute, nil, normal, false, 0, http.StatusNoContent, nil},
+		{"queue timeout sheds", 20 * time.Millisecond, []Priority{normal}, normal, false, 0, http.StatusServiceUnavailable, []int{http.StatusNoContent}},
+		{"slot released while queued", time.Minute, []Priority{normal}, normal, true, 0, http.StatusNoContent, []int{http.StatusNoContent}},
+		{"client gone while queued", time.Minute, []Priority{normal}, normal, false, 20 * time.Millisecond, http.StatusServiceUnavailable, []int{http.StatusNoContent}},
+		{"full queue sheds at once", time.Minute, []Priority{normal, normal}, normal, false, 0, http.StatusServiceUnavailable, []int{http.StatusNoContent, http.StatusNoContent}},
+		{"low cannot evict normal", time.Minute, []Priority{normal, normal}, low, false, 0, http.StatusServiceUnavailable, []int{http.StatusNoContent, http.StatusNoContent}},
+		{"low cannot evict low", time.Minute, []Priority{normal, low}, low, false, 0, http.StatusServiceUnavailable, []int{http.StatusNoContent, http.StatusNoContent}},
+		{"normal evicts queued low", time.Minute, []Priority{normal, low}, normal, true, 0, http.StatusNoContent, []int{http.StatusNoContent, http.StatusServiceUnavailable}},
+		{"critical skips the limit", time.Minute, []Priority{normal, normal}, critical, false, 0, http.StatusNoContent, []int{http.StatusNoContent, http.StatusNoContent}},
+		{"long-running skips the limit", time.Minute, []Priority{normal, normal}, longRunning, false, 0, http.StatusNoContent, []int{http.StatusNoContent, http.StatusNoContent}},
+		{"long-running pool full", time.Minute, []Priority{longRunning}, longRunning, false, 0, http.StatusServiceUnavailable, []int{http.StatusNoContent}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewConcurrencyLimiter(ConcurrencyLimitOptions{
+				InitialLimit:     1,
+				MinLimit:         1,
+				MaxLimit:         1,
+				LatencyTarget:    time.Hour,
+				QueueSize:        1,
+				QueueTimeout:     tt.queueTimeout,
+				LongRunningLimit: 1,
+			}, func(r *http.Request) Priority {
+				priority, _ := strconv.Atoi(r.Header.Get("X-Priority"))
+				return Priority(priority)
+			})
+			hold := make(chan struct{})
+			release := sync.OnceFunc(func() { close(hold) })
+			handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("X-Hold") != "" {
+					<-hold
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			request := func(ctx context.Context, priority Priority, held bool) int {
+				r := httptest.NewRequestWithContext(ctx, http.MethodGet, "/users", nil)
+				r.Header.Set("X-Priority", strconv.Itoa(int(priority)))
+				if held {
+					r.Header.Set("X-Hold", "true")
+				}
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, r)
+				return w.Code
+			}
+
+			var wg sync.WaitGroup
+			beforeStatus := make([]int, len(tt.before))
+			for i, priority := range tt.before {
+				wg.Go(func() { beforeStatus[i] = request(context.Background(), priority, true) })
+				held := waitForLimiter(limiter, func() bool {
+					return limiter.inflight+limiter.queue.Len()+limiter.longRunning == i+1
+				})
+				if !held {
+					t.Fatalf("held request %d was neither in flight nor queued", i)
+				}
+			}
+			if tt.release {
+				wg.Go(func() {
+					queued := waitForLimiter(limiter, func() bool {
+						front := limiter.queue.Front()
+						return limiter.queue.Len() == 1 && front.Value.(*concurrencyWaiter).priority == tt.priority
+					})
+					if !queued {
+						t.Errorf("request was not queued")
+					}
+					release()
+				})
+			}
+
+			ctx := context.Background()
+			if tt.clientTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.clientTimeout)
+				defer cancel()
+			}
+			status := request(ctx, tt.priority, false)
+			release()
+			wg.Wait()
+
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+			for i, want := range tt.wantBefore {
+				if beforeStatus[i] != want {
+					t.Errorf("status of held request %d = %d, want %d", i, beforeStatus[i], want)
+				}
+			}
+			limiter.mu.Lock()
+			defer limiter.mu.Unlock()
+			if limiter.inflight != 0 || limiter.queue.Len() != 0 || limiter.longRunning != 0 {
+				t.Errorf("after all requests: inflight = %d, queued = %d, long-running = %d, want 0",
+					limiter.inflight, limiter.queue.Len(), limiter.longRunning)
+			}
+		})
+	}
+}
+
+func TestConcurrencyLimiterRelease(t *testing.T) {
+	tests := []struct {
+		name string
+		// limit and inflight are the state before the request is released
+		limit        float64
+		inflight     int
+		latency      time.Duration
+		lastDecrease time.Duration
+		want         float64
+	}{
+		{"slow request decreases", 10, 10, 2 * time.Second, time.Hour, 9},
+		{"slow request that saw the last decrease", 10, 10, 2 * time.Second, time.Second, 10},
+		{"decrease stops at the minimum", 2, 2, 2 * time.Second, time.Hour, 2},
+		{"fast request with the limit in use increases", 10, 5, time.Millisecond, time.Hour, 10.1},
+		{"fast request with the limit unused", 10, 4, time.Millisecond, time.Hour, 10},
+		{"increase stops at the maximum", 20, 20, time.Millisecond, time.Hour, 20},
+		{"request at the latency target", 10, 10, time.Second, time.Hour, 10.1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewConcurrencyLimiter(ConcurrencyLimitOptions{
+				InitialLimit:  int(tt.limit),
+				MinLimit:      2,
+				MaxLimit:      20,
+				LatencyTarget: time.Second,
+			}, nil)
+			l.inflight = tt.inflight
+			l.lastDecrease = time.Now().Add(-tt.lastDecrease)
+
+			l.release(tt.latency)
+			if math.Abs(l.limit-tt.want) > 1e-9 {
+				t.Errorf("release(%v) limit = %v, want %v", tt.latency, l.limit, tt.want)
+			}
+			if l.inflight != tt.inflight-1 {
+				t.Errorf("release(%v) inflight = %d, want %d", tt.latency, l.inflight, tt.inflight-1)
+			}
+		})
+	}
+}
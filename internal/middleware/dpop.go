@@ -0,0 +1,276 @@
+package middleware
+
+import (
+	"container/list"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/lestrrat-go/jwx/v3/jws"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+)
+
+// DPoP modes for the JWT security scheme
+const (
+	DPoPDisabled = "disabled" // only Bearer tokens are accepted
+	DPoPOptional = "optional" // Bearer and DPoP tokens are accepted, DPoP-bound tokens require a proof
+	DPoPRequired = "required" // only DPoP tokens with a valid proof are accepted
+)
+
+// DPoPOptions configures the validation of DPoP proofs (RFC 9449)
+type DPoPOptions struct {
+	Mode string
+	// PublicURL is the externally visible base URL used to check the htu claim, e.g. https://api.example.com.
+	// If empty it is derived from the request, which is wrong behind a proxy that rewrites the host or scheme.
+	PublicURL string
+	// MaxAge is how long a proof is accepted after its iat, and how long its jti is remembered
+	MaxAge time.Duration
+	// ReplayCacheSize bounds the number of remembered proof jtis
+	ReplayCacheSize int
+}
+
+// dpopModeContextKey is the key used to store the DPoP mode of a route in request context
+const dpopModeContextKey contextKey = "dpop_mode"
+
+// DPoPModes sets the DPoP mode of routes, written as "METHOD /pattern" like they are registered.
+// Other routes use the mode passed to UseDPoP. It must run before authentication.
+func DPoPModes(routes map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := r.Method + " " + chi.RouteContext(r.Context()).RoutePattern()
+			if mode, ok := routes[route]; ok {
+				r = r.WithContext(context.WithValue(r.Context(), dpopModeContextKey, mode))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// dpopProofType is the required typ header of a proof
+const dpopProofType = "dpop+jwt"
+
+// dpopClockSkew tolerates proofs with an iat slightly in the future
+const dpopClockSkew = 5 * time.Second
+
+// dpopChallenge is sent in WWW-Authenticate when a proof is missing or invalid
+const dpopChallenge = `DPoP algs="RS256 RS384 RS512 PS256 PS384 PS512 ES256 ES384 ES512 EdDSA"`
+
+// dpopVerifier validates DPoP proofs and remembers their jtis
+type dpopVerifier struct {
+	opts   DPoPOptions
+	replay *replayCache
+}
+
+func newDPoPVerifier(opts DPoPOptions) *dpopVerifier {
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = 5 * time.Minute
+	}
+	if opts.ReplayCacheSize <= 0 {
+		opts.ReplayCacheSize = 100_000
+	}
+	return &dpopVerifier{
+		opts:   opts,
+		replay: newReplayCache(opts.ReplayCacheSize),
+	}
+}
+
+type dpopClaims struct {
+	JTI string `json:"jti"`
+	HTM string `json:"htm"`
+	HTU string `json:"htu"`
+	IAT int64  `json:"iat"`
+	ATH string `json:"ath"`
+}
+
+// verify checks the DPoP proof of the request against the access token and the thumbprint jkt it is bound to
+func (v *dpopVerifier) verify(r *http.Request, accessToken, jkt string) error {
+	proofs := r.Header.Values("DPoP")
+	if len(proofs) != 1 {
+		return errors.New("exactly one DPoP proof is required")
+	}
+	proof := []byte(proofs[0])
+
+	msg, err := jws.Parse(proof, jws.WithCompact())
+	if err != nil || len(msg.Signatures()) != 1 {
+		return errors.New("malformed DPoP proof")
+	}
+	headers := msg.Signatures()[0].ProtectedHeaders()
+
+	if typ, _ := headers.Type(); typ != dpopProofType {
+		return errors.New("DPoP proof must have typ dpop+jwt")
+	}
+	alg, ok := headers.Algorithm()
+	if !ok || alg.IsSymmetric() || alg.String() == "none" {
+		return errors.New("DPoP proof must be signed with an asymmetric algorithm")
+	}
+	key, ok := headers.JWK()
+	if !ok {
+		return errors.New("DPoP proof has no jwk header")
+	}
+	if private, err := jwk.IsPrivateKey(key); err != nil || private {
+		return errors.New("DPoP proof jwk must be a public key")
+	}
+
+	payload, err := jws.Verify(proof, jws.WithKey(alg, key))
+	if err != nil {
+		return errors.New("invalid DPoP proof signature")
+	}
+
+	var claims dpopClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return errors.New("malformed DPoP proof claims")
+	}
+	if claims.JTI == "" {
+		return errors.New("DPoP proof has no jti")
+	}
+	if claims.HTM != r.Method {
+		return errors.New("DPoP proof htm does not match the request method")
+	}
+	if !sameHTU(claims.HTU, v.requestURL(r)) {
+		return errors.New("DPoP proof htu does not match the request URL")
+	}
+
+	issuedAt := time.Unix(claims.IAT, 0)
+	now := time.Now()
+	if issuedAt.After(now.Add(dpopClockSkew)) || now.Sub(issuedAt) > v.opts.MaxAge {
+		return errors.New("DPoP proof is expired or not yet valid")
+	}
+
+	hash := sha256.Sum256([]byte(accessToken))
+	if claims.ATH != base64.RawURLEncoding.EncodeToString(hash[:]) {
+		return errors.New("DPoP proof ath does not match the access token")
+	}
+
+	thumbprint, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return fmt.Errorf("failed to compute jwk thumbprint: %w", err)
+	}
+	if base64.RawURLEncoding.EncodeToString(thumbprint) != jkt {
+		return errors.New("DPoP proof key does not match the token binding")
+	}
+
+	// Only remember proofs that passed every other check, so forged proofs cannot fill the cache
+	// and a proof rejected for another reason cannot burn the jti of the client
+	if !v.replay.add(jkt+":"+claims.JTI, issuedAt.Add(v.opts.MaxAge)) {
+		return errors.New("DPoP proof was already used")
+	}
+
+	return nil
+}
+
+// requestURL returns the URL the client sent the request to, without query and fragment
+func (v *dpopVerifier) requestURL(r *http.Request) string {
+	if v.opts.PublicURL != "" {
+		return strings.TrimSuffix(v.opts.PublicURL, "/") + r.URL.Path
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + r.URL.Path
+}
+
+// sameHTU compares the htu claim with the request URL, ignoring its query and fragment and the case of scheme and host
+func sameHTU(htu, requestURL string) bool {
+	htu, _, _ = strings.Cut(htu, "#")
+	htu, _, _ = strings.Cut(htu, "?")
+
+	htuScheme, htuRest, ok := strings.Cut(htu, "://")
+	if !ok {
+		return false
+	}
+	reqScheme, reqRest, _ := strings.Cut(requestURL, "://")
+	htuHost, htuPath, _ := strings.Cut(htuRest, "/")
+	reqHost, reqPath, _ := strings.Cut(reqRest, "/")
+
+	return strings.EqualFold(htuScheme, reqScheme) && strings.EqualFold(htuHost, reqHost) && htuPath == reqPath
+}
+
+// tokenJKT returns the cnf.jkt claim of a DPoP-bound access token
+func tokenJKT(token jwt.Token) (string, bool) {
+	var cnf map[string]any
+	if err := token.Get("cnf", &cnf); err != nil {
+		return "", false
+	}
+	jkt, ok := cnf["jkt"].(string)
+	return jkt, ok && jkt != ""
+}
+
+// replayCache remembers proof jtis until they expire. When it is full the oldest entry is evicted,
+// so size it for the expected proofs per MaxAge. Every replica has its own cache.
+type replayCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // oldest first
+	entries map[string]*list.Element
+}
+
+type replayEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+func newReplayCache(size int) *replayCache {
+	return &replayCache{
+		size:    size,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+// add records the key and reports false if it was already seen
+func (c *replayCache) add(key string, expiresAt time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if elem, found := c.entries[key]; found && now.Before(elem.Value.(replayEntry).expiresAt) {
+		return false
+	}
+
+	// Drop expired entries from the front, proofs are added roughly in expiry order
+	for front := c.order.Front(); front != nil; front = c.order.Front() {
+		entry := front.Value.(replayEntry)
+		if now.Before(entry.expiresAt) && c.order.Len() < c.size {
+			break
+		}
+		c.order.Remove(front)
+		delete(c.entries, entry.key)
+	}
+
+	if elem, found := c.entries[key]; found {
+		c.order.Remove(elem)
+	}
+	c.entries[key] = c.order.PushBack(replayEntry{key: key, expiresAt: expiresAt})
+	return true
+}
+
+// authenticateDPoP validates a token presented with the DPoP scheme and its proof
+func (j *JWTAuth) authenticateDPoP(r *http.Request, tokenString string) (context.Context, error) {
+	token, err := j.parseToken(r.Context(), tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	jkt, bound := tokenJKT(token)
+	if !bound {
+		return nil, &AuthError{Status: http.StatusUnauthorized, Message: "token is not DPoP-bound.", Challenge: dpopChallenge}
+	}
+
+	if err := j.dpop.verify(r, tokenString, jkt); err != nil {
+		return nil, &AuthError{Status: http.StatusUnauthorized, Message: err.Error() + ".", Challenge: dpopChallenge + `, error="invalid_dpop_proof"`}
+	}
+
+	return contextWithToken(r.Context(), token), nil
+}
@@ -0,0 +1,244 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v3/jwa"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+	"github.com/lestrrat-go/jwx/v3/jws"
+)
+
+const (
+	dpopTestURL   = "https://api.example.com/users/me"
+	dpopTestToken = "access-token"
+)
+
+// dpopTestKey is the key of a client and the thumbprint tokens bound to it carry in cnf.jkt
+type dpopTestKey struct {
+	private *ecdsa.PrivateKey
+	public  jwk.Key
+	jkt     string
+}
+
+func newDPoPTestKey(t *testing.T) dpopTestKey {
+	t.Helper()
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() error = %v", err)
+	}
+	public, err := jwk.PublicKeyOf(&private.PublicKey)
+	if err != nil {
+		t.Fatalf("jwk.PublicKeyOf() error = %v", err)
+	}
+	thumbprint, err := public.Thumbprint(crypto.SHA256)
+	if err != nil {
+		t.Fatalf("Thumbprint() error = %v", err)
+	}
+	return dpopTestKey{private: private, public: public, jkt: base64.RawURLEncoding.EncodeToString(thumbprint)}
+}
+
+// proof signs the claims with the key, typ is the typ header
+func (k dpopTestKey) proof(t *testing.T, typ string, claims dpopClaims) string {
+	t.Helper()
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	headers := jws.NewHeaders()
+	if err := headers.Set(jws.TypeKey, typ); err != nil {
+		t.Fatalf("Set(typ) error = %v", err)
+	}
+	if err := headers.Set(jws.JWKKey, k.public); err != nil {
+		t.Fatalf("Set(jwk) error = %v", err)
+	}
+	signed, err := jws.Sign(payload, jws.WithKey(jwa.ES256(), k.private, jws.WithProtectedHeaders(headers)))
+	if err != nil {
+		t.Fatalf("jws.Sign() error = %v", err)
+	}
+	return string(signed)
+}
+
+// validDPoPClaims are the claims of a fresh proof for GET dpopTestURL with dpopTestToken
+func validDPoPClaims(jti string) dpopClaims {
+	hash := sha256.Sum256([]byte(dpopTestToken))
+	return dpopClaims{
+		JTI: jti,
+		HTM: http.MethodGet,
+		HTU: dpopTestURL,
+		IAT: time.Now().Unix(),
+		ATH: base64.RawURLEncoding.EncodeToString(hash[:]),
+	}
+}
+
+func dpopRequest(proofs ...string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/users/me", nil)
+	for _, proof := range proofs {
+		r.Header.Add("DPoP", proof)
+	}
+	return r
+}
+
+func TestDPoPVerify(t *testing.T) {
+	key := newDPoPTestKey(t)
+	other := newDPoPTestKey(t)
+
+	tests := []struct {
+		name    string
+		typ     string
+		claims  func(c *dpopClaims)
+		jkt     string
+		wantErr string
+	}{
+		{"valid", dpopProofType, func(c *dpopClaims) {}, key.jkt, ""},
+		{"htu with query and fragment", dpopProofType, func(c *dpopClaims) { c.HTU = dpopTestURL + "?page=2#top" }, key.jkt, ""},
+		{"htu with upper case host", dpopProofType, func(c *dpopClaims) { c.HTU = "HTTPS://API.EXAMPLE.COM/users/me" }, key.jkt, ""},
+		{"iat within clock skew", dpopProofType, func(c *dpopClaims) { c.IAT = time.Now().Add(dpopClockSkew / 2).Unix() }, key.jkt, ""},
+		{"expired", dpopProofType, func(c *dpopClaims) { c.IAT = time.Now().Add(-6 * time.Minute).Unix() }, key.jkt, "expired or not yet valid"},
+		{"issued in the future", dpopProofType, func(c *dpopClaims) { c.IAT = time.Now().Add(time.Minute).Unix() }, key.jkt, "expired or not yet valid"},
+		{"wrong htm", dpopProofType, func(c *dpopClaims) { c.HTM = http.MethodPost }, key.jkt, "htm does not match"},
+		{"lower case htm", dpopProofType, func(c *dpopClaims) { c.HTM = "get" }, key.jkt, "htm does not match"},
+		{"wrong htu path", dpopProofType, func(c *dpopClaims) { c.HTU = "https://api.example.com/users" }, key.jkt, "htu does not match"},
+		{"wrong htu host", dpopProofType, func(c *dpopClaims) { c.HTU = "https://evil.example.com/users/me" }, key.jkt, "htu does not match"},
+		{"wrong htu scheme", dpopProofType, func(c *dpopClaims) { c.HTU = "http://api.example.com/users/me" }, key.jkt, "htu does not match"},
+		{"htu without scheme", dpopProofType, func(c *dpopClaims) { c.HTU = "api.example.com/users/me" }, key.jkt, "htu does not match"},
+		{"wrong ath", dpopProofType, func(c *dpopClaims) { c.ATH = "bogus" }, key.jkt, "ath does not match"},
+		{"without jti", dpopProofType, func(c *dpopClaims) { c.JTI = "" }, key.jkt, "has no jti"},
+		{"wrong typ", "JWT", func(c *dpopClaims) {}, key.jkt, "typ dpop+jwt"},
+		{"key of another client", dpopProofType, func(c *dpopClaims) {}, other.jkt, "does not match the token binding"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newDPoPVerifier(DPoPOptions{Mode: DPoPRequired, PublicURL: "https://api.example.com/"})
+			claims := validDPoPClaims(tt.name)
+			tt.claims(&claims)
+
+			err := v.verify(dpopRequest(key.proof(t, tt.typ, claims)), dpopTestToken, tt.jkt)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("verify() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("verify() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDPoPVerifyProofCount(t *testing.T) {
+	key := newDPoPTestKey(t)
+	v := newDPoPVerifier(DPoPOptions{Mode: DPoPRequired, PublicURL: "https://api.example.com"})
+
+	tests := []struct {
+		name   string
+		proofs []string
+	}{
+		{"without proof", nil},
+		{"two proofs", []string{
+			key.proof(t, dpopProofType, validDPoPClaims("one")),
+			key.proof(t, dpopProofType, validDPoPClaims("two")),
+		}},
+		{"malformed proof", []string{"not-a-jws"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v.verify(dpopRequest(tt.proofs...), dpopTestToken, key.jkt); err == nil {
+				t.Errorf("verify() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestDPoPVerifyReplay(t *testing.T) {
+	key := newDPoPTestKey(t)
+	other := newDPoPTestKey(t)
+
+	tests := []struct {
+		name string
+		// first is verified before second, whose result is checked
+		first      string
+		firstJKT   string
+		second     string
+		secondJKT  string
+		wantReplay bool
+	}{
+		{
+			name:  "replayed proof",
+			first: key.proof(t, dpopProofType, validDPoPClaims("same")), firstJKT: key.jkt,
+			second: key.proof(t, dpopProofType, validDPoPClaims("same")), secondJKT: key.jkt,
+			wantReplay: true,
+		},
+		{
+			name:  "new jti",
+			first: key.proof(t, dpopProofType, validDPoPClaims("first")), firstJKT: key.jkt,
+			second: key.proof(t, dpopProofType, validDPoPClaims("second")), secondJKT: key.jkt,
+		},
+		{
+			name:  "same jti of another key",
+			first: other.proof(t, dpopProofType, validDPoPClaims("same")), firstJKT: other.jkt,
+			second: key.proof(t, dpopProofType, validDPoPClaims("same")), secondJKT: key.jkt,
+		},
+		{
+			// A forged proof for a token bound to another key must not burn the jti of the client
+			name:  "jti of a proof rejected by the binding",
+			first: key.proof(t, dpopProofType, validDPoPClaims("same")), firstJKT: other.jkt,
+			second: key.proof(t, dpopProofType, validDPoPClaims("same")), secondJKT: key.jkt,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newDPoPVerifier(DPoPOptions{Mode: DPoPRequired, PublicURL: "https://api.example.com"})
+			_ = v.verify(dpopRequest(tt.first), dpopTestToken, tt.firstJKT)
+
+			err := v.verify(dpopRequest(tt.second), dpopTestToken, tt.secondJKT)
+			if !tt.wantReplay {
+				if err != nil {
+					t.Errorf("verify() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "already used") {
+				t.Errorf("verify() error = %v, want %q", err, "already used")
+			}
+		})
+	}
+}
+
+func TestReplayCacheEviction(t *testing.T) {
+	c := newReplayCache(2)
+	expiresAt := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"a", true},
+		{"a", false},
+		{"b", true},
+		// Full, a is evicted
+		{"c", true},
+		{"a", true},
+		{"c", false},
+	}
+	for _, tt := range tests {
+		if got := c.add(tt.key, expiresAt); got != tt.want {
+			t.Errorf("add(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+
+	if !c.add("expired", time.Now().Add(-time.Second)) || !c.add("expired", expiresAt) {
+		t.Errorf("add() of an expired key = false, want true")
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ErrorWriter writes an error response of the middlewares, see UseErrorWriter
+type ErrorWriter func(w http.ResponseWriter, r *http.Request, status int, message string)
+
+// errorWriterContextKey is the key used to store the ErrorWriter of a route in request context
+const errorWriterContextKey contextKey = "error_writer"
+
+// UseErrorWriter returns a middleware that makes the middlewares after it write errors with fn,
+// e.g. for protocols like SCIM that define their own error format
+func UseErrorWriter(fn ErrorWriter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), errorWriterContextKey, fn)))
+		})
+	}
+}
+
+// writeError writes an error with the ErrorWriter of the route, by default as {"error": "..."}
+func writeError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	if fn, ok := r.Context().Value(errorWriterContextKey).(ErrorWriter); ok {
+		fn(w, r, status, message)
+		return
+	}
+	http.Error(w, fmt.Sprintf(`{"error": %q}`, message), status)
+}
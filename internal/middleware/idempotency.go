@@ -0,0 +1,247 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header holding the client generated key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from a previous request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength bounds the stored keys, UUIDs are the expected format
+	maxIdempotencyKeyLength = 255
+	// idempotencyLockTimeout is how long a key stays locked if its request never completes, e.g. after a crash.
+	// It must be longer than the longest request.
+	idempotencyLockTimeout = time.Minute
+)
+
+// Idempotency stores the responses of requests with an Idempotency-Key in Postgres and replays them for retries
+type Idempotency struct {
+	queries *repository.Queries
+	ttl     time.Duration
+}
+
+// NewIdempotency creates an idempotency store that keeps responses for ttl
+func NewIdempotency(queries *repository.Queries, ttl time.Duration) *Idempotency {
+	return &Idempotency{
+		queries: queries,
+		ttl:     ttl,
+	}
+}
+
+// Middleware handles Idempotency-Key for the given routes, written as "METHOD /pattern" like they are registered.
+// It must run after authentication, keys are scoped to the caller and rejected without one.
+//   - the first request is executed and its response stored, unless it fails with a 5xx status
+//   - a retry with the same key and request gets the stored response
+//   - a retry while the first request is in progress gets 409
+//   - the same key with a different request gets 422
+func (i *Idempotency) Middleware(routes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || !slices.Contains(routes, r.Method+" "+chi.RouteContext(r.Context()).RoutePattern()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				writeError(w, r, http.StatusBadRequest, "Idempotency-Key must not be longer than 255 characters.")
+				return
+			}
+
+			owner, ok := principalKey(r.Context())
+			if !ok {
+				writeError(w, r, http.StatusUnauthorized, "Idempotency-Key requires an authenticated caller.")
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeError(w, r, http.StatusBadRequest, "failed to read request body.")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			fingerprint := requestFingerprint(r, body)
+
+			_, err = i.queries.ClaimIdempotencyKey(r.Context(), repository.ClaimIdempotencyKeyParams{
+				Owner:       owner,
+				Key:         key,
+				Fingerprint: fingerprint,
+				ExpiresAt:   time.Now().Add(idempotencyLockTimeout),
+			})
+			if errors.Is(err, pgx.ErrNoRows) {
+				i.replay(w, r, owner, key, fingerprint)
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to claim idempotency key", "error", err)
+				writeError(w, r, http.StatusInternalServerError, "An internal server error occurred")
+				return
+			}
+
+			i.execute(w, r, next, owner, key, fingerprint)
+		})
+	}
+}
+
+// execute runs the request and stores its response. Failed requests release the key so the client can retry.
+func (i *Idempotency) execute(w http.ResponseWriter, r *http.Request, next http.Handler, owner, key, fingerprint string) {
+	// The response must be stored even if the client gave up waiting
+	ctx := context.WithoutCancel(r.Context())
+	headersBefore := w.Header().Clone()
+	recorder := &recordingWriter{ResponseWriter: w}
+
+	completed := false
+	defer func() {
+		// Also runs when the handler panics
+		if completed {
+			return
+		}
+		err := i.queries.ReleaseIdempotencyKey(ctx, repository.ReleaseIdempotencyKeyParams{
+			Owner:       owner,
+			Key:         key,
+			Fingerprint: fingerprint,
+		})
+		if err != nil {
+			slog.Warn("Failed to release idempotency key, it is locked until it expires", "error", err)
+		}
+	}()
+
+	next.ServeHTTP(recorder, r)
+
+	status := recorder.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if status >= http.StatusInternalServerError {
+		return
+	}
+
+	// Only store the headers set by the handler, the middlewares before set theirs again on replay
+	headers := http.Header{}
+	for name, values := range w.Header() {
+		if !slices.Equal(headersBefore[name], values) {
+			headers[name] = values
+		}
+	}
+	headersJSON, err := json.Marshal(headers)
+	if err != nil {
+		slog.Error("Failed to encode response headers for idempotency key", "error", err)
+		return
+	}
+
+	err = i.queries.CompleteIdempotencyKey(ctx, repository.CompleteIdempotencyKeyParams{
+		StatusCode:      pgtype.Int4{Int32: int32(status), Valid: true},
+		ResponseHeaders: headersJSON,
+		ResponseBody:    recorder.body.Bytes(),
+		ExpiresAt:       time.Now().Add(i.ttl),
+		Owner:           owner,
+		Key:             key,
+		Fingerprint:     fingerprint,
+	})
+	if err != nil {
+		slog.Error("Failed to store response for idempotency key", "error", err)
+		return
+	}
+	completed = true
+}
+
+// replay writes the stored response of a key that is already in use
+func (i *Idempotency) replay(w http.ResponseWriter, r *http.Request, owner, key, fingerprint string) {
+	stored, err := i.queries.GetIdempotencyKey(r.Context(), repository.GetIdempotencyKeyParams{
+		Owner: owner,
+		Key:   key,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Released between the claim and the lookup, the first request failed
+		writeError(w, r, http.StatusConflict, "a request with this Idempotency-Key is in progress, retry later.")
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to load idempotency key", "error", err)
+		writeError(w, r, http.StatusInternalServerError, "An internal server error occurred")
+		return
+	}
+
+	if stored.Fingerprint != fingerprint {
+		writeError(w, r, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request.")
+		return
+	}
+	if !stored.StatusCode.Valid {
+		writeError(w, r, http.StatusConflict, "a request with this Idempotency-Key is in progress, retry later.")
+		return
+	}
+
+	var headers http.Header
+	if err := json.Unmarshal(stored.ResponseHeaders, &headers); err != nil {
+		slog.Error("Failed to decode stored response headers", "error", err)
+		writeError(w, r, http.StatusInternalServerError, "An internal server error occurred")
+		return
+	}
+	for name, values := range headers {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(int(stored.StatusCode.Int32))
+	_, _ = w.Write(stored.ResponseBody)
+}
+
+// DeleteExpired deletes the expired keys once, e.g. as a scheduled task
+func (i *Idempotency) DeleteExpired(ctx context.Context) error {
+	deleted, err := i.queries.DeleteExpiredIdempotencyKeys(ctx)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		slog.Debug("Deleted expired idempotency keys", "count", deleted)
+	}
+	return nil
+}
+
+// requestFingerprint hashes everything that makes two requests the same operation
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// recordingWriter passes the response through and keeps a copy of its status and body
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(code int) {
+	if rw.status == 0 {
+		rw.status = code
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
@@ -0,0 +1,309 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/httprc/v3"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+)
+
+// Key sources reported in KeyStatus
+const (
+	KeySourceStatic = "static" // keys from OIDC_JWKS or OIDC_JWKS_FILE, the issuer is never contacted
+	KeySourceIssuer = "issuer" // keys fetched from the issuer's jwks_uri
+	KeySourceCache  = "cache"  // last good keys persisted to disk, used while the issuer is unreachable
+	KeySourceNone   = "none"   // no keys, every token is rejected
+)
+
+var errKeysUnavailable = errors.New("no signing keys available")
+
+// JWTAuthOptions configures where JWTAuth gets its signing keys from
+type JWTAuthOptions struct {
+	// JWKS is an inline JSON Web Key Set. It disables discovery.
+	JWKS string
+	// JWKSFile is a JSON Web Key Set on disk. It disables discovery.
+	JWKSFile string
+	// CacheFile persists the last good JWKS of the issuer, it is used when the issuer is unreachable at startup
+	CacheFile string
+	// AllowDegraded starts even if the issuer is unreachable and keeps retrying discovery in the background
+	AllowDegraded bool
+	// RefreshInterval is how often the keys are fetched from the issuer and persisted
+	RefreshInterval time.Duration
+}
+
+// KeyStatus reports the state of the signing keys, e.g. for the readiness probe
+type KeyStatus struct {
+	Source      string
+	Degraded    bool      // the issuer is unreachable
+	LastRefresh time.Time // last successful fetch from the issuer
+	LastError   string
+}
+
+// Ready reports whether tokens can be validated
+func (s KeyStatus) Ready() bool {
+	return s.Source != KeySourceNone
+}
+
+// keySource provides the key set used to validate tokens
+type keySource struct {
+	issuer string
+	opts   JWTAuthOptions
+
+	// cache refreshes the issuer's keys, it is created once and the JWKS URL is registered when discovery succeeds
+	cache *jwk.Cache
+
+	mu        sync.RWMutex
+	jwksURL   string  // empty until discovery succeeded
+	fallback  jwk.Set // static keys or keys loaded from the cache file
+	status    KeyStatus
+	persisted []byte
+}
+
+// newKeySource loads static keys or discovers the issuer's JWKS.
+// In degraded mode discovery failures are not fatal and are retried by run.
+func newKeySource(ctx context.Context, issuer string, opts JWTAuthOptions) (*keySource, error) {
+	k := &keySource{
+		issuer: issuer,
+		opts:   opts,
+	}
+
+	if opts.JWKS != "" || opts.JWKSFile != "" {
+		set, err := loadStaticKeys(opts)
+		if err != nil {
+			return nil, err
+		}
+		k.fallback = set
+		k.status = KeyStatus{Source: KeySourceStatic}
+		return k, nil
+	}
+
+	if issuer == "" {
+		return nil, fmt.Errorf("issuer cannot be empty")
+	}
+
+	// The cache refreshes in the background until ctx is done
+	cache, err := jwk.NewCache(ctx, httprc.NewClient())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWKS cache: %w", err)
+	}
+	k.cache = cache
+
+	err = k.connect(ctx)
+	if err == nil {
+		return k, nil
+	}
+	if !opts.AllowDegraded {
+		_ = cache.Shutdown(ctx)
+		return nil, err
+	}
+
+	k.status = KeyStatus{Source: KeySourceNone, Degraded: true, LastError: err.Error()}
+	if opts.CacheFile != "" {
+		set, cacheErr := jwk.ReadFile(opts.CacheFile)
+		if cacheErr == nil {
+			k.fallback = set
+			k.status.Source = KeySourceCache
+		} else if !errors.Is(cacheErr, os.ErrNotExist) {
+			slog.Warn("Failed to read JWKS cache file", "file", opts.CacheFile, "error", cacheErr)
+		}
+	}
+	slog.Warn("Issuer unreachable, starting in degraded mode", "issuer", issuer, "key_source", k.status.Source, "error", err)
+	return k, nil
+}
+
+func loadStaticKeys(opts JWTAuthOptions) (jwk.Set, error) {
+	if opts.JWKS != "" {
+		set, err := jwk.ParseString(opts.JWKS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse inline JWKS: %w", err)
+		}
+		return set, nil
+	}
+
+	set, err := jwk.ReadFile(opts.JWKSFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+	return set, nil
+}
+
+// connect discovers the JWKS URL and registers it with the auto-refreshing cache
+func (k *keySource) connect(ctx context.Context) error {
+	// Discover JWKS URL from .well-known endpoint
+	wellKnownURL := strings.TrimSuffix(k.issuer, "/") + "/.well-known/openid-configuration"
+	jwksURL, err := discoverJWKSURL(ctx, wellKnownURL)
+	if err != nil {
+		return fmt.Errorf("failed to discover JWKS URL: %w", err)
+	}
+
+	// Register the JWKS URL with auto-refresh, the next attempt registers it again if the first fetch fails
+	if err = k.cache.Register(ctx, jwksURL); err != nil {
+		k.unregister(ctx, jwksURL)
+		return fmt.Errorf("failed to register JWKS URL: %w", err)
+	}
+
+	set, err := k.cache.Lookup(ctx, jwksURL)
+	if err != nil {
+		k.unregister(ctx, jwksURL)
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+
+	k.mu.Lock()
+	k.jwksURL = jwksURL
+	k.status = KeyStatus{Source: KeySourceIssuer, LastRefresh: time.Now()}
+	k.mu.Unlock()
+
+	k.persist(set)
+	return nil
+}
+
+// unregister removes a JWKS URL that failed, so that it stops being refreshed
+func (k *keySource) unregister(ctx context.Context, jwksURL string) {
+	if k.cache.IsRegistered(ctx, jwksURL) {
+		_ = k.cache.Unregister(ctx, jwksURL)
+	}
+}
+
+// keySet returns the keys to validate tokens with, preferring the issuer over the fallback keys
+func (k *keySource) keySet(ctx context.Context) (jwk.Set, error) {
+	k.mu.RLock()
+	jwksURL, fallback := k.jwksURL, k.fallback
+	k.mu.RUnlock()
+
+	if jwksURL != "" {
+		set, err := k.cache.Lookup(ctx, jwksURL)
+		if err == nil {
+			return set, nil
+		}
+		if fallback == nil {
+			return nil, err
+		}
+	}
+	if fallback != nil {
+		return fallback, nil
+	}
+	return nil, errKeysUnavailable
+}
+
+// Status returns the current key status
+func (k *keySource) Status() KeyStatus {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.status
+}
+
+// run retries discovery while degraded and refreshes the keys until the context is canceled
+func (k *keySource) run(ctx context.Context) {
+	if k.Status().Source == KeySourceStatic {
+		return
+	}
+
+	retry := 5 * time.Second
+	for {
+		k.mu.RLock()
+		connected := k.jwksURL != ""
+		k.mu.RUnlock()
+
+		wait := k.opts.RefreshInterval
+		if !connected {
+			wait = min(retry, k.opts.RefreshInterval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		if !connected {
+			if err := k.connect(ctx); err != nil {
+				k.failed(err)
+				retry = min(retry*2, k.opts.RefreshInterval)
+				continue
+			}
+			slog.Info("Issuer reachable again, leaving degraded mode", "issuer", k.issuer)
+			continue
+		}
+
+		k.refresh(ctx)
+	}
+}
+
+func (k *keySource) refresh(ctx context.Context) {
+	k.mu.RLock()
+	jwksURL := k.jwksURL
+	k.mu.RUnlock()
+
+	set, err := k.cache.Refresh(ctx, jwksURL)
+	if err != nil {
+		k.failed(err)
+		return
+	}
+
+	k.mu.Lock()
+	k.status = KeyStatus{Source: KeySourceIssuer, LastRefresh: time.Now()}
+	k.mu.Unlock()
+
+	k.persist(set)
+}
+
+func (k *keySource) failed(err error) {
+	slog.Warn("Failed to refresh JWKS", "issuer", k.issuer, "error", err)
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.status.Degraded = true
+	k.status.LastError = err.Error()
+}
+
+// persist writes the key set to the cache file if it changed
+func (k *keySource) persist(set jwk.Set) {
+	if k.opts.CacheFile == "" {
+		return
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		slog.Warn("Failed to encode JWKS for the cache file", "error", err)
+		return
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if bytes.Equal(data, k.persisted) {
+		return
+	}
+
+	// Write to a temporary file first, so a crash never leaves a truncated cache behind
+	tmp, err := os.CreateTemp(filepath.Dir(k.opts.CacheFile), ".jwks-*")
+	if err != nil {
+		slog.Warn("Failed to write JWKS cache file", "file", k.opts.CacheFile, "error", err)
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		slog.Warn("Failed to write JWKS cache file", "file", k.opts.CacheFile, "error", err)
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		slog.Warn("Failed to write JWKS cache file", "file", k.opts.CacheFile, "error", err)
+		return
+	}
+	if err := os.Rename(tmp.Name(), k.opts.CacheFile); err != nil {
+		slog.Warn("Failed to write JWKS cache file", "file", k.opts.CacheFile, "error", err)
+		return
+	}
+	k.persisted = data
+}
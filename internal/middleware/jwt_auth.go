@@ -5,15 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log/slog"
 	"net/http"
 	"slices"
 	"strings"
 	"time"
 
-	"github.com/getkin/kin-openapi/openapi3filter"
-	"github.com/lestrrat-go/httprc/v3"
-	"github.com/lestrrat-go/jwx/v3/jwk"
 	"github.com/lestrrat-go/jwx/v3/jwt"
 )
 
@@ -29,46 +25,72 @@ const (
 
 // JWTAuth holds the JWT authentication middleware state
 type JWTAuth struct {
-	issuer   string
-	audience string
-	cache    *jwk.Cache
-	jwksURL  string
+	issuer      string
+	audience    string
+	keys        *keySource
+	revocations RevocationChecker
+	dpop        *dpopVerifier // nil if DPoP was not configured, then it is disabled for every route
+}
+
+// RevocationChecker reports whether a token with a valid signature was revoked
+type RevocationChecker interface {
+	IsRevoked(token jwt.Token) bool
 }
 
 // NewJWTAuth creates a new JWT authentication middleware
 // issuer: the OIDC issuer URL
 // audience: the typically your client ID
-func NewJWTAuth(ctx context.Context, issuer, audience string) (*JWTAuth, error) {
-	if issuer == "" {
-		return nil, fmt.Errorf("issuer cannot be empty")
-	}
-
-	// Discover JWKS URL from .well-known endpoint
-	wellKnownURL := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
-	jwksURL, err := discoverJWKSURL(ctx, wellKnownURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to discover JWKS URL: %w", err)
+// opts: where the signing keys come from, the zero value discovers them from the issuer
+func NewJWTAuth(ctx context.Context, issuer, audience string, opts JWTAuthOptions) (*JWTAuth, error) {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = 15 * time.Minute
 	}
 
-	// Create a cache that auto-refreshes the JWKS
-	cache, err := jwk.NewCache(ctx, httprc.NewClient())
+	keys, err := newKeySource(ctx, issuer, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create JWKS cache: %w", err)
+		return nil, err
 	}
 
-	// Register the JWKS URL with auto-refresh
-	if err = cache.Register(ctx, jwksURL); err != nil {
-		return nil, fmt.Errorf("failed to register JWKS URL: %w", err)
-	}
+	// Keep retrying discovery while degraded and persist refreshed keys
+	go keys.run(ctx)
 
 	return &JWTAuth{
 		issuer:   issuer,
 		audience: audience,
-		cache:    cache,
-		jwksURL:  jwksURL,
+		keys:     keys,
 	}, nil
 }
 
+// KeyStatus reports where the signing keys come from and whether the issuer is reachable
+func (j *JWTAuth) KeyStatus() KeyStatus {
+	return j.keys.Status()
+}
+
+// UseRevocationChecker rejects tokens reported as revoked by the checker
+func (j *JWTAuth) UseRevocationChecker(checker RevocationChecker) {
+	j.revocations = checker
+}
+
+// UseDPoP enables DPoP (RFC 9449) sender-constrained tokens with the DPoP authorization scheme.
+// opts.Mode applies to routes without their own mode, see DPoPModes.
+func (j *JWTAuth) UseDPoP(opts DPoPOptions) {
+	if opts.Mode == "" {
+		opts.Mode = DPoPDisabled
+	}
+	j.dpop = newDPoPVerifier(opts)
+}
+
+// dpopMode returns the DPoP mode of the route, the configured one if the route has none
+func (j *JWTAuth) dpopMode(ctx context.Context) string {
+	if j.dpop == nil {
+		return DPoPDisabled
+	}
+	if mode, ok := ctx.Value(dpopModeContextKey).(string); ok {
+		return mode
+	}
+	return j.dpop.opts.Mode
+}
+
 // discoverJWKSURL fetches the OIDC discovery document and extracts the JWKS URL
 func discoverJWKSURL(ctx context.Context, wellKnownURL string) (string, error) {
 	client := &http.Client{Timeout: 10 * time.Second}
@@ -104,70 +126,94 @@ func discoverJWKSURL(ctx context.Context, wellKnownURL string) (string, error) {
 
 // Middleware returns the HTTP middleware handler
 func (j *JWTAuth) Middleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, `{"error": "missing authorization header"}`, http.StatusUnauthorized)
-			return
-		}
+	return Authenticate(j)(next)
+}
 
-		// Extract bearer token
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
-			http.Error(w, `{"error": "invalid authorization header format"}`, http.StatusUnauthorized)
-			return
-		}
-		tokenString := parts[1]
-
-		// Get the cached JWKS
-		keySet, err := j.cache.Lookup(r.Context(), j.jwksURL)
-		if err != nil {
-			slog.Error("failed to get JWKS", "error", err)
-			http.Error(w, `{"error": "Internal Server Error while Authorizing"}`, http.StatusInternalServerError)
-			return
+// Authenticate implements Authenticator for bearer and DPoP tokens
+func (j *JWTAuth) Authenticate(r *http.Request) (context.Context, error) {
+	mode := j.dpopMode(r.Context())
+	if mode != DPoPDisabled {
+		if tokenString, ok := authorizationCredentials(r, "dpop"); ok {
+			return j.authenticateDPoP(r, tokenString)
 		}
+	}
 
-		// Parse and validate token
-		parseOpts := []jwt.ParseOption{
-			jwt.WithKeySet(keySet),
-			// jwt.WithIssuer(j.issuer), // TODO: Reanable; Problem: With SSO a oidc provider may issue to different apps, so <issuer>/o/app1 or <issuer>/o/app2. At this moment it is not clear how to solve this
-			jwt.WithRequiredClaim(jwt.ExpirationKey),
-		}
+	// Extract bearer token
+	tokenString, ok := authorizationCredentials(r, "bearer")
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+	if mode == DPoPRequired {
+		return nil, &AuthError{Status: http.StatusUnauthorized, Message: "DPoP token required.", Challenge: dpopChallenge}
+	}
 
-		// Add audience validation if configured
-		if j.audience != "" {
-			parseOpts = append(parseOpts, jwt.WithAudience(j.audience))
-		}
+	token, err := j.parseToken(r.Context(), tokenString)
+	if err != nil {
+		return nil, err
+	}
 
-		token, err := jwt.ParseString(tokenString, parseOpts...)
-		if err != nil {
-			http.Error(w, `{"error": "invalid token."}`, http.StatusUnauthorized)
-			return
-		}
+	// A DPoP-bound token must not be usable as a plain bearer token
+	if _, bound := tokenJKT(token); bound && mode != DPoPDisabled {
+		return nil, &AuthError{Status: http.StatusUnauthorized, Message: "DPoP-bound token requires a DPoP proof.", Challenge: dpopChallenge}
+	}
 
-		// Add token to context
-		ctx := context.WithValue(r.Context(), ClaimsContextKey, token)
-		if sub, ok := token.Subject(); ok {
-			ctx = context.WithValue(ctx, SubjectContextKey, sub)
-		}
+	// Add token to context
+	return contextWithToken(r.Context(), token), nil
+}
 
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+// AuthenticateToken validates a bearer token that was not sent in the Authorization header,
+// e.g. by a WebSocket client. DPoP-bound tokens are rejected, there is no request to prove.
+func (j *JWTAuth) AuthenticateToken(ctx context.Context, tokenString string) (context.Context, error) {
+	mode := j.dpopMode(ctx)
+	if mode == DPoPRequired {
+		return nil, &AuthError{Status: http.StatusUnauthorized, Message: "DPoP token required.", Challenge: dpopChallenge}
+	}
+
+	token, err := j.parseToken(ctx, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if _, bound := tokenJKT(token); bound && mode != DPoPDisabled {
+		return nil, &AuthError{Status: http.StatusUnauthorized, Message: "DPoP-bound token requires a DPoP proof.", Challenge: dpopChallenge}
+	}
+
+	return contextWithToken(ctx, token), nil
 }
 
-// Returns middleware that is automatically applied to routes generated by oapi-codegen which are required to be authenticated
-// The biggest downside to this approch is that you cannot easily chain additional middlewares like RequireScope or RequireRole after this one
-// So instead of using this for complete JWT authentication it just checks if the token is present.
-// This just ensures oapi doesn't reject the request but we can still use the other validations from the package.
-func (j *JWTAuth) OAPIMiddleware(_ *JWTAuth) openapi3filter.AuthenticationFunc {
-	return func(_ context.Context, input *openapi3filter.AuthenticationInput) error {
-		// Extract Authorization header
-		authHeader := input.RequestValidationInput.Request.Header.Get("Authorization")
-		if authHeader == "" {
-			return errors.New("missing authorization header")
-		}
-		return nil
+// parseToken validates the signature and claims of an access token and checks the denylist
+func (j *JWTAuth) parseToken(ctx context.Context, tokenString string) (jwt.Token, error) {
+	// Get the cached JWKS
+	keySet, err := j.keys.keySet(ctx)
+	if errors.Is(err, errKeysUnavailable) {
+		return nil, &AuthError{Status: http.StatusServiceUnavailable, Message: "signing keys unavailable."}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get JWKS: %w", err)
+	}
+
+	// Parse and validate token
+	parseOpts := []jwt.ParseOption{
+		jwt.WithKeySet(keySet),
+		// jwt.WithIssuer(j.issuer), // TODO: Reanable; Problem: With SSO a oidc provider may issue to different apps, so <issuer>/o/app1 or <issuer>/o/app2. At this moment it is not clear how to solve this
+		jwt.WithRequiredClaim(jwt.ExpirationKey),
+	}
+
+	// Add audience validation if configured
+	if j.audience != "" {
+		parseOpts = append(parseOpts, jwt.WithAudience(j.audience))
+	}
+
+	token, err := jwt.ParseString(tokenString, parseOpts...)
+	if err != nil {
+		return nil, &AuthError{Status: http.StatusUnauthorized, Message: "invalid token."}
 	}
+
+	// Only consult the denylist after the signature was validated
+	if j.revocations != nil && j.revocations.IsRevoked(token) {
+		return nil, &AuthError{Status: http.StatusUnauthorized, Message: "token revoked."}
+	}
+
+	return token, nil
 }
 
 // GetToken extracts the JWT token from the request context
@@ -198,30 +244,36 @@ func GetClaim[T any](ctx context.Context, key string) (T, bool) {
 	return val, true
 }
 
+// HasScope reports whether the token in the context has the scope
+func HasScope(ctx context.Context, required string) bool {
+	token, ok := GetToken(ctx)
+	if !ok {
+		return false
+	}
+
+	var scopeStr string
+	if err := token.Get("scope", &scopeStr); err != nil {
+		return false
+	}
+	return slices.Contains(strings.Fields(scopeStr), required)
+}
+
 // RequireScope returns a middleware that checks if the token has the required scope
 // Requires the JWT middleware to be used first
 func RequireScope(required string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			token, ok := GetToken(r.Context())
-			if !ok {
-				http.Error(w, `{"error": "no token in context"}`, http.StatusUnauthorized)
-				return
-			}
-
-			var scopeStr string
-			if err := token.Get("scope", &scopeStr); err != nil {
-				http.Error(w, fmt.Sprintf(`{"error": "missing required scope: %s"}`, required), http.StatusForbidden)
+			if _, ok := GetToken(r.Context()); !ok {
+				writeError(w, r, http.StatusUnauthorized, "no token in context")
 				return
 			}
 
-			scopes := strings.Fields(scopeStr)
-			if slices.Contains(scopes, required) {
+			if HasScope(r.Context(), required) {
 				next.ServeHTTP(w, r)
 				return
 			}
 
-			http.Error(w, fmt.Sprintf(`{"error": "missing required scope: %s"}`, required), http.StatusForbidden)
+			writeError(w, r, http.StatusForbidden, "missing required scope: "+required)
 		})
 	}
 }
@@ -233,7 +285,7 @@ func RequireRole(claimName, required string) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			token, ok := GetToken(r.Context())
 			if !ok {
-				http.Error(w, `{"error": "no token in context"}`, http.StatusUnauthorized)
+				writeError(w, r, http.StatusUnauthorized, "no token in context")
 				return
 			}
 
@@ -257,7 +309,7 @@ func RequireRole(claimName, required string) func(http.Handler) http.Handler {
 				}
 			}
 
-			http.Error(w, fmt.Sprintf(`{"error": "missing required role: %s"}`, required), http.StatusForbidden)
+			writeError(w, r, http.StatusForbidden, "missing required role: "+required)
 		})
 	}
 }
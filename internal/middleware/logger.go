@@ -43,6 +43,11 @@ func (rw *responseWriter) Status() int {
 	return rw.status
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to flush streams
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // statusColor returns the ANSI color for a given HTTP status code
 func statusColor(status int) string {
 	switch {
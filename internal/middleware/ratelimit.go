@@ -0,0 +1,441 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+// RateLimitAlgorithm decides how requests are counted
+type RateLimitAlgorithm string
+
+const (
+	// TokenBucket allows bursts of up to the limit and refills the bucket evenly over the period
+	TokenBucket RateLimitAlgorithm = "token-bucket"
+	// SlidingWindow allows the limit within any period, approximated from the current and previous window
+	SlidingWindow RateLimitAlgorithm = "sliding-window"
+)
+
+// RateLimit allows Requests per Period
+type RateLimit struct {
+	Algorithm RateLimitAlgorithm
+	Requests  int
+	Period    time.Duration
+}
+
+// Validate checks that the limit can be enforced
+func (l RateLimit) Validate() error {
+	if l.Algorithm != TokenBucket && l.Algorithm != SlidingWindow {
+		return fmt.Errorf("algorithm must be %s or %s, got: %q", TokenBucket, SlidingWindow, l.Algorithm)
+	}
+	if l.Requests < 1 {
+		return fmt.Errorf("requests must be positive, got: %d", l.Requests)
+	}
+	if l.Period < time.Second {
+		return fmt.Errorf("period must be at least 1s, got: %s", l.Period)
+	}
+	return nil
+}
+
+// RateLimitResult is the outcome of a request
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	// Reset is the time until the limit is fully available again
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, if this one was not
+	RetryAfter time.Duration
+}
+
+// RateLimitStore keeps the state of the limits
+type RateLimitStore interface {
+	// Take counts a request of the key against the limit
+	Take(ctx context.Context, key string, limit RateLimit, now time.Time) (RateLimitResult, error)
+	// Peek returns the result a request of the key would have, without counting it
+	Peek(ctx context.Context, key string, limit RateLimit, now time.Time) (RateLimitResult, error)
+}
+
+// RateLimiter limits the requests of each client
+type RateLimiter struct {
+	store        RateLimitStore
+	defaultLimit RateLimit
+	authFailures RateLimit
+}
+
+// NewRateLimiter creates a rate limiter applying defaultLimit to routes without their own limit.
+// authFailures limits the failed authentications of each IP address, see AuthFailureMiddleware.
+func NewRateLimiter(store RateLimitStore, defaultLimit, authFailures RateLimit) *RateLimiter {
+	return &RateLimiter{
+		store:        store,
+		defaultLimit: defaultLimit,
+		authFailures: authFailures,
+	}
+}
+
+// DefaultLimit returns the limit of routes without their own limit
+func (l *RateLimiter) DefaultLimit() RateLimit {
+	return l.defaultLimit
+}
+
+// Middleware limits the requests of each client, identified by its API key, subject or IP address.
+// routes are limits of single routes, written as "METHOD /pattern" like they are registered, each counted
+// separately from the default limit. It must run after authentication and chi's RealIP.
+// If the store fails, requests are allowed.
+func (l *RateLimiter) Middleware(routes map[string]RateLimit) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope, limit := "default", l.defaultLimit
+			route := r.Method + " " + chi.RouteContext(r.Context()).RoutePattern()
+			if routeLimit, ok := routes[route]; ok {
+				scope, limit = route, routeLimit
+			}
+
+			l.serve(w, r, next, scope+"|"+rateLimitClient(r), limit)
+		})
+	}
+}
+
+// ConnectionMiddleware applies the default limit to each connection address, for public endpoints where
+// X-Forwarded-For would let callers choose their own key. Behind a proxy all requests share its address.
+// It needs ConnAddr before chi's RealIP. If the store fails, requests are allowed.
+func (l *RateLimiter) ConnectionMiddleware(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			l.serve(w, r, next, scope+"|"+connectionIP(r), l.defaultLimit)
+		})
+	}
+}
+
+// serve takes a request of key and passes it to next or answers 429
+func (l *RateLimiter) serve(w http.ResponseWriter, r *http.Request, next http.Handler, key string, limit RateLimit) {
+	result, err := l.store.Take(r.Context(), key, limit, time.Now())
+	if err != nil {
+		slog.Error("Failed to check rate limit", "error", err)
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, int(limit.Period.Seconds())))
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(limit.Requests))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+	if !result.Allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(max(1, ceilSeconds(result.RetryAfter))))
+		writeError(w, r, http.StatusTooManyRequests, "rate limit exceeded.")
+		return
+	}
+	next.ServeHTTP(w, r)
+}
+
+// AuthFailureMiddleware limits the failed authentications of each IP address, the requests answered with 401.
+// It must run before authentication, so the credentials of an address over the limit are not even checked and
+// guessing them costs no more than a rejected request. If the store fails, requests are allowed.
+func (l *RateLimiter) AuthFailureMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := "auth-failures|" + clientIP(r)
+			result, err := l.store.Peek(r.Context(), key, l.authFailures, time.Now())
+			if err != nil {
+				slog.Error("Failed to check auth failure rate limit", "error", err)
+			} else if !result.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(max(1, ceilSeconds(result.RetryAfter))))
+				writeError(w, r, http.StatusTooManyRequests, "too many failed authentications.")
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+			if ww.Status() != http.StatusUnauthorized {
+				return
+			}
+			if _, err := l.store.Take(context.WithoutCancel(r.Context()), key, l.authFailures, time.Now()); err != nil {
+				slog.Error("Failed to count auth failure", "error", err)
+			}
+		})
+	}
+}
+
+// rateLimitClient identifies the caller, API keys are limited separately from other credentials of their owner
+func rateLimitClient(r *http.Request) string {
+	if principal, ok := principalKey(r.Context()); ok {
+		return principal
+	}
+	return clientIP(r)
+}
+
+// clientIP identifies a caller without credentials by its IP address
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		// RealIP sets the address without port
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// connAddrContextKey is the key used to store the address of the connection in request context
+const connAddrContextKey contextKey = "conn_addr"
+
+// ConnAddr stores the address of the connection in the request context. It must run before chi's RealIP,
+// which replaces RemoteAddr with the address from X-Forwarded-For or X-Real-IP.
+func ConnAddr(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), connAddrContextKey, r.RemoteAddr)))
+	})
+}
+
+// connectionIP identifies a caller by the address of its connection, which unlike clientIP it cannot set itself
+func connectionIP(r *http.Request) string {
+	addr, ok := r.Context().Value(connAddrContextKey).(string)
+	if !ok {
+		addr = r.RemoteAddr
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	return "conn:" + host
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// rateLimitState is the state of a key, each algorithm uses its own fields
+type rateLimitState struct {
+	// Tokens are left in the bucket at UpdatedAt, a zero UpdatedAt is a full bucket
+	Tokens    float64
+	UpdatedAt time.Time
+	// Count and PrevCount are the requests of the window starting at WindowStart and the one before
+	WindowStart time.Time
+	Count       int
+	PrevCount   int
+}
+
+// take counts a request and updates the state
+func (l RateLimit) take(state *rateLimitState, now time.Time) RateLimitResult {
+	if l.Algorithm == SlidingWindow {
+		return l.takeSlidingWindow(state, now)
+	}
+	return l.takeTokenBucket(state, now)
+}
+
+func (l RateLimit) takeTokenBucket(state *rateLimitState, now time.Time) RateLimitResult {
+	capacity := float64(l.Requests)
+	perSecond := capacity / l.Period.Seconds()
+	if state.UpdatedAt.IsZero() {
+		state.Tokens = capacity
+	} else {
+		state.Tokens = min(capacity, state.Tokens+now.Sub(state.UpdatedAt).Seconds()*perSecond)
+	}
+	state.UpdatedAt = now
+
+	var result RateLimitResult
+	if state.Tokens >= 1 {
+		state.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - state.Tokens) / perSecond)
+	}
+	result.Remaining = int(state.Tokens)
+	result.Reset = seconds((capacity - state.Tokens) / perSecond)
+	return result
+}
+
+func (l RateLimit) takeSlidingWindow(state *rateLimitState, now time.Time) RateLimitResult {
+	windowStart := now.Truncate(l.Period)
+	switch {
+	case state.WindowStart.Equal(windowStart):
+	case state.WindowStart.Equal(windowStart.Add(-l.Period)):
+		state.PrevCount, state.Count = state.Count, 0
+	default:
+		state.PrevCount, state.Count = 0, 0
+	}
+	state.WindowStart = windowStart
+
+	// The previous window counts with the share of it that is still within the period
+	elapsed := now.Sub(windowStart)
+	period := l.Period.Seconds()
+	estimate := float64(state.PrevCount)*(1-elapsed.Seconds()/period) + float64(state.Count)
+
+	result := RateLimitResult{Reset: l.Period - elapsed}
+	allowed := float64(l.Requests - 1)
+	switch {
+	case estimate <= allowed:
+		state.Count++
+		estimate++
+		result.Allowed = true
+	case float64(state.Count) <= allowed:
+		// The previous window fades out far enough within this window
+		result.RetryAfter = seconds(period*(1-(allowed-float64(state.Count))/float64(state.PrevCount)) - elapsed.Seconds())
+	default:
+		// This window has to fade out within the next one
+		result.RetryAfter = seconds(period - elapsed.Seconds() + period*(1-allowed/float64(state.Count)))
+	}
+	result.Remaining = max(0, l.Requests-int(math.Ceil(estimate)))
+	return result
+}
+
+// expiresAt is when the state equals that of a new key
+func (l RateLimit) expiresAt(now time.Time) time.Time {
+	return now.Add(2 * l.Period)
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// MemoryRateLimitStore keeps the limits in memory, each replica limits on its own
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	states    map[string]*memoryRateLimit
+	lastSweep time.Time
+}
+
+type memoryRateLimit struct {
+	state     rateLimitState
+	expiresAt time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		states: map[string]*memoryRateLimit{},
+	}
+}
+
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, limit RateLimit, now time.Time) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= time.Minute {
+		s.lastSweep = now
+		for key, entry := range s.states {
+			if now.After(entry.expiresAt) {
+				delete(s.states, key)
+			}
+		}
+	}
+
+	entry, ok := s.states[key]
+	if !ok || now.After(entry.expiresAt) {
+		entry = &memoryRateLimit{}
+		s.states[key] = entry
+	}
+	result := limit.take(&entry.state, now)
+	entry.expiresAt = limit.expiresAt(now)
+	return result, nil
+}
+
+func (s *MemoryRateLimitStore) Peek(_ context.Context, key string, limit RateLimit, now time.Time) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var state rateLimitState
+	if entry, ok := s.states[key]; ok && !now.After(entry.expiresAt) {
+		state = entry.state
+	}
+	return limit.take(&state, now), nil
+}
+
+// PostgresRateLimitStore keeps the limits in the rate_limits table, shared by all replicas
+type PostgresRateLimitStore struct {
+	db      *pgxpool.Pool
+	queries *repository.Queries
+}
+
+func NewPostgresRateLimitStore(db *pgxpool.Pool, queries *repository.Queries) *PostgresRateLimitStore {
+	return &PostgresRateLimitStore{
+		db:      db,
+		queries: queries,
+	}
+}
+
+func (s *PostgresRateLimitStore) Take(ctx context.Context, key string, limit RateLimit, now time.Time) (RateLimitResult, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	defer tx.Rollback(context.WithoutCancel(ctx))
+	queries := s.queries.WithTx(tx)
+
+	dbLimit, err := queries.LockRateLimit(ctx, repository.LockRateLimitParams{
+		Key:       key,
+		ExpiresAt: limit.expiresAt(now),
+	})
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	state := rateLimitState{
+		Tokens:      dbLimit.Tokens,
+		UpdatedAt:   dbLimit.UpdatedAt.Time,
+		WindowStart: dbLimit.WindowStart.Time,
+		Count:       int(dbLimit.Count),
+		PrevCount:   int(dbLimit.PrevCount),
+	}
+	result := limit.take(&state, now)
+
+	err = queries.UpdateRateLimit(ctx, repository.UpdateRateLimitParams{
+		Tokens:      state.Tokens,
+		UpdatedAt:   optionalTimestamptz(state.UpdatedAt),
+		WindowStart: optionalTimestamptz(state.WindowStart),
+		Count:       int32(state.Count),
+		PrevCount:   int32(state.PrevCount),
+		ExpiresAt:   limit.expiresAt(now),
+		Key:         key,
+	})
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	return result, tx.Commit(ctx)
+}
+
+func (s *PostgresRateLimitStore) Peek(ctx context.Context, key string, limit RateLimit, now time.Time) (RateLimitResult, error) {
+	var state rateLimitState
+	dbLimit, err := s.queries.GetRateLimit(ctx, key)
+	switch {
+	case err == nil:
+		state = rateLimitState{
+			Tokens:      dbLimit.Tokens,
+			UpdatedAt:   dbLimit.UpdatedAt.Time,
+			WindowStart: dbLimit.WindowStart.Time,
+			Count:       int(dbLimit.Count),
+			PrevCount:   int(dbLimit.PrevCount),
+		}
+	case !errors.Is(err, pgx.ErrNoRows):
+		return RateLimitResult{}, err
+	}
+	// The state is a copy, taking from it counts nothing
+	return limit.take(&state, now), nil
+}
+
+// DeleteExpired deletes the state of clients that have not sent requests for a while. Register it as scheduled task.
+func (s *PostgresRateLimitStore) DeleteExpired(ctx context.Context) error {
+	deleted, err := s.queries.DeleteExpiredRateLimits(ctx)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		slog.Debug("Deleted expired rate limits", "count", deleted)
+	}
+	return nil
+}
+
+func optionalTimestamptz(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: !t.IsZero()}
+}
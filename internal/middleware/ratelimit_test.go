@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// rateLimitStep is a request at offset from the start, checked against the result of the limit
+type rateLimitStep struct {
+	offset         time.Duration
+	wantAllowed    bool
+	wantRemaining  int
+	wantRetryAfter time.Duration
+}
+
+// checkRateLimitSteps takes the steps in order on one state
+func checkRateLimitSteps(t *testing.T, limit RateLimit, steps []rateLimitStep) {
+	t.Helper()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var state rateLimitState
+	for i, step := range steps {
+		result := limit.take(&state, start.Add(step.offset))
+		if result.Allowed != step.wantAllowed || result.Remaining != step.wantRemaining {
+			t.Errorf("step %d at %v: allowed = %v, remaining = %d, want %v, %d",
+				i, step.offset, result.Allowed, result.Remaining, step.wantAllowed, step.wantRemaining)
+		}
+		// Float arithmetic can be off by a nanosecond
+		if diff := (result.RetryAfter - step.wantRetryAfter).Abs(); diff > time.Millisecond {
+			t.Errorf("step %d at %v: retry after = %v, want %v", i, step.offset, result.RetryAfter, step.wantRetryAfter)
+		}
+	}
+}
+
+// burst is n allowed requests at offset, counting the remaining requests down from remaining
+func burst(offset time.Duration, n, remaining int) []rateLimitStep {
+	steps := make([]rateLimitStep, n)
+	for i := range steps {
+		steps[i] = rateLimitStep{offset: offset, wantAllowed: true, wantRemaining: remaining - 1 - i}
+	}
+	return steps
+}
+
+func TestTakeTokenBucket(t *testing.T) {
+	// One token per second
+	limit := RateLimit{Algorithm: TokenBucket, Requests: 10, Period: 10 * time.Second}
+
+	tests := []struct {
+		name  string
+		steps []rateLimitStep
+	}{
+		{"first request gets a full bucket", []rateLimitStep{{0, true, 9, 0}}},
+		{"empty bucket", append(burst(0, 10, 10), rateLimitStep{0, false, 0, time.Second})},
+		{"half a token is not enough", append(burst(0, 10, 10),
+			rateLimitStep{500 * time.Millisecond, false, 0, 500 * time.Millisecond},
+		)},
+		{"exactly one token refilled", append(burst(0, 10, 10),
+			rateLimitStep{time.Second, true, 0, 0},
+			rateLimitStep{time.Second, false, 0, time.Second},
+		)},
+		{"rejected requests do not use tokens", append(burst(0, 10, 10),
+			rateLimitStep{0, false, 0, time.Second},
+			rateLimitStep{500 * time.Millisecond, false, 0, 500 * time.Millisecond},
+			rateLimitStep{time.Second, true, 0, 0},
+		)},
+		{"partial refill", append(burst(0, 10, 10),
+			rateLimitStep{3500 * time.Millisecond, true, 2, 0},
+		)},
+		{"refill is capped at the limit", append(burst(0, 10, 10),
+			append([]rateLimitStep{{time.Minute, true, 9, 0}}, burst(time.Minute, 9, 9)...)...,
+		)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkRateLimitSteps(t, limit, tt.steps)
+		})
+	}
+}
+
+func TestTakeSlidingWindow(t *testing.T) {
+	limit := RateLimit{Algorithm: SlidingWindow, Requests: 10, Period: 10 * time.Second}
+
+	tests := []struct {
+		name  string
+		steps []rateLimitStep
+	}{
+		{"first request", []rateLimitStep{{0, true, 9, 0}}},
+		{"full window waits for the next one to fade it out", append(burst(0, 10, 10),
+			rateLimitStep{0, false, 0, 11 * time.Second},
+		)},
+		{"previous window counts fully at its end", append(burst(0, 10, 10),
+			rateLimitStep{10 * time.Second, false, 0, time.Second},
+		)},
+		{"previous window fades out", append(burst(0, 10, 10),
+			rateLimitStep{11 * time.Second, true, 0, 0},
+		)},
+		{"previous window is gone after a period", append(burst(0, 10, 10),
+			burst(20*time.Second, 10, 10)...,
+		)},
+		{"idle for longer than two windows", append(burst(0, 5, 10),
+			rateLimitStep{time.Minute, true, 9, 0},
+		)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkRateLimitSteps(t, limit, tt.steps)
+		})
+	}
+}
+
+func TestMemoryRateLimitStorePeek(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryRateLimitStore()
+	limit := RateLimit{Algorithm: TokenBucket, Requests: 2, Period: time.Minute}
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		peek        bool
+		wantAllowed bool
+	}{
+		{"peek at a new key", true, true},
+		{"peeking does not count", true, true},
+		{"first request", false, true},
+		{"second request", false, true},
+		{"peek at the empty bucket", true, false},
+		{"third request", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			take := store.Take
+			if tt.peek {
+				take = store.Peek
+			}
+			result, err := take(ctx, "key", limit, now)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if result.Allowed != tt.wantAllowed {
+				t.Errorf("allowed = %v, want %v", result.Allowed, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestConnectionIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		connAddr   string
+		remoteAddr string
+		want       string
+	}{
+		{"connection address", "10.0.0.1:4711", "203.0.113.7", "conn:10.0.0.1"},
+		{"IPv6", "[2001:db8::1]:4711", "203.0.113.7", "conn:2001:db8::1"},
+		{"without ConnAddr", "", "10.0.0.1:4711", "conn:10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// RealIP replaces RemoteAddr after ConnAddr stored it
+				r.RemoteAddr = tt.remoteAddr
+				got = connectionIP(r)
+			})
+			r := httptest.NewRequest(http.MethodPost, "/csp-reports", nil)
+			if tt.connAddr != "" {
+				r.RemoteAddr = tt.connAddr
+				ConnAddr(handler).ServeHTTP(httptest.NewRecorder(), r)
+			} else {
+				r.RemoteAddr = tt.remoteAddr
+				handler.ServeHTTP(httptest.NewRecorder(), r)
+			}
+			if got != tt.want {
+				t.Errorf("connectionIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
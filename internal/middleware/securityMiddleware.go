@@ -1,14 +1,108 @@
 package middleware
 
-import "net/http"
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
 
-// SecurityHeaders sets security-related HTTP headers
-func SecurityHeaders(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("X-Content-Type-Options", "nosniff")
-		w.Header().Set("X-Frame-Options", "DENY")
-		w.Header().Set("X-XSS-Protection", "1; mode=block")
+// SecurityPolicy configures the security headers, empty values omit a header
+type SecurityPolicy struct {
+	// HSTSMaxAge enables Strict-Transport-Security on HTTPS requests, zero omits it
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+
+	// ContentSecurityPolicy applies to all routes except those of CSPRoutes, a map of path prefixes to their
+	// policy, e.g. for a Swagger UI that needs inline scripts. The longest matching prefix wins.
+	ContentSecurityPolicy string
+	CSPRoutes             map[string]string
+	// CSPReportOnly sends the policies as Content-Security-Policy-Report-Only, violations are reported but not blocked
+	CSPReportOnly bool
+	// CSPReportURI is added to the policies as report-uri
+	CSPReportURI string
+
+	ReferrerPolicy            string
+	PermissionsPolicy         string
+	CrossOriginOpenerPolicy   string
+	CrossOriginEmbedderPolicy string
+	CrossOriginResourcePolicy string
+}
 
-		next.ServeHTTP(w, r)
+// SecurityHeaders sets security-related HTTP headers
+func SecurityHeaders(policy SecurityPolicy) func(http.Handler) http.Handler {
+	cspHeader := "Content-Security-Policy"
+	if policy.CSPReportOnly {
+		cspHeader = "Content-Security-Policy-Report-Only"
+	}
+	csp := withReportURI(policy.ContentSecurityPolicy, policy.CSPReportURI)
+	routeCSP := map[string]string{}
+	prefixes := make([]string, 0, len(policy.CSPRoutes))
+	for prefix, routePolicy := range policy.CSPRoutes {
+		routeCSP[prefix] = withReportURI(routePolicy, policy.CSPReportURI)
+		prefixes = append(prefixes, prefix)
+	}
+	// Longest prefix first
+	slices.SortFunc(prefixes, func(a, b string) int {
+		return len(b) - len(a)
 	})
+
+	hsts := ""
+	if policy.HSTSMaxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d", int(policy.HSTSMaxAge.Seconds()))
+		if policy.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if policy.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+
+	headers := map[string]string{
+		"X-Content-Type-Options":       "nosniff",
+		"X-Frame-Options":              "DENY",
+		"Referrer-Policy":              policy.ReferrerPolicy,
+		"Permissions-Policy":           policy.PermissionsPolicy,
+		"Cross-Origin-Opener-Policy":   policy.CrossOriginOpenerPolicy,
+		"Cross-Origin-Embedder-Policy": policy.CrossOriginEmbedderPolicy,
+		"Cross-Origin-Resource-Policy": policy.CrossOriginResourcePolicy,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			for name, value := range headers {
+				if value != "" {
+					header.Set(name, value)
+				}
+			}
+
+			// Browsers ignore Strict-Transport-Security over plain HTTP, a proxy terminating TLS sets X-Forwarded-Proto
+			if hsts != "" && (r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https") {
+				header.Set("Strict-Transport-Security", hsts)
+			}
+
+			policy := csp
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					policy = routeCSP[prefix]
+					break
+				}
+			}
+			if policy != "" {
+				header.Set(cspHeader, policy)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func withReportURI(policy, reportURI string) string {
+	if policy == "" || reportURI == "" {
+		return policy
+	}
+	return strings.TrimSuffix(strings.TrimSpace(policy), ";") + "; report-uri " + reportURI
 }
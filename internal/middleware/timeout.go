@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// timeoutWriteGrace is the time left after the deadline to write the response
+const timeoutWriteGrace = 5 * time.Second
+
+// Timeout sets a deadline on the request context, which cancels the database queries of the handler.
+// routes are timeouts of single routes, written as "METHOD /pattern" like they are registered, a zero
+// timeout disables the deadline, e.g. for streams. A handler failing with a server error after the deadline
+// is answered with 504. The connection's read and write deadlines are moved to the route's deadline,
+// so that routes may take longer than the server's timeouts.
+func Timeout(defaultTimeout time.Duration, routes map[string]time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := r.Method + " " + chi.RouteContext(r.Context()).RoutePattern()
+			timeout, ok := routes[route]
+			if !ok {
+				timeout = defaultTimeout
+			}
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			deadline, _ := ctx.Deadline()
+
+			// Not every writer supports deadlines, the server's timeouts apply then
+			controller := http.NewResponseController(w)
+			_ = controller.SetReadDeadline(deadline)
+			_ = controller.SetWriteDeadline(deadline.Add(timeoutWriteGrace))
+
+			tw := &timeoutWriter{ResponseWriter: w, ctx: ctx}
+			next.ServeHTTP(tw, r.WithContext(ctx))
+
+			if tw.timedOut || (!tw.wroteHeader && errors.Is(ctx.Err(), context.DeadlineExceeded)) {
+				slog.Warn("Request timed out", "route", route, "timeout", timeout)
+				writeError(w, r, http.StatusGatewayTimeout, "request timed out.")
+			}
+		})
+	}
+}
+
+// timeoutWriter drops server errors written after the deadline, the middleware answers with 504 instead.
+// Responses that succeeded despite the deadline are passed through.
+type timeoutWriter struct {
+	http.ResponseWriter
+	ctx         context.Context
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (w *timeoutWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if status >= http.StatusInternalServerError && errors.Is(w.ctx.Err(), context.DeadlineExceeded) {
+		w.timedOut = true
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.timedOut {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer
+func (w *timeoutWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,114 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// MultiPublisher publishes every message to all publishers. If one of them fails,
+// the message is published to all of them again.
+type MultiPublisher []Publisher
+
+func (m MultiPublisher) Publish(ctx context.Context, message Message) error {
+	for _, publisher := range m {
+		if err := publisher.Publish(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LogPublisher writes messages to the log, e.g. for development
+type LogPublisher struct{}
+
+func (LogPublisher) Publish(ctx context.Context, message Message) error {
+	slog.InfoContext(ctx, "outbox event",
+		"id", message.ID,
+		"type", message.Type,
+		"aggregate_type", message.AggregateType,
+		"aggregate_id", message.AggregateID,
+		"data", string(message.Data),
+	)
+	return nil
+}
+
+// WebhookPublisher POSTs every message as JSON to a URL. Any status other than 2xx is a failure.
+type WebhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookPublisher(url string, timeout time.Duration) *WebhookPublisher {
+	return &WebhookPublisher{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *WebhookPublisher) Publish(ctx context.Context, message Message) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Receivers deduplicate redeliveries by the event ID
+	req.Header.Set("X-Event-Id", message.ID.String())
+	req.Header.Set("X-Event-Type", message.Type)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// FilePublisher appends every message as a JSON line to a file, e.g. for tests
+type FilePublisher struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFilePublisher(path string) *FilePublisher {
+	return &FilePublisher{
+		path: path,
+	}
+}
+
+func (p *FilePublisher) Publish(_ context.Context, message Message) error {
+	line, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	file, err := os.OpenFile(p.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
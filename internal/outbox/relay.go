@@ -0,0 +1,167 @@
+// Package outbox publishes domain events that were written to the outbox_events table in the
+// transaction of the mutation, so an event is published if and only if the change was committed.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+// Event types of the user aggregate
+const (
+	UserCreated = "user.created"
+	UserUpdated = "user.updated"
+	UserDeleted = "user.deleted"
+)
+
+const (
+	minRetryDelay = time.Second
+	maxRetryDelay = time.Hour
+)
+
+// Message is an event as it is handed to publishers
+type Message struct {
+	ID            uuid.UUID       `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// Publisher delivers messages to a sink. Delivery is at least once, so a message can be published
+// again if the relay stops before it was marked as published. Consumers deduplicate by ID.
+type Publisher interface {
+	Publish(ctx context.Context, message Message) error
+}
+
+// Relay moves events from the outbox to a publisher. Every replica can run a relay,
+// events are claimed with FOR UPDATE SKIP LOCKED.
+type Relay struct {
+	db        *pgxpool.Pool
+	publisher Publisher
+	batchSize int32
+	retention time.Duration
+}
+
+// NewRelay creates a relay publishing up to batchSize events per transaction.
+// Published events are kept for retention.
+func NewRelay(db *pgxpool.Pool, publisher Publisher, batchSize int32, retention time.Duration) *Relay {
+	return &Relay{
+		db:        db,
+		publisher: publisher,
+		batchSize: batchSize,
+		retention: retention,
+	}
+}
+
+// RelayOnce publishes one batch of pending events and returns how many were claimed.
+// Failed events are retried with exponential backoff, later events of their aggregate wait for them.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+	queries := repository.New(tx)
+
+	events, err := queries.ClaimOutboxEvents(ctx, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		publishErr := r.publisher.Publish(ctx, toMessage(event))
+		if publishErr == nil {
+			err = queries.MarkOutboxEventPublished(ctx, event.EventID)
+		} else {
+			delay := retryDelay(event.Attempts)
+			slog.Warn("Failed to publish outbox event", "event_id", event.EventID, "type", event.EventType, "attempts", event.Attempts+1, "retry_in", delay, "error", publishErr)
+			err = queries.FailOutboxEvent(ctx, repository.FailOutboxEventParams{
+				LastError:     pgtype.Text{String: publishErr.Error(), Valid: true},
+				NextAttemptAt: time.Now().Add(delay),
+				EventID:       event.EventID,
+			})
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(events), tx.Commit(ctx)
+}
+
+// Run relays events every interval until ctx is done. Full batches are followed by the next batch right away.
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				claimed, err := r.RelayOnce(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						slog.Error("Failed to relay outbox events", "error", err)
+					}
+					break
+				}
+				if claimed < int(r.batchSize) {
+					break
+				}
+			}
+
+			if time.Since(lastCleanup) >= time.Hour {
+				lastCleanup = time.Now()
+				r.cleanup(ctx)
+			}
+		}
+	}
+}
+
+// cleanup deletes published events older than the retention
+func (r *Relay) cleanup(ctx context.Context) {
+	deleted, err := repository.New(r.db).DeletePublishedOutboxEvents(ctx, pgtype.Timestamptz{Time: time.Now().Add(-r.retention), Valid: true})
+	if err != nil {
+		slog.Error("Failed to delete published outbox events", "error", err)
+		return
+	}
+	if deleted > 0 {
+		slog.Debug("Deleted published outbox events", "count", deleted)
+	}
+}
+
+// retryDelay doubles the delay with every failed attempt, up to maxRetryDelay
+func retryDelay(attempts int32) time.Duration {
+	delay := minRetryDelay
+	for range attempts {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
+
+func toMessage(event repository.OutboxEvent) Message {
+	return Message{
+		ID:            event.EventID,
+		Type:          event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		OccurredAt:    event.CreatedAt,
+		Data:          event.Payload,
+	}
+}
@@ -0,0 +1,124 @@
+// Package pgnotify dispatches Postgres LISTEN/NOTIFY notifications to in-process handlers.
+// It keeps a dedicated connection from the pool and reconnects when the connection is lost.
+package pgnotify
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Handler receives the payload of a notification
+type Handler func(payload string)
+
+// Listener listens on a set of channels and dispatches notifications to the subscribed handlers
+type Listener struct {
+	pool *pgxpool.Pool
+
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	onListen []func()
+}
+
+// NewListener creates a new listener. Subscribe to channels before calling Run.
+func NewListener(pool *pgxpool.Pool) *Listener {
+	return &Listener{
+		pool:     pool,
+		handlers: map[string][]Handler{},
+	}
+}
+
+// Subscribe registers a handler for a channel. Handlers are called sequentially and must not block.
+func (l *Listener) Subscribe(channel string, handler Handler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handlers[channel] = append(l.handlers[channel], handler)
+}
+
+// OnListen registers a function that is called each time LISTEN is established, the first time and after
+// every reconnect. Notifications sent before are lost, so subscribers should resynchronize their state here.
+// Like handlers, it must not block.
+func (l *Listener) OnListen(fn func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onListen = append(l.onListen, fn)
+}
+
+// Run listens until the context is canceled
+func (l *Listener) Run(ctx context.Context) {
+	backoff := time.Second
+
+	for ctx.Err() == nil {
+		err := l.listen(ctx, func() {
+			l.listening()
+			backoff = time.Second
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		slog.Warn("Lost LISTEN connection, reconnecting", "error", err, "backoff", backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+func (l *Listener) listen(ctx context.Context, onListening func()) error {
+	pooled, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The connection stays in LISTEN state, so take it out of the pool instead of releasing it
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	l.mu.RLock()
+	channels := make([]string, 0, len(l.handlers))
+	for channel := range l.handlers {
+		channels = append(channels, channel)
+	}
+	l.mu.RUnlock()
+
+	for _, channel := range channels {
+		if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return err
+		}
+	}
+	onListening()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		l.dispatch(notification.Channel, notification.Payload)
+	}
+}
+
+func (l *Listener) dispatch(channel, payload string) {
+	l.mu.RLock()
+	handlers := l.handlers[channel]
+	l.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(payload)
+	}
+}
+
+func (l *Listener) listening() {
+	l.mu.RLock()
+	callbacks := l.onListen
+	l.mu.RUnlock()
+
+	for _, fn := range callbacks {
+		fn()
+	}
+}
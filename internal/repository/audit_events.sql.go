@@ -0,0 +1,107 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: audit_events.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const insertAuditEvent = `-- name: InsertAuditEvent :exec
+INSERT INTO audit_events (actor, actor_type, action, resource_type, resource_id, before, after, request_id, ip)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+`
+
+type InsertAuditEventParams struct {
+	Actor        string `json:"actor"`
+	ActorType    string `json:"actor_type"`
+	Action       string `json:"action"`
+	ResourceType string `json:"resource_type"`
+	ResourceID   string `json:"resource_id"`
+	Before       []byte `json:"before"`
+	After        []byte `json:"after"`
+	RequestID    string `json:"request_id"`
+	Ip           string `json:"ip"`
+}
+
+func (q *Queries) InsertAuditEvent(ctx context.Context, arg InsertAuditEventParams) error {
+	_, err := q.db.Exec(ctx, insertAuditEvent,
+		arg.Actor,
+		arg.ActorType,
+		arg.Action,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.Before,
+		arg.After,
+		arg.RequestID,
+		arg.Ip,
+	)
+	return err
+}
+
+const listAuditEvents = `-- name: ListAuditEvents :many
+SELECT event_id, actor, actor_type, action, resource_type, resource_id, before, after, request_id, ip, created_at FROM audit_events
+WHERE ($1::text IS NULL OR actor = $1)
+  AND ($2::text IS NULL OR resource_type = $2)
+  AND ($3::text IS NULL OR resource_id = $3)
+  AND ($4::timestamptz IS NULL OR created_at >= $4)
+  AND ($5::timestamptz IS NULL OR created_at < $5)
+  AND ($6::uuid IS NULL OR event_id < $6)
+ORDER BY event_id DESC
+LIMIT $7
+`
+
+type ListAuditEventsParams struct {
+	Actor        pgtype.Text        `json:"actor"`
+	ResourceType pgtype.Text        `json:"resource_type"`
+	ResourceID   pgtype.Text        `json:"resource_id"`
+	Since        pgtype.Timestamptz `json:"since"`
+	Until        pgtype.Timestamptz `json:"until"`
+	Cursor       pgtype.UUID        `json:"cursor"`
+	MaxResults   int32              `json:"max_results"`
+}
+
+// Newest first. The cursor is the event_id of the last event of the previous page.
+func (q *Queries) ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error) {
+	rows, err := q.db.Query(ctx, listAuditEvents,
+		arg.Actor,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.Since,
+		arg.Until,
+		arg.Cursor,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditEvent
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.EventID,
+			&i.Actor,
+			&i.ActorType,
+			&i.Action,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.Before,
+			&i.After,
+			&i.RequestID,
+			&i.Ip,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
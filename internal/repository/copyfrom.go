@@ -0,0 +1,45 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: copyfrom.go
+
+package repository
+
+import (
+	"context"
+)
+
+// iteratorForCopyUsers implements pgx.CopyFromSource.
+type iteratorForCopyUsers struct {
+	rows                 []CopyUsersParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCopyUsers) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCopyUsers) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].UserID,
+		r.rows[0].Email,
+		r.rows[0].FirstName,
+		r.rows[0].LastName,
+	}, nil
+}
+
+func (r iteratorForCopyUsers) Err() error {
+	return nil
+}
+
+func (q *Queries) CopyUsers(ctx context.Context, arg []CopyUsersParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"users"}, []string{"user_id", "email", "first_name", "last_name"}, &iteratorForCopyUsers{rows: arg})
+}
@@ -0,0 +1,142 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: idempotency_keys.sql
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimIdempotencyKey = `-- name: ClaimIdempotencyKey :one
+INSERT INTO idempotency_keys (owner, key, fingerprint, expires_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (owner, key) DO UPDATE
+SET fingerprint      = EXCLUDED.fingerprint,
+    status_code      = NULL,
+    response_headers = NULL,
+    response_body    = NULL,
+    expires_at       = EXCLUDED.expires_at,
+    created_at       = now()
+WHERE idempotency_keys.expires_at < now()
+RETURNING owner, key, fingerprint, status_code, response_headers, response_body, expires_at, created_at
+`
+
+type ClaimIdempotencyKeyParams struct {
+	Owner       string    `json:"owner"`
+	Key         string    `json:"key"`
+	Fingerprint string    `json:"fingerprint"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// Inserts the key for a new request, or takes over an expired one. Returns no row if the key is in use.
+func (q *Queries) ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, claimIdempotencyKey,
+		arg.Owner,
+		arg.Key,
+		arg.Fingerprint,
+		arg.ExpiresAt,
+	)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Owner,
+		&i.Key,
+		&i.Fingerprint,
+		&i.StatusCode,
+		&i.ResponseHeaders,
+		&i.ResponseBody,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const completeIdempotencyKey = `-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code      = $1,
+    response_headers = $2,
+    response_body    = $3,
+    expires_at       = $4
+WHERE owner = $5 AND key = $6 AND fingerprint = $7
+`
+
+type CompleteIdempotencyKeyParams struct {
+	StatusCode      pgtype.Int4 `json:"status_code"`
+	ResponseHeaders []byte      `json:"response_headers"`
+	ResponseBody    []byte      `json:"response_body"`
+	ExpiresAt       time.Time   `json:"expires_at"`
+	Owner           string      `json:"owner"`
+	Key             string      `json:"key"`
+	Fingerprint     string      `json:"fingerprint"`
+}
+
+func (q *Queries) CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) error {
+	_, err := q.db.Exec(ctx, completeIdempotencyKey,
+		arg.StatusCode,
+		arg.ResponseHeaders,
+		arg.ResponseBody,
+		arg.ExpiresAt,
+		arg.Owner,
+		arg.Key,
+		arg.Fingerprint,
+	)
+	return err
+}
+
+const deleteExpiredIdempotencyKeys = `-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys WHERE expires_at < now()
+`
+
+func (q *Queries) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredIdempotencyKeys)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT owner, key, fingerprint, status_code, response_headers, response_body, expires_at, created_at FROM idempotency_keys WHERE owner = $1 AND key = $2
+`
+
+type GetIdempotencyKeyParams struct {
+	Owner string `json:"owner"`
+	Key   string `json:"key"`
+}
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, getIdempotencyKey, arg.Owner, arg.Key)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Owner,
+		&i.Key,
+		&i.Fingerprint,
+		&i.StatusCode,
+		&i.ResponseHeaders,
+		&i.ResponseBody,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const releaseIdempotencyKey = `-- name: ReleaseIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE owner = $1 AND key = $2 AND fingerprint = $3 AND status_code IS NULL
+`
+
+type ReleaseIdempotencyKeyParams struct {
+	Owner       string `json:"owner"`
+	Key         string `json:"key"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// Lets the client retry a request that failed, only while it is still in progress.
+func (q *Queries) ReleaseIdempotencyKey(ctx context.Context, arg ReleaseIdempotencyKeyParams) error {
+	_, err := q.db.Exec(ctx, releaseIdempotencyKey, arg.Owner, arg.Key, arg.Fingerprint)
+	return err
+}
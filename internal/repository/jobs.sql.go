@@ -0,0 +1,166 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: jobs.sql
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimJobs = `-- name: ClaimJobs :many
+UPDATE jobs
+SET status = 'running', attempts = attempts + 1, locked_until = $1
+WHERE job_id IN (
+    SELECT job_id FROM jobs j
+    WHERE j.kind = ANY($2::text[])
+      AND ((j.status = 'available' AND j.run_at <= now())
+        OR (j.status = 'running' AND j.locked_until < now()))
+    ORDER BY j.run_at, j.job_id
+    LIMIT $3
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING job_id, kind, args, status, unique_key, attempts, max_attempts, run_at, locked_until, last_error, finished_at, created_at
+`
+
+type ClaimJobsParams struct {
+	LockedUntil pgtype.Timestamptz `json:"locked_until"`
+	Kinds       []string           `json:"kinds"`
+	MaxResults  int32              `json:"max_results"`
+}
+
+// Leases due jobs of the given kinds, including running jobs whose lease expired because their worker stopped.
+func (q *Queries) ClaimJobs(ctx context.Context, arg ClaimJobsParams) ([]Job, error) {
+	rows, err := q.db.Query(ctx, claimJobs, arg.LockedUntil, arg.Kinds, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Job
+	for rows.Next() {
+		var i Job
+		if err := rows.Scan(
+			&i.JobID,
+			&i.Kind,
+			&i.Args,
+			&i.Status,
+			&i.UniqueKey,
+			&i.Attempts,
+			&i.MaxAttempts,
+			&i.RunAt,
+			&i.LockedUntil,
+			&i.LastError,
+			&i.FinishedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const completeJob = `-- name: CompleteJob :exec
+UPDATE jobs
+SET status = 'completed', locked_until = NULL, last_error = NULL, finished_at = now()
+WHERE job_id = $1
+`
+
+func (q *Queries) CompleteJob(ctx context.Context, jobID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, completeJob, jobID)
+	return err
+}
+
+const deleteFinishedJobs = `-- name: DeleteFinishedJobs :execrows
+DELETE FROM jobs WHERE finished_at < $1
+`
+
+func (q *Queries) DeleteFinishedJobs(ctx context.Context, finishedBefore pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteFinishedJobs, finishedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const discardJob = `-- name: DiscardJob :exec
+UPDATE jobs
+SET status = 'discarded', locked_until = NULL, last_error = $1, finished_at = now()
+WHERE job_id = $2
+`
+
+type DiscardJobParams struct {
+	LastError pgtype.Text `json:"last_error"`
+	JobID     uuid.UUID   `json:"job_id"`
+}
+
+func (q *Queries) DiscardJob(ctx context.Context, arg DiscardJobParams) error {
+	_, err := q.db.Exec(ctx, discardJob, arg.LastError, arg.JobID)
+	return err
+}
+
+const insertJob = `-- name: InsertJob :execrows
+INSERT INTO jobs (kind, args, unique_key, max_attempts, run_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (kind, unique_key) WHERE unique_key IS NOT NULL AND status IN ('available', 'running') DO NOTHING
+`
+
+type InsertJobParams struct {
+	Kind        string      `json:"kind"`
+	Args        []byte      `json:"args"`
+	UniqueKey   pgtype.Text `json:"unique_key"`
+	MaxAttempts int32       `json:"max_attempts"`
+	RunAt       time.Time   `json:"run_at"`
+}
+
+// Returns 0 if an available or running job with the same kind and unique key exists.
+func (q *Queries) InsertJob(ctx context.Context, arg InsertJobParams) (int64, error) {
+	result, err := q.db.Exec(ctx, insertJob,
+		arg.Kind,
+		arg.Args,
+		arg.UniqueKey,
+		arg.MaxAttempts,
+		arg.RunAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const releaseJob = `-- name: ReleaseJob :exec
+UPDATE jobs
+SET status = 'available', locked_until = NULL, attempts = attempts - 1
+WHERE job_id = $1
+`
+
+// Returns a job interrupted by a shutdown without counting the attempt.
+func (q *Queries) ReleaseJob(ctx context.Context, jobID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, releaseJob, jobID)
+	return err
+}
+
+const retryJob = `-- name: RetryJob :exec
+UPDATE jobs
+SET status = 'available', locked_until = NULL, last_error = $1, run_at = $2
+WHERE job_id = $3
+`
+
+type RetryJobParams struct {
+	LastError pgtype.Text `json:"last_error"`
+	RunAt     time.Time   `json:"run_at"`
+	JobID     uuid.UUID   `json:"job_id"`
+}
+
+func (q *Queries) RetryJob(ctx context.Context, arg RetryJobParams) error {
+	_, err := q.db.Exec(ctx, retryJob, arg.LastError, arg.RunAt, arg.JobID)
+	return err
+}
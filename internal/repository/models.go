@@ -11,10 +11,148 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type User struct {
-	UserID    uuid.UUID   `json:"user_id"`
-	Email     pgtype.Text `json:"email"`
-	FirstName pgtype.Text `json:"first_name"`
-	LastName  pgtype.Text `json:"last_name"`
+type ApiKey struct {
+	KeyID      uuid.UUID          `json:"key_id"`
+	Name       string             `json:"name"`
+	Prefix     string             `json:"prefix"`
+	KeyHash    string             `json:"key_hash"`
+	Owner      string             `json:"owner"`
+	Scopes     []string           `json:"scopes"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type AuditEvent struct {
+	EventID      uuid.UUID `json:"event_id"`
+	Actor        string    `json:"actor"`
+	ActorType    string    `json:"actor_type"`
+	Action       string    `json:"action"`
+	ResourceType string    `json:"resource_type"`
+	ResourceID   string    `json:"resource_id"`
+	Before       []byte    `json:"before"`
+	After        []byte    `json:"after"`
+	RequestID    string    `json:"request_id"`
+	Ip           string    `json:"ip"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type IdempotencyKey struct {
+	Owner           string      `json:"owner"`
+	Key             string      `json:"key"`
+	Fingerprint     string      `json:"fingerprint"`
+	StatusCode      pgtype.Int4 `json:"status_code"`
+	ResponseHeaders []byte      `json:"response_headers"`
+	ResponseBody    []byte      `json:"response_body"`
+	ExpiresAt       time.Time   `json:"expires_at"`
+	CreatedAt       time.Time   `json:"created_at"`
+}
+
+type Job struct {
+	JobID       uuid.UUID          `json:"job_id"`
+	Kind        string             `json:"kind"`
+	Args        []byte             `json:"args"`
+	Status      string             `json:"status"`
+	UniqueKey   pgtype.Text        `json:"unique_key"`
+	Attempts    int32              `json:"attempts"`
+	MaxAttempts int32              `json:"max_attempts"`
+	RunAt       time.Time          `json:"run_at"`
+	LockedUntil pgtype.Timestamptz `json:"locked_until"`
+	LastError   pgtype.Text        `json:"last_error"`
+	FinishedAt  pgtype.Timestamptz `json:"finished_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+type OutboxEvent struct {
+	EventID       uuid.UUID          `json:"event_id"`
+	AggregateType string             `json:"aggregate_type"`
+	AggregateID   string             `json:"aggregate_id"`
+	EventType     string             `json:"event_type"`
+	Payload       []byte             `json:"payload"`
+	Attempts      int32              `json:"attempts"`
+	NextAttemptAt time.Time          `json:"next_attempt_at"`
+	LastError     pgtype.Text        `json:"last_error"`
+	PublishedAt   pgtype.Timestamptz `json:"published_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+	XactID        int64              `json:"xact_id"`
+}
+
+type RateLimit struct {
+	Key         string             `json:"key"`
+	Tokens      float64            `json:"tokens"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	WindowStart pgtype.Timestamptz `json:"window_start"`
+	Count       int32              `json:"count"`
+	PrevCount   int32              `json:"prev_count"`
+	ExpiresAt   time.Time          `json:"expires_at"`
+}
+
+type RevokedSubject struct {
+	Issuer        string      `json:"issuer"`
+	Subject       string      `json:"subject"`
+	RevokedBefore time.Time   `json:"revoked_before"`
+	Reason        pgtype.Text `json:"reason"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+type RevokedToken struct {
+	Jti       string      `json:"jti"`
+	ExpiresAt time.Time   `json:"expires_at"`
+	Reason    pgtype.Text `json:"reason"`
 	CreatedAt time.Time   `json:"created_at"`
 }
+
+type ScheduledTaskRun struct {
+	RunID      uuid.UUID          `json:"run_id"`
+	Task       string             `json:"task"`
+	Replica    string             `json:"replica"`
+	StartedAt  time.Time          `json:"started_at"`
+	FinishedAt pgtype.Timestamptz `json:"finished_at"`
+	Error      pgtype.Text        `json:"error"`
+}
+
+type User struct {
+	UserID          uuid.UUID          `json:"user_id"`
+	Email           pgtype.Text        `json:"email"`
+	FirstName       pgtype.Text        `json:"first_name"`
+	LastName        pgtype.Text        `json:"last_name"`
+	Issuer          pgtype.Text        `json:"issuer"`
+	ExternalSubject pgtype.Text        `json:"external_subject"`
+	Status          string             `json:"status"`
+	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
+	Version         int64              `json:"version"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	DeliveryID     uuid.UUID          `json:"delivery_id"`
+	SubscriptionID uuid.UUID          `json:"subscription_id"`
+	EventID        uuid.UUID          `json:"event_id"`
+	EventType      string             `json:"event_type"`
+	Payload        []byte             `json:"payload"`
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	NextAttemptAt  time.Time          `json:"next_attempt_at"`
+	LockedUntil    pgtype.Timestamptz `json:"locked_until"`
+	LastError      pgtype.Text        `json:"last_error"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+type WebhookDeliveryAttempt struct {
+	AttemptID   uuid.UUID   `json:"attempt_id"`
+	DeliveryID  uuid.UUID   `json:"delivery_id"`
+	StatusCode  pgtype.Int4 `json:"status_code"`
+	Error       pgtype.Text `json:"error"`
+	DurationMs  int32       `json:"duration_ms"`
+	AttemptedAt time.Time   `json:"attempted_at"`
+}
+
+type WebhookSubscription struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	Url            string    `json:"url"`
+	EventTypes     []string  `json:"event_types"`
+	Secret         string    `json:"secret"`
+	MaxConcurrency int32     `json:"max_concurrency"`
+	CreatedAt      time.Time `json:"created_at"`
+}
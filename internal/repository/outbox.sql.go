@@ -0,0 +1,232 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: outbox.sql
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimOutboxEvents = `-- name: ClaimOutboxEvents :many
+SELECT event_id, aggregate_type, aggregate_id, event_type, payload, attempts, next_attempt_at, last_error, published_at, created_at, xact_id FROM outbox_events o
+WHERE o.published_at IS NULL
+  AND o.next_attempt_at <= now()
+  AND NOT EXISTS (
+    SELECT 1 FROM outbox_events earlier
+    WHERE earlier.aggregate_type = o.aggregate_type
+      AND earlier.aggregate_id = o.aggregate_id
+      AND earlier.published_at IS NULL
+      AND earlier.event_id < o.event_id
+  )
+ORDER BY o.event_id
+LIMIT $1
+FOR UPDATE SKIP LOCKED
+`
+
+// Locks the oldest pending event of each aggregate until the end of the transaction. Later events
+// of an aggregate wait until the earlier ones are published, so every aggregate is published in order.
+func (q *Queries) ClaimOutboxEvents(ctx context.Context, batchSize int32) ([]OutboxEvent, error) {
+	rows, err := q.db.Query(ctx, claimOutboxEvents, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OutboxEvent
+	for rows.Next() {
+		var i OutboxEvent
+		if err := rows.Scan(
+			&i.EventID,
+			&i.AggregateType,
+			&i.AggregateID,
+			&i.EventType,
+			&i.Payload,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.XactID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deletePublishedOutboxEvents = `-- name: DeletePublishedOutboxEvents :execrows
+DELETE FROM outbox_events WHERE published_at < $1
+`
+
+func (q *Queries) DeletePublishedOutboxEvents(ctx context.Context, publishedBefore pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePublishedOutboxEvents, publishedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const enqueueUserEvents = `-- name: EnqueueUserEvents :exec
+INSERT INTO outbox_events (aggregate_type, aggregate_id, event_type, payload)
+SELECT 'user', user_id::text, $1, jsonb_build_object(
+    'user_id', user_id,
+    'email', email,
+    'first_name', first_name,
+    'last_name', last_name,
+    'status', status,
+    'deleted_at', deleted_at,
+    'version', version
+)
+FROM users
+WHERE user_id = ANY($2::uuid[])
+ORDER BY user_id
+`
+
+type EnqueueUserEventsParams struct {
+	EventType string      `json:"event_type"`
+	UserIds   []uuid.UUID `json:"user_ids"`
+}
+
+// Adds an event with the current state of each user. Must run in the transaction of the mutation.
+func (q *Queries) EnqueueUserEvents(ctx context.Context, arg EnqueueUserEventsParams) error {
+	_, err := q.db.Exec(ctx, enqueueUserEvents, arg.EventType, arg.UserIds)
+	return err
+}
+
+const failOutboxEvent = `-- name: FailOutboxEvent :exec
+UPDATE outbox_events SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2
+WHERE event_id = $3
+`
+
+type FailOutboxEventParams struct {
+	LastError     pgtype.Text `json:"last_error"`
+	NextAttemptAt time.Time   `json:"next_attempt_at"`
+	EventID       uuid.UUID   `json:"event_id"`
+}
+
+func (q *Queries) FailOutboxEvent(ctx context.Context, arg FailOutboxEventParams) error {
+	_, err := q.db.Exec(ctx, failOutboxEvent, arg.LastError, arg.NextAttemptAt, arg.EventID)
+	return err
+}
+
+const getCommitHorizon = `-- name: GetCommitHorizon :one
+SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint AS horizon
+`
+
+// Transactions with an ID below the horizon have ended, their events are all visible.
+func (q *Queries) GetCommitHorizon(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, getCommitHorizon)
+	var horizon int64
+	err := row.Scan(&horizon)
+	return horizon, err
+}
+
+const listUserEventsAfter = `-- name: ListUserEventsAfter :many
+SELECT event_id, aggregate_type, aggregate_id, event_type, payload, attempts, next_attempt_at, last_error, published_at, created_at, xact_id FROM outbox_events
+WHERE aggregate_type = 'user' AND event_id > $1
+ORDER BY event_id
+LIMIT $2
+`
+
+type ListUserEventsAfterParams struct {
+	After      uuid.UUID `json:"after"`
+	MaxResults int32     `json:"max_results"`
+}
+
+// Event IDs are UUIDv7, so the events after an ID are the ones created after it.
+func (q *Queries) ListUserEventsAfter(ctx context.Context, arg ListUserEventsAfterParams) ([]OutboxEvent, error) {
+	rows, err := q.db.Query(ctx, listUserEventsAfter, arg.After, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OutboxEvent
+	for rows.Next() {
+		var i OutboxEvent
+		if err := rows.Scan(
+			&i.EventID,
+			&i.AggregateType,
+			&i.AggregateID,
+			&i.EventType,
+			&i.Payload,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.XactID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserEventsSince = `-- name: ListUserEventsSince :many
+SELECT event_id, aggregate_type, aggregate_id, event_type, payload, attempts, next_attempt_at, last_error, published_at, created_at, xact_id FROM outbox_events
+WHERE aggregate_type = 'user' AND xact_id >= $1 AND event_id > $2
+ORDER BY event_id
+LIMIT $3
+`
+
+type ListUserEventsSinceParams struct {
+	Horizon    int64     `json:"horizon"`
+	After      uuid.UUID `json:"after"`
+	MaxResults int32     `json:"max_results"`
+}
+
+// Lists the visible events of the transactions from the horizon on, a page at a time ordered by event ID.
+func (q *Queries) ListUserEventsSince(ctx context.Context, arg ListUserEventsSinceParams) ([]OutboxEvent, error) {
+	rows, err := q.db.Query(ctx, listUserEventsSince, arg.Horizon, arg.After, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OutboxEvent
+	for rows.Next() {
+		var i OutboxEvent
+		if err := rows.Scan(
+			&i.EventID,
+			&i.AggregateType,
+			&i.AggregateID,
+			&i.EventType,
+			&i.Payload,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.XactID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markOutboxEventPublished = `-- name: MarkOutboxEventPublished :exec
+UPDATE outbox_events SET published_at = now(), attempts = attempts + 1, last_error = NULL
+WHERE event_id = $1
+`
+
+func (q *Queries) MarkOutboxEventPublished(ctx context.Context, eventID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, markOutboxEventPublished, eventID)
+	return err
+}
@@ -12,7 +12,7 @@ import (
 )
 
 const findByID = `-- name: FindByID :one
-SELECT user_id, email, first_name, last_name, created_at FROM users WHERE user_id = $1
+SELECT user_id, email, first_name, last_name, issuer, external_subject, status, deleted_at, version, created_at FROM users WHERE user_id = $1
 `
 
 func (q *Queries) FindByID(ctx context.Context, userID uuid.UUID) (User, error) {
@@ -23,6 +23,11 @@ func (q *Queries) FindByID(ctx context.Context, userID uuid.UUID) (User, error)
 		&i.Email,
 		&i.FirstName,
 		&i.LastName,
+		&i.Issuer,
+		&i.ExternalSubject,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
 		&i.CreatedAt,
 	)
 	return i, err
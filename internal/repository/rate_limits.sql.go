@@ -0,0 +1,112 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: rate_limits.sql
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const deleteExpiredRateLimits = `-- name: DeleteExpiredRateLimits :execrows
+DELETE FROM rate_limits WHERE expires_at < now()
+`
+
+func (q *Queries) DeleteExpiredRateLimits(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredRateLimits)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getRateLimit = `-- name: GetRateLimit :one
+SELECT key, tokens, updated_at, window_start, count, prev_count, expires_at FROM rate_limits WHERE key = $1 AND expires_at >= now()
+`
+
+// Returns the state of the key without locking it, no row if it has none or it expired.
+func (q *Queries) GetRateLimit(ctx context.Context, key string) (RateLimit, error) {
+	row := q.db.QueryRow(ctx, getRateLimit, key)
+	var i RateLimit
+	err := row.Scan(
+		&i.Key,
+		&i.Tokens,
+		&i.UpdatedAt,
+		&i.WindowStart,
+		&i.Count,
+		&i.PrevCount,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const lockRateLimit = `-- name: LockRateLimit :one
+INSERT INTO rate_limits (key, expires_at)
+VALUES ($1, $2)
+ON CONFLICT (key) DO UPDATE
+SET tokens       = CASE WHEN rate_limits.expires_at < now() THEN 0 ELSE rate_limits.tokens END,
+    updated_at   = CASE WHEN rate_limits.expires_at < now() THEN NULL ELSE rate_limits.updated_at END,
+    window_start = CASE WHEN rate_limits.expires_at < now() THEN NULL ELSE rate_limits.window_start END,
+    count        = CASE WHEN rate_limits.expires_at < now() THEN 0 ELSE rate_limits.count END,
+    prev_count   = CASE WHEN rate_limits.expires_at < now() THEN 0 ELSE rate_limits.prev_count END
+RETURNING key, tokens, updated_at, window_start, count, prev_count, expires_at
+`
+
+type LockRateLimitParams struct {
+	Key       string    `json:"key"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Returns the state of the key, a new one if it has none or it expired, locked until the end of the transaction.
+func (q *Queries) LockRateLimit(ctx context.Context, arg LockRateLimitParams) (RateLimit, error) {
+	row := q.db.QueryRow(ctx, lockRateLimit, arg.Key, arg.ExpiresAt)
+	var i RateLimit
+	err := row.Scan(
+		&i.Key,
+		&i.Tokens,
+		&i.UpdatedAt,
+		&i.WindowStart,
+		&i.Count,
+		&i.PrevCount,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const updateRateLimit = `-- name: UpdateRateLimit :exec
+UPDATE rate_limits
+SET tokens       = $1,
+    updated_at   = $2,
+    window_start = $3,
+    count        = $4,
+    prev_count   = $5,
+    expires_at   = $6
+WHERE key = $7
+`
+
+type UpdateRateLimitParams struct {
+	Tokens      float64            `json:"tokens"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	WindowStart pgtype.Timestamptz `json:"window_start"`
+	Count       int32              `json:"count"`
+	PrevCount   int32              `json:"prev_count"`
+	ExpiresAt   time.Time          `json:"expires_at"`
+	Key         string             `json:"key"`
+}
+
+func (q *Queries) UpdateRateLimit(ctx context.Context, arg UpdateRateLimitParams) error {
+	_, err := q.db.Exec(ctx, updateRateLimit,
+		arg.Tokens,
+		arg.UpdatedAt,
+		arg.WindowStart,
+		arg.Count,
+		arg.PrevCount,
+		arg.ExpiresAt,
+		arg.Key,
+	)
+	return err
+}
@@ -0,0 +1,206 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: revocations.sql
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const deleteExpiredRevokedTokens = `-- name: DeleteExpiredRevokedTokens :exec
+DELETE FROM revoked_tokens WHERE expires_at <= now()
+`
+
+func (q *Queries) DeleteExpiredRevokedTokens(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteExpiredRevokedTokens)
+	return err
+}
+
+const deleteRevokedSubject = `-- name: DeleteRevokedSubject :one
+DELETE FROM revoked_subjects WHERE issuer = $1 AND subject = $2
+RETURNING issuer, subject, revoked_before, reason, created_at
+`
+
+type DeleteRevokedSubjectParams struct {
+	Issuer  string `json:"issuer"`
+	Subject string `json:"subject"`
+}
+
+func (q *Queries) DeleteRevokedSubject(ctx context.Context, arg DeleteRevokedSubjectParams) (RevokedSubject, error) {
+	row := q.db.QueryRow(ctx, deleteRevokedSubject, arg.Issuer, arg.Subject)
+	var i RevokedSubject
+	err := row.Scan(
+		&i.Issuer,
+		&i.Subject,
+		&i.RevokedBefore,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRevokedSubject = `-- name: GetRevokedSubject :one
+SELECT issuer, subject, revoked_before, reason, created_at FROM revoked_subjects WHERE issuer = $1 AND subject = $2
+`
+
+type GetRevokedSubjectParams struct {
+	Issuer  string `json:"issuer"`
+	Subject string `json:"subject"`
+}
+
+func (q *Queries) GetRevokedSubject(ctx context.Context, arg GetRevokedSubjectParams) (RevokedSubject, error) {
+	row := q.db.QueryRow(ctx, getRevokedSubject, arg.Issuer, arg.Subject)
+	var i RevokedSubject
+	err := row.Scan(
+		&i.Issuer,
+		&i.Subject,
+		&i.RevokedBefore,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRevokedToken = `-- name: GetRevokedToken :one
+SELECT jti, expires_at, reason, created_at FROM revoked_tokens WHERE jti = $1 AND expires_at > now()
+`
+
+func (q *Queries) GetRevokedToken(ctx context.Context, jti string) (RevokedToken, error) {
+	row := q.db.QueryRow(ctx, getRevokedToken, jti)
+	var i RevokedToken
+	err := row.Scan(
+		&i.Jti,
+		&i.ExpiresAt,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listRevokedSubjects = `-- name: ListRevokedSubjects :many
+SELECT issuer, subject, revoked_before, reason, created_at FROM revoked_subjects
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListRevokedSubjects(ctx context.Context) ([]RevokedSubject, error) {
+	rows, err := q.db.Query(ctx, listRevokedSubjects)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RevokedSubject
+	for rows.Next() {
+		var i RevokedSubject
+		if err := rows.Scan(
+			&i.Issuer,
+			&i.Subject,
+			&i.RevokedBefore,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRevokedTokens = `-- name: ListRevokedTokens :many
+SELECT jti, expires_at, reason, created_at FROM revoked_tokens
+WHERE expires_at > now()
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListRevokedTokens(ctx context.Context) ([]RevokedToken, error) {
+	rows, err := q.db.Query(ctx, listRevokedTokens)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RevokedToken
+	for rows.Next() {
+		var i RevokedToken
+		if err := rows.Scan(
+			&i.Jti,
+			&i.ExpiresAt,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeSubject = `-- name: RevokeSubject :one
+INSERT INTO revoked_subjects (issuer, subject, revoked_before, reason)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (issuer, subject) DO UPDATE
+SET revoked_before = GREATEST(revoked_subjects.revoked_before, EXCLUDED.revoked_before),
+    reason = EXCLUDED.reason
+RETURNING issuer, subject, revoked_before, reason, created_at
+`
+
+type RevokeSubjectParams struct {
+	Issuer        string      `json:"issuer"`
+	Subject       string      `json:"subject"`
+	RevokedBefore time.Time   `json:"revoked_before"`
+	Reason        pgtype.Text `json:"reason"`
+}
+
+func (q *Queries) RevokeSubject(ctx context.Context, arg RevokeSubjectParams) (RevokedSubject, error) {
+	row := q.db.QueryRow(ctx, revokeSubject,
+		arg.Issuer,
+		arg.Subject,
+		arg.RevokedBefore,
+		arg.Reason,
+	)
+	var i RevokedSubject
+	err := row.Scan(
+		&i.Issuer,
+		&i.Subject,
+		&i.RevokedBefore,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const revokeToken = `-- name: RevokeToken :one
+INSERT INTO revoked_tokens (jti, expires_at, reason)
+VALUES ($1, $2, $3)
+ON CONFLICT (jti) DO UPDATE
+SET expires_at = GREATEST(revoked_tokens.expires_at, EXCLUDED.expires_at),
+    reason = EXCLUDED.reason
+RETURNING jti, expires_at, reason, created_at
+`
+
+type RevokeTokenParams struct {
+	Jti       string      `json:"jti"`
+	ExpiresAt time.Time   `json:"expires_at"`
+	Reason    pgtype.Text `json:"reason"`
+}
+
+func (q *Queries) RevokeToken(ctx context.Context, arg RevokeTokenParams) (RevokedToken, error) {
+	row := q.db.QueryRow(ctx, revokeToken, arg.Jti, arg.ExpiresAt, arg.Reason)
+	var i RevokedToken
+	err := row.Scan(
+		&i.Jti,
+		&i.ExpiresAt,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,161 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: scheduler.sql
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const advisoryUnlock = `-- name: AdvisoryUnlock :exec
+SELECT pg_advisory_unlock($1::bigint)
+`
+
+func (q *Queries) AdvisoryUnlock(ctx context.Context, lockKey int64) error {
+	_, err := q.db.Exec(ctx, advisoryUnlock, lockKey)
+	return err
+}
+
+const deleteScheduledTaskRuns = `-- name: DeleteScheduledTaskRuns :execrows
+DELETE FROM scheduled_task_runs WHERE started_at < $1
+`
+
+func (q *Queries) DeleteScheduledTaskRuns(ctx context.Context, startedBefore time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteScheduledTaskRuns, startedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const finishScheduledTaskRun = `-- name: FinishScheduledTaskRun :exec
+UPDATE scheduled_task_runs SET finished_at = now(), error = $1
+WHERE run_id = $2
+`
+
+type FinishScheduledTaskRunParams struct {
+	Error pgtype.Text `json:"error"`
+	RunID uuid.UUID   `json:"run_id"`
+}
+
+func (q *Queries) FinishScheduledTaskRun(ctx context.Context, arg FinishScheduledTaskRunParams) error {
+	_, err := q.db.Exec(ctx, finishScheduledTaskRun, arg.Error, arg.RunID)
+	return err
+}
+
+const getLatestScheduledTaskRuns = `-- name: GetLatestScheduledTaskRuns :many
+SELECT DISTINCT ON (task) run_id, task, replica, started_at, finished_at, error FROM scheduled_task_runs
+ORDER BY task, run_id DESC
+`
+
+func (q *Queries) GetLatestScheduledTaskRuns(ctx context.Context) ([]ScheduledTaskRun, error) {
+	rows, err := q.db.Query(ctx, getLatestScheduledTaskRuns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ScheduledTaskRun
+	for rows.Next() {
+		var i ScheduledTaskRun
+		if err := rows.Scan(
+			&i.RunID,
+			&i.Task,
+			&i.Replica,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScheduledTaskRuns = `-- name: ListScheduledTaskRuns :many
+SELECT run_id, task, replica, started_at, finished_at, error FROM scheduled_task_runs
+WHERE ($1::text IS NULL OR task = $1)
+  AND ($2::uuid IS NULL OR run_id < $2)
+ORDER BY run_id DESC
+LIMIT $3
+`
+
+type ListScheduledTaskRunsParams struct {
+	Task       pgtype.Text `json:"task"`
+	Cursor     pgtype.UUID `json:"cursor"`
+	MaxResults int32       `json:"max_results"`
+}
+
+// Newest first. The cursor is the run_id of the last run of the previous page.
+func (q *Queries) ListScheduledTaskRuns(ctx context.Context, arg ListScheduledTaskRunsParams) ([]ScheduledTaskRun, error) {
+	rows, err := q.db.Query(ctx, listScheduledTaskRuns, arg.Task, arg.Cursor, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ScheduledTaskRun
+	for rows.Next() {
+		var i ScheduledTaskRun
+		if err := rows.Scan(
+			&i.RunID,
+			&i.Task,
+			&i.Replica,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const startScheduledTaskRun = `-- name: StartScheduledTaskRun :one
+INSERT INTO scheduled_task_runs (task, replica)
+VALUES ($1, $2)
+RETURNING run_id, task, replica, started_at, finished_at, error
+`
+
+type StartScheduledTaskRunParams struct {
+	Task    string `json:"task"`
+	Replica string `json:"replica"`
+}
+
+func (q *Queries) StartScheduledTaskRun(ctx context.Context, arg StartScheduledTaskRunParams) (ScheduledTaskRun, error) {
+	row := q.db.QueryRow(ctx, startScheduledTaskRun, arg.Task, arg.Replica)
+	var i ScheduledTaskRun
+	err := row.Scan(
+		&i.RunID,
+		&i.Task,
+		&i.Replica,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Error,
+	)
+	return i, err
+}
+
+const tryAdvisoryLock = `-- name: TryAdvisoryLock :one
+SELECT pg_try_advisory_lock($1::bigint)::boolean AS locked
+`
+
+// Session level lock, held until it is unlocked or the connection closes.
+func (q *Queries) TryAdvisoryLock(ctx context.Context, lockKey int64) (bool, error) {
+	row := q.db.QueryRow(ctx, tryAdvisoryLock, lockKey)
+	var locked bool
+	err := row.Scan(&locked)
+	return locked, err
+}
@@ -7,26 +7,104 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CopyUsersParams struct {
+	UserID    uuid.UUID   `json:"user_id"`
+	Email     pgtype.Text `json:"email"`
+	FirstName pgtype.Text `json:"first_name"`
+	LastName  pgtype.Text `json:"last_name"`
+}
+
 const countUsers = `-- name: CountUsers :one
 SELECT COUNT(*) FROM users
+WHERE deleted_at IS NULL OR $1::boolean
 `
 
-func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
-	row := q.db.QueryRow(ctx, countUsers)
+func (q *Queries) CountUsers(ctx context.Context, includeDeleted bool) (int64, error) {
+	row := q.db.QueryRow(ctx, countUsers, includeDeleted)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
+const countUsersByFilter = `-- name: CountUsersByFilter :one
+SELECT COUNT(*) FROM users
+WHERE deleted_at IS NULL
+  AND ($1::uuid IS NULL OR user_id = $1)
+  AND ($2::text IS NULL OR email ILIKE $2)
+  AND ($3::text IS NULL OR external_subject LIKE $3)
+  AND ($4::text IS NULL OR first_name ILIKE $4)
+  AND ($5::text IS NULL OR last_name ILIKE $5)
+`
+
+type CountUsersByFilterParams struct {
+	UserID          pgtype.UUID `json:"user_id"`
+	Email           pgtype.Text `json:"email"`
+	ExternalSubject pgtype.Text `json:"external_subject"`
+	FirstName       pgtype.Text `json:"first_name"`
+	LastName        pgtype.Text `json:"last_name"`
+}
+
+func (q *Queries) CountUsersByFilter(ctx context.Context, arg CountUsersByFilterParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countUsersByFilter,
+		arg.UserID,
+		arg.Email,
+		arg.ExternalSubject,
+		arg.FirstName,
+		arg.LastName,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createExternalUser = `-- name: CreateExternalUser :one
+INSERT INTO users (email, first_name, last_name, issuer, external_subject)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING user_id, email, first_name, last_name, issuer, external_subject, status, deleted_at, version, created_at
+`
+
+type CreateExternalUserParams struct {
+	Email           pgtype.Text `json:"email"`
+	FirstName       pgtype.Text `json:"first_name"`
+	LastName        pgtype.Text `json:"last_name"`
+	Issuer          pgtype.Text `json:"issuer"`
+	ExternalSubject pgtype.Text `json:"external_subject"`
+}
+
+func (q *Queries) CreateExternalUser(ctx context.Context, arg CreateExternalUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, createExternalUser,
+		arg.Email,
+		arg.FirstName,
+		arg.LastName,
+		arg.Issuer,
+		arg.ExternalSubject,
+	)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.FirstName,
+		&i.LastName,
+		&i.Issuer,
+		&i.ExternalSubject,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (email, first_name, last_name) 
 VALUES ($1, $2, $3)
-RETURNING user_id, email, first_name, last_name, created_at
+RETURNING user_id, email, first_name, last_name, issuer, external_subject, status, deleted_at, version, created_at
 `
 
 type CreateUserParams struct {
@@ -43,23 +121,80 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.FirstName,
 		&i.LastName,
+		&i.Issuer,
+		&i.ExternalSubject,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
 		&i.CreatedAt,
 	)
 	return i, err
 }
 
+const declareUserExportCursor = `-- name: DeclareUserExportCursor :exec
+DECLARE user_export NO SCROLL CURSOR FOR
+SELECT user_id, email, first_name, last_name, issuer, external_subject, status, deleted_at, version, created_at FROM users
+WHERE deleted_at IS NULL OR $1::boolean
+ORDER BY created_at, user_id
+`
+
+// Must run in a transaction, rows are read with FETCH FORWARD n FROM user_export.
+func (q *Queries) DeclareUserExportCursor(ctx context.Context, includeDeleted bool) error {
+	_, err := q.db.Exec(ctx, declareUserExportCursor, includeDeleted)
+	return err
+}
+
 const getUser = `-- name: GetUser :one
-SELECT user_id, email, first_name, last_name, created_at FROM users WHERE user_id = $1
+SELECT user_id, email, first_name, last_name, issuer, external_subject, status, deleted_at, version, created_at FROM users
+WHERE user_id = $1
+  AND (deleted_at IS NULL OR $2::boolean)
+`
+
+type GetUserParams struct {
+	UserID         uuid.UUID `json:"user_id"`
+	IncludeDeleted bool      `json:"include_deleted"`
+}
+
+func (q *Queries) GetUser(ctx context.Context, arg GetUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, getUser, arg.UserID, arg.IncludeDeleted)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.FirstName,
+		&i.LastName,
+		&i.Issuer,
+		&i.ExternalSubject,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserBySubject = `-- name: GetUserBySubject :one
+SELECT user_id, email, first_name, last_name, issuer, external_subject, status, deleted_at, version, created_at FROM users WHERE issuer = $1 AND external_subject = $2
 `
 
-func (q *Queries) GetUser(ctx context.Context, userID uuid.UUID) (User, error) {
-	row := q.db.QueryRow(ctx, getUser, userID)
+type GetUserBySubjectParams struct {
+	Issuer          pgtype.Text `json:"issuer"`
+	ExternalSubject pgtype.Text `json:"external_subject"`
+}
+
+func (q *Queries) GetUserBySubject(ctx context.Context, arg GetUserBySubjectParams) (User, error) {
+	row := q.db.QueryRow(ctx, getUserBySubject, arg.Issuer, arg.ExternalSubject)
 	var i User
 	err := row.Scan(
 		&i.UserID,
 		&i.Email,
 		&i.FirstName,
 		&i.LastName,
+		&i.Issuer,
+		&i.ExternalSubject,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
 		&i.CreatedAt,
 	)
 	return i, err
@@ -71,20 +206,94 @@ SELECT
     email, 
     first_name, 
     last_name, 
+    status,
+    deleted_at,
     created_at 
 FROM users 
+WHERE deleted_at IS NULL OR $1::boolean
 ORDER BY created_at DESC 
-LIMIT $1 
+LIMIT $3 
 OFFSET $2
 `
 
 type GetUsersParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	IncludeDeleted bool  `json:"include_deleted"`
+	Skip           int32 `json:"skip"`
+	MaxResults     int32 `json:"max_results"`
 }
 
-func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]User, error) {
-	rows, err := q.db.Query(ctx, getUsers, arg.Limit, arg.Offset)
+type GetUsersRow struct {
+	UserID    uuid.UUID          `json:"user_id"`
+	Email     pgtype.Text        `json:"email"`
+	FirstName pgtype.Text        `json:"first_name"`
+	LastName  pgtype.Text        `json:"last_name"`
+	Status    string             `json:"status"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error) {
+	rows, err := q.db.Query(ctx, getUsers, arg.IncludeDeleted, arg.Skip, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUsersRow
+	for rows.Next() {
+		var i GetUsersRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Email,
+			&i.FirstName,
+			&i.LastName,
+			&i.Status,
+			&i.DeletedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByFilter = `-- name: ListUsersByFilter :many
+SELECT user_id, email, first_name, last_name, issuer, external_subject, status, deleted_at, version, created_at FROM users
+WHERE deleted_at IS NULL
+  AND ($1::uuid IS NULL OR user_id = $1)
+  AND ($2::text IS NULL OR email ILIKE $2)
+  AND ($3::text IS NULL OR external_subject LIKE $3)
+  AND ($4::text IS NULL OR first_name ILIKE $4)
+  AND ($5::text IS NULL OR last_name ILIKE $5)
+ORDER BY created_at, user_id
+LIMIT $7
+OFFSET $6
+`
+
+type ListUsersByFilterParams struct {
+	UserID          pgtype.UUID `json:"user_id"`
+	Email           pgtype.Text `json:"email"`
+	ExternalSubject pgtype.Text `json:"external_subject"`
+	FirstName       pgtype.Text `json:"first_name"`
+	LastName        pgtype.Text `json:"last_name"`
+	Skip            int32       `json:"skip"`
+	MaxResults      int32       `json:"max_results"`
+}
+
+// Patterns are LIKE patterns, NULL skips the condition. email and names are matched case-insensitively.
+func (q *Queries) ListUsersByFilter(ctx context.Context, arg ListUsersByFilterParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersByFilter,
+		arg.UserID,
+		arg.Email,
+		arg.ExternalSubject,
+		arg.FirstName,
+		arg.LastName,
+		arg.Skip,
+		arg.MaxResults,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -97,6 +306,11 @@ func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]User, err
 			&i.Email,
 			&i.FirstName,
 			&i.LastName,
+			&i.Issuer,
+			&i.ExternalSubject,
+			&i.Status,
+			&i.DeletedAt,
+			&i.Version,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -108,3 +322,278 @@ func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]User, err
 	}
 	return items, nil
 }
+
+const lockUser = `-- name: LockUser :one
+SELECT user_id, email, first_name, last_name, issuer, external_subject, status, deleted_at, version, created_at FROM users
+WHERE user_id = $1 AND deleted_at IS NULL
+FOR UPDATE
+`
+
+// Locks the user until the end of the transaction, e.g. to record its state before an update.
+func (q *Queries) LockUser(ctx context.Context, userID uuid.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, lockUser, userID)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.FirstName,
+		&i.LastName,
+		&i.Issuer,
+		&i.ExternalSubject,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const purgeDeletedUsers = `-- name: PurgeDeletedUsers :execrows
+DELETE FROM users WHERE deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedUsers, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const replaceUser = `-- name: ReplaceUser :one
+UPDATE users
+SET email            = $1,
+    first_name       = $2,
+    last_name        = $3,
+    issuer           = $4,
+    external_subject = $5,
+    status           = COALESCE($6, status),
+    version          = version + 1
+WHERE user_id = $7 AND deleted_at IS NULL
+RETURNING user_id, email, first_name, last_name, issuer, external_subject, status, deleted_at, version, created_at
+`
+
+type ReplaceUserParams struct {
+	Email           pgtype.Text `json:"email"`
+	FirstName       pgtype.Text `json:"first_name"`
+	LastName        pgtype.Text `json:"last_name"`
+	Issuer          pgtype.Text `json:"issuer"`
+	ExternalSubject pgtype.Text `json:"external_subject"`
+	Status          pgtype.Text `json:"status"`
+	UserID          uuid.UUID   `json:"user_id"`
+}
+
+func (q *Queries) ReplaceUser(ctx context.Context, arg ReplaceUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, replaceUser,
+		arg.Email,
+		arg.FirstName,
+		arg.LastName,
+		arg.Issuer,
+		arg.ExternalSubject,
+		arg.Status,
+		arg.UserID,
+	)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.FirstName,
+		&i.LastName,
+		&i.Issuer,
+		&i.ExternalSubject,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users SET deleted_at = NULL, version = version + 1
+WHERE user_id = $1 AND deleted_at > $2 AND version = $3
+RETURNING user_id, email, first_name, last_name, issuer, external_subject, status, deleted_at, version, created_at
+`
+
+type RestoreUserParams struct {
+	UserID       uuid.UUID          `json:"user_id"`
+	DeletedAfter pgtype.Timestamptz `json:"deleted_after"`
+	Version      int64              `json:"version"`
+}
+
+// Only users deleted after deleted_after can be restored, older ones are about to be purged.
+func (q *Queries) RestoreUser(ctx context.Context, arg RestoreUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, restoreUser, arg.UserID, arg.DeletedAfter, arg.Version)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.FirstName,
+		&i.LastName,
+		&i.Issuer,
+		&i.ExternalSubject,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :one
+UPDATE users SET deleted_at = now(), version = version + 1
+WHERE user_id = $1
+  AND deleted_at IS NULL
+  AND ($2::bigint IS NULL OR version = $2)
+RETURNING user_id, email, first_name, last_name, issuer, external_subject, status, deleted_at, version, created_at
+`
+
+type SoftDeleteUserParams struct {
+	UserID  uuid.UUID   `json:"user_id"`
+	Version pgtype.Int8 `json:"version"`
+}
+
+// A NULL version deletes any version of the user.
+func (q *Queries) SoftDeleteUser(ctx context.Context, arg SoftDeleteUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, softDeleteUser, arg.UserID, arg.Version)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.FirstName,
+		&i.LastName,
+		&i.Issuer,
+		&i.ExternalSubject,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET first_name = COALESCE($1, first_name),
+    last_name  = COALESCE($2, last_name),
+    version    = version + 1
+WHERE user_id = $3
+  AND deleted_at IS NULL
+  AND status = 'active'
+RETURNING user_id, email, first_name, last_name, issuer, external_subject, status, deleted_at, version, created_at
+`
+
+type UpdateUserProfileParams struct {
+	FirstName pgtype.Text `json:"first_name"`
+	LastName  pgtype.Text `json:"last_name"`
+	UserID    uuid.UUID   `json:"user_id"`
+}
+
+// Returns no row if the user was deleted or is no longer active.
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserProfile, arg.FirstName, arg.LastName, arg.UserID)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.FirstName,
+		&i.LastName,
+		&i.Issuer,
+		&i.ExternalSubject,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateUserStatus = `-- name: UpdateUserStatus :one
+UPDATE users SET status = $1, version = version + 1
+WHERE user_id = $2
+  AND deleted_at IS NULL
+  AND status = ANY($3::text[])
+  AND version = $4
+RETURNING user_id, email, first_name, last_name, issuer, external_subject, status, deleted_at, version, created_at
+`
+
+type UpdateUserStatusParams struct {
+	Status       string    `json:"status"`
+	UserID       uuid.UUID `json:"user_id"`
+	FromStatuses []string  `json:"from_statuses"`
+	Version      int64     `json:"version"`
+}
+
+// Transitions are only applied from one of the allowed statuses, so concurrent transitions cannot skip the state machine.
+func (q *Queries) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserStatus,
+		arg.Status,
+		arg.UserID,
+		arg.FromStatuses,
+		arg.Version,
+	)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.FirstName,
+		&i.LastName,
+		&i.Issuer,
+		&i.ExternalSubject,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const upsertUserBySubject = `-- name: UpsertUserBySubject :one
+INSERT INTO users (issuer, external_subject, email, first_name, last_name)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (issuer, external_subject) DO UPDATE
+SET email      = COALESCE(EXCLUDED.email, users.email),
+    first_name = COALESCE(users.first_name, EXCLUDED.first_name),
+    last_name  = COALESCE(users.last_name, EXCLUDED.last_name),
+    version    = users.version + 1
+WHERE users.deleted_at IS NULL
+  AND (users.email IS DISTINCT FROM COALESCE(EXCLUDED.email, users.email)
+    OR (users.first_name IS NULL AND EXCLUDED.first_name IS NOT NULL)
+    OR (users.last_name IS NULL AND EXCLUDED.last_name IS NOT NULL))
+RETURNING user_id, email, first_name, last_name, issuer, external_subject, status, deleted_at, version, created_at
+`
+
+type UpsertUserBySubjectParams struct {
+	Issuer          pgtype.Text `json:"issuer"`
+	ExternalSubject pgtype.Text `json:"external_subject"`
+	Email           pgtype.Text `json:"email"`
+	FirstName       pgtype.Text `json:"first_name"`
+	LastName        pgtype.Text `json:"last_name"`
+}
+
+// Creates the user of a token subject or syncs the email from the token. Names are only filled in if
+// they are missing, so they can be changed with UpdateUserProfile. Returns no row if nothing changed
+// or the user was deleted.
+func (q *Queries) UpsertUserBySubject(ctx context.Context, arg UpsertUserBySubjectParams) (User, error) {
+	row := q.db.QueryRow(ctx, upsertUserBySubject,
+		arg.Issuer,
+		arg.ExternalSubject,
+		arg.Email,
+		arg.FirstName,
+		arg.LastName,
+	)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.FirstName,
+		&i.LastName,
+		&i.Issuer,
+		&i.ExternalSubject,
+		&i.Status,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,399 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: webhooks.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimWebhookDeliveries = `-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries
+SET status = 'in_flight', attempts = attempts + 1, locked_until = $1
+WHERE delivery_id IN (
+    SELECT delivery_id FROM webhook_deliveries d
+    WHERE d.subscription_id = $2
+      AND ((d.status = 'pending' AND d.next_attempt_at <= now())
+        OR (d.status = 'in_flight' AND d.locked_until < now()))
+    ORDER BY d.next_attempt_at, d.delivery_id
+    LIMIT COALESCE((
+        SELECT GREATEST(s.max_concurrency - count(l.delivery_id), 0)
+        FROM webhook_subscriptions s
+        LEFT JOIN webhook_deliveries l ON l.subscription_id = s.subscription_id
+            AND l.status = 'in_flight' AND l.locked_until >= now()
+        WHERE s.subscription_id = $2
+        GROUP BY s.max_concurrency
+    ), 0)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING delivery_id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, locked_until, last_error, created_at
+`
+
+type ClaimWebhookDeliveriesParams struct {
+	LockedUntil    pgtype.Timestamptz `json:"locked_until"`
+	SubscriptionID uuid.UUID          `json:"subscription_id"`
+}
+
+// Leases due deliveries of a subscription, including deliveries whose lease expired because their replica stopped.
+// Only as many as max_concurrency minus the unexpired leases of all replicas are leased, run it after
+// LockWebhookSubscription in the same transaction so concurrent claims see each other's leases.
+func (q *Queries) ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.Query(ctx, claimWebhookDeliveries, arg.LockedUntil, arg.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.DeliveryID,
+			&i.SubscriptionID,
+			&i.EventID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LockedUntil,
+			&i.LastError,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const completeWebhookDelivery = `-- name: CompleteWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status = 'succeeded', locked_until = NULL, last_error = NULL
+WHERE delivery_id = $1
+`
+
+func (q *Queries) CompleteWebhookDelivery(ctx context.Context, deliveryID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, completeWebhookDelivery, deliveryID)
+	return err
+}
+
+const createWebhookSubscription = `-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (url, event_types, secret, max_concurrency)
+VALUES ($1, $2, $3, $4)
+RETURNING subscription_id, url, event_types, secret, max_concurrency, created_at
+`
+
+type CreateWebhookSubscriptionParams struct {
+	Url            string   `json:"url"`
+	EventTypes     []string `json:"event_types"`
+	Secret         string   `json:"secret"`
+	MaxConcurrency int32    `json:"max_concurrency"`
+}
+
+func (q *Queries) CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error) {
+	row := q.db.QueryRow(ctx, createWebhookSubscription,
+		arg.Url,
+		arg.EventTypes,
+		arg.Secret,
+		arg.MaxConcurrency,
+	)
+	var i WebhookSubscription
+	err := row.Scan(
+		&i.SubscriptionID,
+		&i.Url,
+		&i.EventTypes,
+		&i.Secret,
+		&i.MaxConcurrency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteWebhookSubscription = `-- name: DeleteWebhookSubscription :one
+DELETE FROM webhook_subscriptions WHERE subscription_id = $1
+RETURNING subscription_id, url, event_types, secret, max_concurrency, created_at
+`
+
+func (q *Queries) DeleteWebhookSubscription(ctx context.Context, subscriptionID uuid.UUID) (WebhookSubscription, error) {
+	row := q.db.QueryRow(ctx, deleteWebhookSubscription, subscriptionID)
+	var i WebhookSubscription
+	err := row.Scan(
+		&i.SubscriptionID,
+		&i.Url,
+		&i.EventTypes,
+		&i.Secret,
+		&i.MaxConcurrency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const enqueueWebhookDeliveries = `-- name: EnqueueWebhookDeliveries :execrows
+INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+SELECT subscription_id, $1, $2, $3
+FROM webhook_subscriptions
+WHERE cardinality(event_types) = 0 OR $2 = ANY(event_types)
+ON CONFLICT (subscription_id, event_id) DO NOTHING
+`
+
+type EnqueueWebhookDeliveriesParams struct {
+	EventID   uuid.UUID `json:"event_id"`
+	EventType string    `json:"event_type"`
+	Payload   []byte    `json:"payload"`
+}
+
+// Creates a delivery of the event for every subscription of its type. Publishing an event again creates no duplicates.
+func (q *Queries) EnqueueWebhookDeliveries(ctx context.Context, arg EnqueueWebhookDeliveriesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, enqueueWebhookDeliveries, arg.EventID, arg.EventType, arg.Payload)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const failWebhookDelivery = `-- name: FailWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status          = CASE WHEN $1::timestamptz IS NULL THEN 'dead' ELSE 'pending' END,
+    next_attempt_at = COALESCE($1, next_attempt_at),
+    locked_until    = NULL,
+    last_error      = $2
+WHERE delivery_id = $3
+`
+
+type FailWebhookDeliveryParams struct {
+	NextAttemptAt pgtype.Timestamptz `json:"next_attempt_at"`
+	LastError     pgtype.Text        `json:"last_error"`
+	DeliveryID    uuid.UUID          `json:"delivery_id"`
+}
+
+// A NULL next_attempt_at moves the delivery to the dead letter state.
+func (q *Queries) FailWebhookDelivery(ctx context.Context, arg FailWebhookDeliveryParams) error {
+	_, err := q.db.Exec(ctx, failWebhookDelivery, arg.NextAttemptAt, arg.LastError, arg.DeliveryID)
+	return err
+}
+
+const getWebhookDelivery = `-- name: GetWebhookDelivery :one
+SELECT delivery_id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, locked_until, last_error, created_at FROM webhook_deliveries WHERE delivery_id = $1
+`
+
+func (q *Queries) GetWebhookDelivery(ctx context.Context, deliveryID uuid.UUID) (WebhookDelivery, error) {
+	row := q.db.QueryRow(ctx, getWebhookDelivery, deliveryID)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.DeliveryID,
+		&i.SubscriptionID,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LockedUntil,
+		&i.LastError,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWebhookSubscription = `-- name: GetWebhookSubscription :one
+SELECT subscription_id, url, event_types, secret, max_concurrency, created_at FROM webhook_subscriptions WHERE subscription_id = $1
+`
+
+func (q *Queries) GetWebhookSubscription(ctx context.Context, subscriptionID uuid.UUID) (WebhookSubscription, error) {
+	row := q.db.QueryRow(ctx, getWebhookSubscription, subscriptionID)
+	var i WebhookSubscription
+	err := row.Scan(
+		&i.SubscriptionID,
+		&i.Url,
+		&i.EventTypes,
+		&i.Secret,
+		&i.MaxConcurrency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertWebhookDeliveryAttempt = `-- name: InsertWebhookDeliveryAttempt :exec
+INSERT INTO webhook_delivery_attempts (delivery_id, status_code, error, duration_ms)
+VALUES ($1, $2, $3, $4)
+`
+
+type InsertWebhookDeliveryAttemptParams struct {
+	DeliveryID uuid.UUID   `json:"delivery_id"`
+	StatusCode pgtype.Int4 `json:"status_code"`
+	Error      pgtype.Text `json:"error"`
+	DurationMs int32       `json:"duration_ms"`
+}
+
+func (q *Queries) InsertWebhookDeliveryAttempt(ctx context.Context, arg InsertWebhookDeliveryAttemptParams) error {
+	_, err := q.db.Exec(ctx, insertWebhookDeliveryAttempt,
+		arg.DeliveryID,
+		arg.StatusCode,
+		arg.Error,
+		arg.DurationMs,
+	)
+	return err
+}
+
+const listWebhookDeliveries = `-- name: ListWebhookDeliveries :many
+SELECT delivery_id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, locked_until, last_error, created_at FROM webhook_deliveries
+WHERE subscription_id = $1
+  AND ($2::text IS NULL OR status = $2)
+  AND ($3::uuid IS NULL OR delivery_id < $3)
+ORDER BY delivery_id DESC
+LIMIT $4
+`
+
+type ListWebhookDeliveriesParams struct {
+	SubscriptionID uuid.UUID   `json:"subscription_id"`
+	Status         pgtype.Text `json:"status"`
+	Cursor         pgtype.UUID `json:"cursor"`
+	MaxResults     int32       `json:"max_results"`
+}
+
+// Newest first. The cursor is the delivery_id of the last delivery of the previous page.
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.Query(ctx, listWebhookDeliveries,
+		arg.SubscriptionID,
+		arg.Status,
+		arg.Cursor,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.DeliveryID,
+			&i.SubscriptionID,
+			&i.EventID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LockedUntil,
+			&i.LastError,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookDeliveryAttempts = `-- name: ListWebhookDeliveryAttempts :many
+SELECT attempt_id, delivery_id, status_code, error, duration_ms, attempted_at FROM webhook_delivery_attempts
+WHERE delivery_id = $1
+ORDER BY attempt_id
+`
+
+func (q *Queries) ListWebhookDeliveryAttempts(ctx context.Context, deliveryID uuid.UUID) ([]WebhookDeliveryAttempt, error) {
+	rows, err := q.db.Query(ctx, listWebhookDeliveryAttempts, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDeliveryAttempt
+	for rows.Next() {
+		var i WebhookDeliveryAttempt
+		if err := rows.Scan(
+			&i.AttemptID,
+			&i.DeliveryID,
+			&i.StatusCode,
+			&i.Error,
+			&i.DurationMs,
+			&i.AttemptedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookSubscriptions = `-- name: ListWebhookSubscriptions :many
+SELECT subscription_id, url, event_types, secret, max_concurrency, created_at FROM webhook_subscriptions ORDER BY created_at
+`
+
+func (q *Queries) ListWebhookSubscriptions(ctx context.Context) ([]WebhookSubscription, error) {
+	rows, err := q.db.Query(ctx, listWebhookSubscriptions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookSubscription
+	for rows.Next() {
+		var i WebhookSubscription
+		if err := rows.Scan(
+			&i.SubscriptionID,
+			&i.Url,
+			&i.EventTypes,
+			&i.Secret,
+			&i.MaxConcurrency,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockWebhookSubscription = `-- name: LockWebhookSubscription :exec
+SELECT 1 FROM webhook_subscriptions WHERE subscription_id = $1 FOR NO KEY UPDATE
+`
+
+// Serializes the claims of a subscription across replicas until the transaction ends.
+func (q *Queries) LockWebhookSubscription(ctx context.Context, subscriptionID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, lockWebhookSubscription, subscriptionID)
+	return err
+}
+
+const redeliverWebhookDelivery = `-- name: RedeliverWebhookDelivery :one
+UPDATE webhook_deliveries
+SET status = 'pending', attempts = 0, next_attempt_at = now(), last_error = NULL
+WHERE delivery_id = $1 AND status <> 'in_flight'
+RETURNING delivery_id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, locked_until, last_error, created_at
+`
+
+// Schedules a delivery again with a fresh retry budget. Deliveries in flight cannot be redelivered.
+func (q *Queries) RedeliverWebhookDelivery(ctx context.Context, deliveryID uuid.UUID) (WebhookDelivery, error) {
+	row := q.db.QueryRow(ctx, redeliverWebhookDelivery, deliveryID)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.DeliveryID,
+		&i.SubscriptionID,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LockedUntil,
+		&i.LastError,
+		&i.CreatedAt,
+	)
+	return i, err
+}
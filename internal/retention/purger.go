@@ -0,0 +1,55 @@
+// Package retention hard-deletes soft-deleted rows once they can no longer be restored.
+package retention
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"com.tom-ludwig/go-server-template/internal/jobs"
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+// PurgeJob purges the expired users once
+var PurgeJob = jobs.Kind[PurgeArgs]("users.purge")
+
+type PurgeArgs struct{}
+
+// Purger deletes users that were soft-deleted longer than the retention window ago
+type Purger struct {
+	queries   *repository.Queries
+	retention time.Duration
+}
+
+func NewPurger(queries *repository.Queries, retention time.Duration) *Purger {
+	return &Purger{
+		queries:   queries,
+		retention: retention,
+	}
+}
+
+// Purge deletes the expired users once and returns how many were deleted.
+// It is safe to run on every replica at the same time.
+func (p *Purger) Purge(ctx context.Context) (int64, error) {
+	return p.queries.PurgeDeletedUsers(ctx, pgtype.Timestamptz{Time: time.Now().Add(-p.retention), Valid: true})
+}
+
+// Work runs a purge job
+func (p *Purger) Work(ctx context.Context, _ PurgeArgs) error {
+	purged, err := p.Purge(ctx)
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		slog.Info("Purged deleted users", "count", purged)
+	}
+	return nil
+}
+
+// EnqueuePurge enqueues a purge job, e.g. as a scheduled task. It is skipped while a purge job is pending.
+func (p *Purger) EnqueuePurge(ctx context.Context) error {
+	_, err := jobs.Enqueue(ctx, p.queries, PurgeJob, PurgeArgs{}, jobs.EnqueueOptions{UniqueKey: string(PurgeJob)})
+	return err
+}
@@ -0,0 +1,239 @@
+// Package revocation keeps an in-memory copy of revoked tokens and subjects.
+// Entries are stored in Postgres, replicas are kept in sync with LISTEN/NOTIFY.
+package revocation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lestrrat-go/jwx/v3/jwt"
+
+	"com.tom-ludwig/go-server-template/internal/pgnotify"
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+// Channel is the notification channel for denylist changes.
+// Payloads are "token:<jti>" or "subject:<issuer>|<subject>".
+const Channel = "token_revocations"
+
+// Denylist answers whether a token was revoked without a database round trip
+type Denylist struct {
+	queries *repository.Queries
+
+	mu       sync.RWMutex
+	tokens   map[string]time.Time     // jti -> expires_at
+	subjects map[subjectKey]time.Time // tokens of the subject issued before are revoked
+
+	// Notifications are queued for Run, the listener must not block on the database
+	wake      chan struct{}
+	pendingMu sync.Mutex
+	pending   map[string]struct{} // payloads to refresh
+	reload    bool                // the whole denylist must be reloaded
+}
+
+// NewDenylist creates an empty denylist. Call Load before serving requests.
+func NewDenylist(queries *repository.Queries) *Denylist {
+	return &Denylist{
+		queries:  queries,
+		tokens:   map[string]time.Time{},
+		subjects: map[subjectKey]time.Time{},
+		wake:     make(chan struct{}, 1),
+		pending:  map[string]struct{}{},
+	}
+}
+
+// subjectKey identifies a subject, a sub is only unique per issuer
+type subjectKey struct {
+	issuer  string
+	subject string
+}
+
+// TokenPayload returns the notification payload for a revoked jti
+func TokenPayload(jti string) string {
+	return "token:" + jti
+}
+
+// SubjectID identifies a subject across issuers as "<issuer>|<subject>"
+func SubjectID(issuer, subject string) string {
+	return issuer + "|" + subject
+}
+
+// SubjectPayload returns the notification payload for a revoked subject of an issuer
+func SubjectPayload(issuer, subject string) string {
+	return "subject:" + SubjectID(issuer, subject)
+}
+
+// IsRevoked reports whether the token's jti was revoked or it was issued before its subject was revoked.
+// Tokens without an iat claim are treated as revoked once their subject is.
+func (d *Denylist) IsRevoked(token jwt.Token) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if jti, ok := token.JwtID(); ok {
+		if expiresAt, found := d.tokens[jti]; found && time.Now().Before(expiresAt) {
+			return true
+		}
+	}
+
+	issuer, _ := token.Issuer()
+	if sub, ok := token.Subject(); ok {
+		if revokedBefore, found := d.subjects[subjectKey{issuer: issuer, subject: sub}]; found {
+			issuedAt, ok := token.IssuedAt()
+			if !ok || issuedAt.Before(revokedBefore) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Load replaces the cache with the current database state and prunes expired entries
+func (d *Denylist) Load(ctx context.Context) error {
+	if err := d.queries.DeleteExpiredRevokedTokens(ctx); err != nil {
+		return fmt.Errorf("failed to delete expired revoked tokens: %w", err)
+	}
+
+	revokedTokens, err := d.queries.ListRevokedTokens(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list revoked tokens: %w", err)
+	}
+	revokedSubjects, err := d.queries.ListRevokedSubjects(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list revoked subjects: %w", err)
+	}
+
+	tokens := make(map[string]time.Time, len(revokedTokens))
+	for _, token := range revokedTokens {
+		tokens[token.Jti] = token.ExpiresAt
+	}
+	subjects := make(map[subjectKey]time.Time, len(revokedSubjects))
+	for _, subject := range revokedSubjects {
+		subjects[subjectKey{issuer: subject.Issuer, subject: subject.Subject}] = subject.RevokedBefore
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.tokens = tokens
+	d.subjects = subjects
+	return nil
+}
+
+// Subscribe keeps the cache in sync with changes made by any replica, Run applies the notifications
+func (d *Denylist) Subscribe(listener *pgnotify.Listener) {
+	listener.Subscribe(Channel, func(payload string) {
+		d.pendingMu.Lock()
+		d.pending[payload] = struct{}{}
+		d.pendingMu.Unlock()
+		d.notify()
+	})
+	// Notifications sent before LISTEN, since Load or while disconnected, are lost
+	listener.OnListen(func() {
+		d.pendingMu.Lock()
+		d.reload = true
+		d.pendingMu.Unlock()
+		d.notify()
+	})
+}
+
+func (d *Denylist) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run applies notifications and reloads the denylist periodically as a safety net and to drop expired entries
+func (d *Denylist) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+			d.applyPending(ctx)
+		case <-ticker.C:
+			if err := d.Load(ctx); err != nil {
+				slog.Error("Failed to reload denylist", "error", err)
+			}
+		}
+	}
+}
+
+// applyPending reloads the denylist if requested, or else refreshes the entries of the queued notifications
+func (d *Denylist) applyPending(ctx context.Context) {
+	d.pendingMu.Lock()
+	pending, reload := d.pending, d.reload
+	d.pending, d.reload = map[string]struct{}{}, false
+	d.pendingMu.Unlock()
+
+	if reload {
+		if err := d.Load(ctx); err != nil {
+			slog.Error("Failed to reload denylist", "error", err)
+			// Retried with the next notification or reload
+			d.pendingMu.Lock()
+			d.reload = true
+			d.pendingMu.Unlock()
+		}
+		return
+	}
+	for payload := range pending {
+		if err := d.refresh(ctx, payload); err != nil {
+			slog.Error("Failed to refresh denylist entry", "payload", payload, "error", err)
+		}
+	}
+}
+
+// refresh reloads the single entry named in a notification payload
+func (d *Denylist) refresh(ctx context.Context, payload string) error {
+	kind, key, ok := strings.Cut(payload, ":")
+	if !ok {
+		return fmt.Errorf("invalid payload")
+	}
+
+	switch kind {
+	case "token":
+		token, err := d.queries.GetRevokedToken(ctx, key)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if err != nil {
+			delete(d.tokens, key)
+			return nil
+		}
+		d.tokens[key] = token.ExpiresAt
+	case "subject":
+		// Issuers are URLs and contain no "|", the subject may
+		issuer, sub, ok := strings.Cut(key, "|")
+		if !ok {
+			return fmt.Errorf("invalid subject payload")
+		}
+		subject, err := d.queries.GetRevokedSubject(ctx, repository.GetRevokedSubjectParams{Issuer: issuer, Subject: sub})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		id := subjectKey{issuer: issuer, subject: sub}
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if err != nil {
+			delete(d.subjects, id)
+			return nil
+		}
+		d.subjects[id] = subject.RevokedBefore
+	default:
+		return fmt.Errorf("unknown kind %q", kind)
+	}
+	return nil
+}
@@ -1,34 +1,97 @@
 package routes
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	"github.com/jackc/pgx/v5/pgxpool"
 	oapimiddleware "github.com/oapi-codegen/nethttp-middleware"
 
+	"com.tom-ludwig/go-server-template/internal/api/apikeys"
+	auditapi "com.tom-ludwig/go-server-template/internal/api/audit"
 	"com.tom-ludwig/go-server-template/internal/api/health"
+	metricsapi "com.tom-ludwig/go-server-template/internal/api/metrics"
+	"com.tom-ludwig/go-server-template/internal/api/revocations"
+	schedulerapi "com.tom-ludwig/go-server-template/internal/api/scheduler"
+	"com.tom-ludwig/go-server-template/internal/api/scim"
+	"com.tom-ludwig/go-server-template/internal/api/useradmin"
 	"com.tom-ludwig/go-server-template/internal/api/users"
+	"com.tom-ludwig/go-server-template/internal/api/webhooks"
+	"com.tom-ludwig/go-server-template/internal/audit"
 	"com.tom-ludwig/go-server-template/internal/config"
 	"com.tom-ludwig/go-server-template/internal/handler"
 	"com.tom-ludwig/go-server-template/internal/middleware"
 	"com.tom-ludwig/go-server-template/internal/repository"
+	"com.tom-ludwig/go-server-template/internal/scheduler"
+	"com.tom-ludwig/go-server-template/internal/userevents"
 )
 
-func NewRouter(cfg *config.Config, queries *repository.Queries, jwtAuth *middleware.JWTAuth) chi.Router {
+// Dependencies are the services shared by the handlers and middleware
+type Dependencies struct {
+	DB      *pgxpool.Pool
+	Queries *repository.Queries
+	// Authenticators are tried in order, the first one finding credentials in the request decides
+	Authenticators []middleware.Authenticator
+	// JWTAuth is nil if OIDC is disabled
+	JWTAuth *middleware.JWTAuth
+	// Idempotency replays responses for operations marked with x-idempotent
+	Idempotency *middleware.Idempotency
+	// RateLimiter limits the requests of each client, nil if rate limiting is disabled
+	RateLimiter *middleware.RateLimiter
+	// Scheduler lists the periodic tasks
+	Scheduler *scheduler.Scheduler
+	// UserEvents streams user events to the clients of GET /users/events
+	UserEvents *userevents.Broker
+}
+
+func NewRouter(cfg *config.Config, deps Dependencies) chi.Router {
 	r := chi.NewRouter()
+	queries := deps.Queries
 
 	// Core middleware (applied to all routes)
 	r.Use(chimiddleware.RequestID)
+	r.Use(middleware.ConnAddr)
 	r.Use(chimiddleware.RealIP)
+	r.Use(audit.ClientIP)
 	r.Use(middleware.RequestLogger(cfg.LogLevel == slog.LevelDebug))
 	r.Use(chimiddleware.Recoverer)
 
 	// Security headers
-	r.Use(middleware.SecurityHeaders)
+	securityPolicy := middleware.SecurityPolicy{
+		HSTSMaxAge:                cfg.HSTSMaxAge,
+		HSTSIncludeSubdomains:     cfg.HSTSIncludeSubdomains,
+		HSTSPreload:               cfg.HSTSPreload,
+		ContentSecurityPolicy:     cfg.CSPPolicy,
+		CSPRoutes:                 cfg.CSPRoutePolicies,
+		CSPReportOnly:             cfg.CSPReportOnly,
+		ReferrerPolicy:            cfg.ReferrerPolicy,
+		PermissionsPolicy:         cfg.PermissionsPolicy,
+		CrossOriginOpenerPolicy:   cfg.CrossOriginOpenerPolicy,
+		CrossOriginEmbedderPolicy: cfg.CrossOriginEmbedderPolicy,
+		CrossOriginResourcePolicy: cfg.CrossOriginResourcePolicy,
+	}
+	if cfg.CSPReportsEnabled {
+		securityPolicy.CSPReportURI = handler.CSPReportPath
+	}
+	r.Use(middleware.SecurityHeaders(securityPolicy))
+
+	if cfg.CompressionEnabled {
+		r.Use(middleware.Compress(middleware.CompressOptions{
+			MinSize:      cfg.CompressionMinSize,
+			ContentTypes: cfg.CompressionContentTypes,
+		}))
+	}
 
 	// CORS configuration
 	corsOptions := cors.Options{
@@ -41,18 +104,68 @@ func NewRouter(cfg *config.Config, queries *repository.Queries, jwtAuth *middlew
 	}
 	r.Use(cors.Handler(corsOptions))
 
+	// Load shedding, before authentication so that shed requests cost as little as possible
+	if cfg.ConcurrencyLimitEnabled {
+		limiter := middleware.NewConcurrencyLimiter(middleware.ConcurrencyLimitOptions{
+			InitialLimit:     cfg.ConcurrencyInitialLimit,
+			MinLimit:         cfg.ConcurrencyMinLimit,
+			MaxLimit:         cfg.ConcurrencyMaxLimit,
+			LatencyTarget:    cfg.ConcurrencyLatencyTarget,
+			QueueSize:        cfg.ConcurrencyQueueSize,
+			QueueTimeout:     cfg.ConcurrencyQueueTimeout,
+			LongRunningLimit: cfg.ConcurrencyLongRunning,
+		}, requestPriority(longRunningRoutes(cfg), adminRoutes()))
+		r.Use(limiter.Middleware)
+	}
+
 	// Mount Health API (public)
-	mountHealthAPI(r, queries)
+	mountHealthAPI(r, cfg, queries, deps.JWTAuth)
+
+	// Mount Users API (protected if any authentication is enabled)
+	mountUsersAPI(r, cfg, deps)
+
+	// Mount WebSocket subscriptions (authenticated by the handler, browsers cannot send headers)
+	mountWebSocket(r, cfg, deps)
+
+	// Mount CSP violation reports (public, browsers send them without credentials)
+	if cfg.CSPReportsEnabled {
+		mountCSPReports(r, deps)
+	}
+
+	// Mount User Admin API (admin only)
+	mountUserAdminAPI(r, cfg, deps)
 
-	// Mount Users API (protected with JWT auth if enabled)
-	mountUsersAPI(r, queries, jwtAuth)
+	// Mount API Keys API (admin only)
+	mountAPIKeysAPI(r, cfg, deps)
+
+	// Mount Revocations API (admin only)
+	mountRevocationsAPI(r, cfg, deps)
+
+	// Mount Audit API (admin only)
+	mountAuditAPI(r, cfg, deps)
+
+	// Mount Scheduler API (admin only)
+	mountSchedulerAPI(r, cfg, deps)
+
+	// Mount Metrics API (admin only)
+	mountMetricsAPI(r, cfg, deps)
+
+	// Mount Webhooks API (admin only)
+	if cfg.WebhooksEnabled {
+		mountWebhooksAPI(r, cfg, deps)
+	}
+
+	// Mount SCIM API (identity provider only)
+	if cfg.SCIMEnabled {
+		mountSCIMAPI(r, cfg, deps)
+	}
 
 	return r
 }
 
 // mountHealthAPI mounts health check endpoints
-func mountHealthAPI(r chi.Router, queries *repository.Queries) {
-	healthHandler := handler.NewHealthHandler(queries)
+func mountHealthAPI(r chi.Router, cfg *config.Config, queries *repository.Queries, jwtAuth *middleware.JWTAuth) {
+	healthHandler := handler.NewHealthHandler(queries, jwtAuth)
 	strictHealthServer := health.NewStrictHandler(healthHandler, nil)
 
 	healthSwagger, err := health.GetSwagger()
@@ -62,14 +175,22 @@ func mountHealthAPI(r chi.Router, queries *repository.Queries) {
 	}
 
 	r.Group(func(r chi.Router) {
+		useTimeout(r, cfg, healthSwagger)
+		useBodyLimit(r, cfg, healthSwagger)
 		r.Use(oapimiddleware.OapiRequestValidator(healthSwagger))
 		health.HandlerFromMux(strictHealthServer, r)
 	})
 }
 
 // mountUsersAPI mounts user management endpoints
-func mountUsersAPI(r chi.Router, queries *repository.Queries, jwtAuth *middleware.JWTAuth) {
-	userHandler := handler.NewUserHandler(queries)
+func mountUsersAPI(r chi.Router, cfg *config.Config, deps Dependencies) {
+	queries, authenticators := deps.Queries, deps.Authenticators
+	// Without authentication anybody may list deleted users, like the admin endpoints
+	adminScope := cfg.AdminScope
+	if len(authenticators) == 0 {
+		adminScope = ""
+	}
+	userHandler := handler.NewUserHandler(deps.DB, queries, adminScope, deps.UserEvents, cfg.SSEHeartbeatInterval)
 	strictUsersServer := users.NewStrictHandler(userHandler, nil)
 
 	usersSwagger, err := users.GetSwagger()
@@ -79,24 +200,602 @@ func mountUsersAPI(r chi.Router, queries *repository.Queries, jwtAuth *middlewar
 	}
 
 	r.Group(func(r chi.Router) {
-		// Configure request validator with JWT authentication function
-		var validatorOptions oapimiddleware.Options
-		if jwtAuth != nil {
-			validatorOptions = oapimiddleware.Options{
-				Options: openapi3filter.Options{
-					AuthenticationFunc: jwtAuth.OAPIMiddleware(jwtAuth),
-				},
-			}
-		}
-		r.Use(oapimiddleware.OapiRequestValidatorWithOptions(usersSwagger, &validatorOptions))
+		useTimeout(r, cfg, usersSwagger)
+		useBodyLimit(r, cfg, usersSwagger)
+		r.Use(requestValidator(usersSwagger, validatorOptions(authenticators)))
 
-		// Add JWT authentication if enabled
-		if jwtAuth != nil {
-			r.Use(jwtAuth.Middleware)
+		// Add authentication if enabled
+		if len(authenticators) > 0 {
+			useAuthenticate(r, deps.RateLimiter, authenticators, usersSwagger)
 			// r.Use(middleware.RequireScope("read:users"))
 			// r.Use(middleware.RequireRole("groups", "admin"))
 		}
+		useRateLimit(r, deps.RateLimiter, usersSwagger)
+		useIdempotency(r, deps.Idempotency, usersSwagger)
 
 		users.HandlerFromMux(strictUsersServer, r)
 	})
 }
+
+// mountWebSocket mounts the WebSocket endpoint for subscriptions to user events
+func mountWebSocket(r chi.Router, cfg *config.Config, deps Dependencies) {
+	adminScope := cfg.AdminScope
+	if len(deps.Authenticators) == 0 {
+		adminScope = ""
+	}
+	webSocketHandler := handler.NewWebSocketHandler(
+		deps.Authenticators, deps.JWTAuth, deps.UserEvents, adminScope, cfg.CORSAllowedOrigins,
+		cfg.WebSocketSendBuffer, cfg.WebSocketAuthTimeout, cfg.WebSocketPingInterval,
+	)
+	r.Group(func(r chi.Router) {
+		// The upgrade request is limited by IP, the handler authenticates it
+		if deps.RateLimiter != nil {
+			r.Use(deps.RateLimiter.Middleware(nil))
+		}
+		r.Get("/ws", webSocketHandler.ServeHTTP)
+	})
+}
+
+// mountCSPReports mounts the endpoint receiving Content-Security-Policy violation reports
+func mountCSPReports(r chi.Router, deps Dependencies) {
+	r.Group(func(r chi.Router) {
+		// Limited by the connection address, anyone can send reports with a made up X-Forwarded-For
+		if deps.RateLimiter != nil {
+			r.Use(deps.RateLimiter.ConnectionMiddleware("csp-reports"))
+		}
+		r.Post(handler.CSPReportPath, handler.NewCSPReportHandler().ServeHTTP)
+	})
+}
+
+// mountUserAdminAPI mounts the user lifecycle admin endpoints
+func mountUserAdminAPI(r chi.Router, cfg *config.Config, deps Dependencies) {
+	queries, authenticators := deps.Queries, deps.Authenticators
+	userAdminHandler := handler.NewUserAdminHandler(deps.DB, queries, cfg.UserRetention)
+	strictUserAdminServer := useradmin.NewStrictHandler(userAdminHandler, nil)
+
+	userAdminSwagger, err := useradmin.GetSwagger()
+	if err != nil {
+		slog.Error("Failed to load user admin swagger spec", "error", err)
+		os.Exit(1)
+	}
+
+	r.Group(func(r chi.Router) {
+		useTimeout(r, cfg, userAdminSwagger)
+		useBodyLimit(r, cfg, userAdminSwagger)
+		r.Use(oapimiddleware.OapiRequestValidatorWithOptions(userAdminSwagger, validatorOptions(authenticators)))
+		useAdminAuth(r, cfg, authenticators, deps.RateLimiter, userAdminSwagger)
+		useRateLimit(r, deps.RateLimiter, userAdminSwagger)
+		useIdempotency(r, deps.Idempotency, userAdminSwagger)
+		useradmin.HandlerFromMux(strictUserAdminServer, r)
+	})
+}
+
+// mountAPIKeysAPI mounts the API key admin endpoints
+func mountAPIKeysAPI(r chi.Router, cfg *config.Config, deps Dependencies) {
+	queries, authenticators := deps.Queries, deps.Authenticators
+	apiKeyHandler := handler.NewAPIKeyHandler(deps.DB, queries)
+	strictAPIKeysServer := apikeys.NewStrictHandler(apiKeyHandler, nil)
+
+	apiKeysSwagger, err := apikeys.GetSwagger()
+	if err != nil {
+		slog.Error("Failed to load api keys swagger spec", "error", err)
+		os.Exit(1)
+	}
+
+	r.Group(func(r chi.Router) {
+		useTimeout(r, cfg, apiKeysSwagger)
+		useBodyLimit(r, cfg, apiKeysSwagger)
+		r.Use(oapimiddleware.OapiRequestValidatorWithOptions(apiKeysSwagger, validatorOptions(authenticators)))
+		useAdminAuth(r, cfg, authenticators, deps.RateLimiter, apiKeysSwagger)
+		useRateLimit(r, deps.RateLimiter, apiKeysSwagger)
+		useIdempotency(r, deps.Idempotency, apiKeysSwagger)
+		apikeys.HandlerFromMux(strictAPIKeysServer, r)
+	})
+}
+
+// mountRevocationsAPI mounts the token revocation admin endpoints
+func mountRevocationsAPI(r chi.Router, cfg *config.Config, deps Dependencies) {
+	queries, authenticators := deps.Queries, deps.Authenticators
+	revocationHandler := handler.NewRevocationHandler(deps.DB, queries, cfg.OIDCIssuer)
+	strictRevocationsServer := revocations.NewStrictHandler(revocationHandler, nil)
+
+	revocationsSwagger, err := revocations.GetSwagger()
+	if err != nil {
+		slog.Error("Failed to load revocations swagger spec", "error", err)
+		os.Exit(1)
+	}
+
+	r.Group(func(r chi.Router) {
+		useTimeout(r, cfg, revocationsSwagger)
+		useBodyLimit(r, cfg, revocationsSwagger)
+		r.Use(oapimiddleware.OapiRequestValidatorWithOptions(revocationsSwagger, validatorOptions(authenticators)))
+		useAdminAuth(r, cfg, authenticators, deps.RateLimiter, revocationsSwagger)
+		useRateLimit(r, deps.RateLimiter, revocationsSwagger)
+		useIdempotency(r, deps.Idempotency, revocationsSwagger)
+		revocations.HandlerFromMux(strictRevocationsServer, r)
+	})
+}
+
+// mountAuditAPI mounts the audit log admin endpoints
+func mountAuditAPI(r chi.Router, cfg *config.Config, deps Dependencies) {
+	queries, authenticators := deps.Queries, deps.Authenticators
+	auditHandler := handler.NewAuditHandler(queries)
+	strictAuditServer := auditapi.NewStrictHandler(auditHandler, nil)
+
+	auditSwagger, err := auditapi.GetSwagger()
+	if err != nil {
+		slog.Error("Failed to load audit swagger spec", "error", err)
+		os.Exit(1)
+	}
+
+	r.Group(func(r chi.Router) {
+		useTimeout(r, cfg, auditSwagger)
+		useBodyLimit(r, cfg, auditSwagger)
+		r.Use(oapimiddleware.OapiRequestValidatorWithOptions(auditSwagger, validatorOptions(authenticators)))
+		useAdminAuth(r, cfg, authenticators, deps.RateLimiter, auditSwagger)
+		useRateLimit(r, deps.RateLimiter, auditSwagger)
+		auditapi.HandlerFromMux(strictAuditServer, r)
+	})
+}
+
+// mountSchedulerAPI mounts the scheduled task admin endpoints
+func mountSchedulerAPI(r chi.Router, cfg *config.Config, deps Dependencies) {
+	queries, authenticators := deps.Queries, deps.Authenticators
+	schedulerHandler := handler.NewSchedulerHandler(queries, deps.Scheduler)
+	strictSchedulerServer := schedulerapi.NewStrictHandler(schedulerHandler, nil)
+
+	schedulerSwagger, err := schedulerapi.GetSwagger()
+	if err != nil {
+		slog.Error("Failed to load scheduler swagger spec", "error", err)
+		os.Exit(1)
+	}
+
+	r.Group(func(r chi.Router) {
+		useTimeout(r, cfg, schedulerSwagger)
+		useBodyLimit(r, cfg, schedulerSwagger)
+		r.Use(oapimiddleware.OapiRequestValidatorWithOptions(schedulerSwagger, validatorOptions(authenticators)))
+		useAdminAuth(r, cfg, authenticators, deps.RateLimiter, schedulerSwagger)
+		useRateLimit(r, deps.RateLimiter, schedulerSwagger)
+		schedulerapi.HandlerFromMux(strictSchedulerServer, r)
+	})
+}
+
+// mountMetricsAPI mounts the metrics endpoint
+func mountMetricsAPI(r chi.Router, cfg *config.Config, deps Dependencies) {
+	authenticators := deps.Authenticators
+	strictMetricsServer := metricsapi.NewStrictHandler(handler.NewMetricsHandler(), nil)
+
+	metricsSwagger, err := metricsapi.GetSwagger()
+	if err != nil {
+		slog.Error("Failed to load metrics swagger spec", "error", err)
+		os.Exit(1)
+	}
+
+	r.Group(func(r chi.Router) {
+		useTimeout(r, cfg, metricsSwagger)
+		useBodyLimit(r, cfg, metricsSwagger)
+		r.Use(oapimiddleware.OapiRequestValidatorWithOptions(metricsSwagger, validatorOptions(authenticators)))
+		useAdminAuth(r, cfg, authenticators, deps.RateLimiter, metricsSwagger)
+		useRateLimit(r, deps.RateLimiter, metricsSwagger)
+		metricsapi.HandlerFromMux(strictMetricsServer, r)
+	})
+}
+
+// mountWebhooksAPI mounts the webhook subscription admin endpoints
+func mountWebhooksAPI(r chi.Router, cfg *config.Config, deps Dependencies) {
+	queries, authenticators := deps.Queries, deps.Authenticators
+	webhookHandler := handler.NewWebhookHandler(deps.DB, queries)
+	strictWebhooksServer := webhooks.NewStrictHandler(webhookHandler, nil)
+
+	webhooksSwagger, err := webhooks.GetSwagger()
+	if err != nil {
+		slog.Error("Failed to load webhooks swagger spec", "error", err)
+		os.Exit(1)
+	}
+
+	r.Group(func(r chi.Router) {
+		useTimeout(r, cfg, webhooksSwagger)
+		useBodyLimit(r, cfg, webhooksSwagger)
+		r.Use(oapimiddleware.OapiRequestValidatorWithOptions(webhooksSwagger, validatorOptions(authenticators)))
+		useAdminAuth(r, cfg, authenticators, deps.RateLimiter, webhooksSwagger)
+		useRateLimit(r, deps.RateLimiter, webhooksSwagger)
+		useIdempotency(r, deps.Idempotency, webhooksSwagger)
+		webhooks.HandlerFromMux(strictWebhooksServer, r)
+	})
+}
+
+// mountSCIMAPI mounts the SCIM 2.0 provisioning endpoints. Every error, including auth and validation errors, uses the SCIM error format.
+func mountSCIMAPI(r chi.Router, cfg *config.Config, deps Dependencies) {
+	authenticators := deps.Authenticators
+	scimHandler := handler.NewSCIMHandler(deps.DB, deps.Queries, cfg.SCIMIssuer)
+	strictSCIMServer := scim.NewStrictHandlerWithOptions(scimHandler, nil, scim.StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			handler.WriteSCIMError(w, r, http.StatusBadRequest, err.Error())
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			slog.Error("Failed to write scim response", "error", err)
+			handler.WriteSCIMError(w, r, http.StatusInternalServerError, "An internal server error occurred")
+		},
+	})
+
+	scimSwagger, err := scim.GetSwagger()
+	if err != nil {
+		slog.Error("Failed to load scim swagger spec", "error", err)
+		os.Exit(1)
+	}
+
+	// The shared SCIM token is tried first, other bearer tokens fall through to the JWT authenticator
+	if cfg.SCIMToken != "" {
+		authenticators = append([]middleware.Authenticator{middleware.NewStaticTokenAuth(cfg.SCIMToken, "scim", cfg.SCIMScope)}, authenticators...)
+	}
+
+	// SCIM clients send application/scim+json, but some use application/json
+	openapi3filter.RegisterBodyDecoder("application/scim+json", openapi3filter.JSONBodyDecoder)
+
+	options := validatorOptions(authenticators)
+	options.ErrorHandler = func(w http.ResponseWriter, message string, statusCode int) {
+		handler.WriteSCIMError(w, nil, statusCode, message)
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.UseErrorWriter(handler.WriteSCIMError))
+		useTimeout(r, cfg, scimSwagger)
+		r.Use(scimContentType)
+		useBodyLimit(r, cfg, scimSwagger)
+		r.Use(oapimiddleware.OapiRequestValidatorWithOptions(scimSwagger, options))
+		if len(authenticators) == 0 {
+			slog.Warn("No authentication configured, SCIM endpoints are not protected")
+		} else {
+			useAuthenticate(r, deps.RateLimiter, authenticators, scimSwagger)
+			r.Use(middleware.RequireScope(cfg.SCIMScope))
+		}
+		useRateLimit(r, deps.RateLimiter, scimSwagger)
+		scim.HandlerFromMux(strictSCIMServer, r)
+	})
+}
+
+// scimContentType treats application/json request bodies as application/scim+json
+func scimContentType(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if mediaType, _, _ := strings.Cut(r.Header.Get("Content-Type"), ";"); strings.TrimSpace(mediaType) == "application/json" {
+			r.Header.Set("Content-Type", "application/scim+json")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestPriority classifies requests for load shedding. Health checks must answer while the server is
+// overloaded, otherwise it is restarted when it is busiest, and admins must be able to intervene. Streams are
+// long-lived and would hold their slot for hours, they are limited by their own buffers instead. Operations
+// matched by longRunning, e.g. the bulk operations, take minutes by design, so they must not shrink the limit.
+// The limiter runs before authentication, so admin operations are only critical with credentials, which
+// Authenticate checks next; anonymous requests and unknown paths under /admin/ are shed like any other.
+func requestPriority(longRunning, admin *chi.Mux) func(r *http.Request) middleware.Priority {
+	return func(r *http.Request) middleware.Priority {
+		switch {
+		case r.URL.Path == "/healthz" || r.URL.Path == "/livez" || r.URL.Path == "/readyz":
+			return middleware.PriorityCritical
+		case middleware.HasCredentials(r) && admin.Match(chi.NewRouteContext(), r.Method, r.URL.Path):
+			return middleware.PriorityCritical
+		case r.URL.Path == "/users/events" || r.URL.Path == "/ws":
+			return middleware.PriorityCritical
+		case longRunning.Match(chi.NewRouteContext(), r.Method, r.URL.Path):
+			return middleware.PriorityLongRunning
+		default:
+			return middleware.PriorityNormal
+		}
+	}
+}
+
+// loadSwaggers loads the specs of all APIs
+func loadSwaggers() []*openapi3.T {
+	loaders := []func() (*openapi3.T, error){
+		health.GetSwagger, users.GetSwagger, useradmin.GetSwagger, apikeys.GetSwagger, revocations.GetSwagger,
+		auditapi.GetSwagger, schedulerapi.GetSwagger, metricsapi.GetSwagger, webhooks.GetSwagger, scim.GetSwagger,
+	}
+	swaggers := make([]*openapi3.T, 0, len(loaders))
+	for _, load := range loaders {
+		swagger, err := load()
+		if err != nil {
+			slog.Error("Failed to load swagger spec", "error", err)
+			os.Exit(1)
+		}
+		swaggers = append(swaggers, swagger)
+	}
+	return swaggers
+}
+
+// longRunningRoutes matches the operations of all specs declaring an x-timeout above cfg.RequestTimeout.
+// The limiter runs before routing, so the requests are matched against the patterns here.
+func longRunningRoutes(cfg *config.Config) *chi.Mux {
+	mux := chi.NewMux()
+	for _, swagger := range loadSwaggers() {
+		for route, timeout := range timeoutRoutes(swagger) {
+			if timeout > cfg.RequestTimeout {
+				method, pattern, _ := strings.Cut(route, " ")
+				mux.Method(method, pattern, http.NotFoundHandler())
+			}
+		}
+	}
+	return mux
+}
+
+// adminRoutes matches the operations of all specs under /admin/, like longRunningRoutes
+func adminRoutes() *chi.Mux {
+	mux := chi.NewMux()
+	for _, swagger := range loadSwaggers() {
+		for pattern, pathItem := range swagger.Paths.Map() {
+			if !strings.HasPrefix(pattern, "/admin/") {
+				continue
+			}
+			for method := range pathItem.Operations() {
+				mux.Method(method, pattern, http.NotFoundHandler())
+			}
+		}
+	}
+	return mux
+}
+
+// useTimeout sets the deadline of the requests, cfg.RequestTimeout unless the operation declares x-timeout.
+// Operations marked with x-streaming: true have no deadline. It should be the first middleware of the group,
+// so that the deadline also covers the authentication.
+//
+//	x-timeout: 60s
+func useTimeout(r chi.Router, cfg *config.Config, swagger *openapi3.T) {
+	r.Use(middleware.Timeout(cfg.RequestTimeout, timeoutRoutes(swagger)))
+}
+
+// timeoutRoutes returns the timeouts of the operations of the spec declaring x-timeout or x-streaming, as "METHOD /pattern"
+func timeoutRoutes(swagger *openapi3.T) map[string]time.Duration {
+	routes := map[string]time.Duration{}
+	for _, route := range extensionRoutes(swagger, "x-streaming") {
+		routes[route] = 0
+	}
+	for path, pathItem := range swagger.Paths.Map() {
+		for method, operation := range pathItem.Operations() {
+			extension, ok := operation.Extensions["x-timeout"]
+			if !ok {
+				continue
+			}
+			value, _ := extension.(string)
+			timeout, err := time.ParseDuration(value)
+			if err != nil || timeout <= 0 {
+				slog.Error("Invalid x-timeout, must be a positive duration", "operation", operation.OperationID, "value", extension)
+				os.Exit(1)
+			}
+			routes[method+" "+path] = timeout
+		}
+	}
+	return routes
+}
+
+// useBodyLimit limits the request bodies to cfg.RequestBodyLimit unless the operation declares x-body-limit in bytes,
+// accepts the media types of the operation's requestBody and checks JSON bodies against its schema.
+// It must be added before the request validator, which reads the body.
+//
+//	x-body-limit: 104857600 # 100 MiB
+func useBodyLimit(r chi.Router, cfg *config.Config, swagger *openapi3.T) {
+	r.Use(middleware.BodyLimit(middleware.BodyLimitOptions{
+		DefaultLimit: cfg.RequestBodyLimit,
+		MaxDepth:     cfg.JSONMaxDepth,
+		Routes:       bodyRules(swagger),
+	}))
+}
+
+// bodyRules returns the body rules of the operations of the spec with a requestBody, as "METHOD /pattern"
+func bodyRules(swagger *openapi3.T) map[string]middleware.BodyRule {
+	rules := map[string]middleware.BodyRule{}
+	for path, pathItem := range swagger.Paths.Map() {
+		for method, operation := range pathItem.Operations() {
+			rule := middleware.BodyRule{Schemas: map[string]*openapi3.Schema{}}
+			if extension, ok := operation.Extensions["x-body-limit"]; ok {
+				limit, _ := extension.(float64)
+				if limit < 1 || limit != float64(int64(limit)) {
+					slog.Error("Invalid x-body-limit, must be a positive number of bytes", "operation", operation.OperationID, "value", extension)
+					os.Exit(1)
+				}
+				rule.Limit = int64(limit)
+			}
+			rule.Stream, _ = operation.Extensions["x-stream-request-body"].(bool)
+			if operation.RequestBody != nil && operation.RequestBody.Value != nil {
+				for mediaType, content := range operation.RequestBody.Value.Content {
+					rule.MediaTypes = append(rule.MediaTypes, mediaType)
+					if content.Schema != nil {
+						rule.Schemas[mediaType] = content.Schema.Value
+					}
+				}
+				slices.Sort(rule.MediaTypes)
+			}
+			rules[method+" "+path] = rule
+		}
+	}
+	return rules
+}
+
+// useAdminAuth requires an authenticated caller with the admin scope.
+// Without any authenticator the admin endpoints are open, which is only acceptable for local development.
+func useAdminAuth(r chi.Router, cfg *config.Config, authenticators []middleware.Authenticator, limiter *middleware.RateLimiter, swagger *openapi3.T) {
+	if len(authenticators) == 0 {
+		slog.Warn("No authentication configured, admin endpoints are not protected")
+		return
+	}
+	useAuthenticate(r, limiter, authenticators, swagger)
+	r.Use(middleware.RequireScope(cfg.AdminScope))
+}
+
+// useIdempotency enables Idempotency-Key for the operations of the spec marked with x-idempotent: true.
+// It must be added after authentication, keys are scoped to the caller.
+func useIdempotency(r chi.Router, idempotency *middleware.Idempotency, swagger *openapi3.T) {
+	if idempotency == nil {
+		return
+	}
+
+	if idempotentRoutes := extensionRoutes(swagger, "x-idempotent"); len(idempotentRoutes) > 0 {
+		r.Use(idempotency.Middleware(idempotentRoutes...))
+	}
+}
+
+// useAuthenticate authenticates the requests with the first authenticator finding credentials.
+// Failed authentications are limited per IP address, and operations get the DPoP mode of their security scheme.
+func useAuthenticate(r chi.Router, limiter *middleware.RateLimiter, authenticators []middleware.Authenticator, swagger *openapi3.T) {
+	useAuthFailureLimit(r, limiter)
+	if routes := dpopRoutes(swagger); len(routes) > 0 {
+		r.Use(middleware.DPoPModes(routes))
+	}
+	r.Use(middleware.Authenticate(authenticators...))
+}
+
+// dpopRoutes returns the DPoP modes of the operations of the spec secured by a scheme declaring x-dpop,
+// as "METHOD /pattern". Other operations use OIDC_DPOP:
+//
+//	securitySchemes:
+//	  JWT Auth:
+//	    type: http
+//	    scheme: bearer
+//	    x-dpop: required # disabled, optional or required
+func dpopRoutes(swagger *openapi3.T) map[string]string {
+	routes := map[string]string{}
+	if swagger.Components == nil {
+		return routes
+	}
+	for path, pathItem := range swagger.Paths.Map() {
+		for method, operation := range pathItem.Operations() {
+			security := swagger.Security
+			if operation.Security != nil {
+				security = *operation.Security
+			}
+			for _, requirement := range security {
+				for name := range requirement {
+					scheme := swagger.Components.SecuritySchemes[name]
+					if scheme == nil || scheme.Value == nil {
+						continue
+					}
+					extension, ok := scheme.Value.Extensions["x-dpop"]
+					if !ok {
+						continue
+					}
+					mode, _ := extension.(string)
+					if mode != middleware.DPoPDisabled && mode != middleware.DPoPOptional && mode != middleware.DPoPRequired {
+						slog.Error("Invalid x-dpop, must be disabled, optional or required", "scheme", name, "value", extension)
+						os.Exit(1)
+					}
+					routes[method+" "+path] = mode
+				}
+			}
+		}
+	}
+	return routes
+}
+
+// useAuthFailureLimit limits the failed authentications of each IP address.
+// It must be added before authentication, which it counts the failures of.
+func useAuthFailureLimit(r chi.Router, limiter *middleware.RateLimiter) {
+	if limiter == nil {
+		return
+	}
+	r.Use(limiter.AuthFailureMiddleware())
+}
+
+// useRateLimit limits the requests of each client, with the limits of operations declaring x-rate-limit.
+// It must be added after authentication, clients are identified by their credentials.
+func useRateLimit(r chi.Router, limiter *middleware.RateLimiter, swagger *openapi3.T) {
+	if limiter == nil {
+		return
+	}
+	r.Use(limiter.Middleware(rateLimitRoutes(swagger, limiter)))
+}
+
+// rateLimitRoutes returns the limits of the operations of the spec declaring x-rate-limit, as "METHOD /pattern".
+// The algorithm defaults to the one of the limiter:
+//
+//	x-rate-limit:
+//	  requests: 10
+//	  period: 1m
+//	  algorithm: sliding-window
+func rateLimitRoutes(swagger *openapi3.T, limiter *middleware.RateLimiter) map[string]middleware.RateLimit {
+	routes := map[string]middleware.RateLimit{}
+	for path, pathItem := range swagger.Paths.Map() {
+		for method, operation := range pathItem.Operations() {
+			extension, ok := operation.Extensions["x-rate-limit"]
+			if !ok {
+				continue
+			}
+			limit, err := parseRateLimit(extension, limiter.DefaultLimit().Algorithm)
+			if err != nil {
+				slog.Error("Invalid x-rate-limit", "operation", operation.OperationID, "error", err)
+				os.Exit(1)
+			}
+			routes[method+" "+path] = limit
+		}
+	}
+	return routes
+}
+
+func parseRateLimit(extension any, algorithm middleware.RateLimitAlgorithm) (middleware.RateLimit, error) {
+	fields, ok := extension.(map[string]any)
+	if !ok {
+		return middleware.RateLimit{}, errors.New("must be an object with requests, period and optionally algorithm")
+	}
+	requests, _ := fields["requests"].(float64)
+	period, _ := fields["period"].(string)
+	duration, err := time.ParseDuration(period)
+	if err != nil {
+		return middleware.RateLimit{}, fmt.Errorf("invalid period %q", period)
+	}
+	if name, ok := fields["algorithm"].(string); ok {
+		algorithm = middleware.RateLimitAlgorithm(name)
+	}
+
+	limit := middleware.RateLimit{Algorithm: algorithm, Requests: int(requests), Period: duration}
+	return limit, limit.Validate()
+}
+
+// requestValidator validates requests against the spec. The body of operations marked with
+// x-stream-request-body: true is not validated, the validator would read it into memory.
+func requestValidator(swagger *openapi3.T, options *oapimiddleware.Options) func(http.Handler) http.Handler {
+	validate := oapimiddleware.OapiRequestValidatorWithOptions(swagger, options)
+	streamedRoutes := extensionRoutes(swagger, "x-stream-request-body")
+	if len(streamedRoutes) == 0 {
+		return validate
+	}
+
+	streamOptions := *options
+	streamOptions.Options.ExcludeRequestBody = true
+	validateStreamed := oapimiddleware.OapiRequestValidatorWithOptions(swagger, &streamOptions)
+
+	return func(next http.Handler) http.Handler {
+		validated, streamed := validate(next), validateStreamed(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(streamedRoutes, r.Method+" "+chi.RouteContext(r.Context()).RoutePattern()) {
+				streamed.ServeHTTP(w, r)
+				return
+			}
+			validated.ServeHTTP(w, r)
+		})
+	}
+}
+
+// extensionRoutes returns the operations of the spec with the extension set to true, as "METHOD /pattern"
+func extensionRoutes(swagger *openapi3.T, extension string) []string {
+	var routes []string
+	for path, pathItem := range swagger.Paths.Map() {
+		for method, operation := range pathItem.Operations() {
+			if enabled, _ := operation.Extensions[extension].(bool); enabled {
+				routes = append(routes, method+" "+path)
+			}
+		}
+	}
+	return routes
+}
+
+// validatorOptions configures the request validator to accept requests with credentials for the declared security schemes
+func validatorOptions(authenticators []middleware.Authenticator) *oapimiddleware.Options {
+	if len(authenticators) == 0 {
+		return &oapimiddleware.Options{}
+	}
+	return &oapimiddleware.Options{
+		Options: openapi3filter.Options{
+			AuthenticationFunc: middleware.OAPIAuthenticationFunc(),
+		},
+	}
+}
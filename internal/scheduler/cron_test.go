@@ -0,0 +1,148 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr string
+	}{
+		{"*/15 * * * *", ""},
+		{"0 3 * * 1-5", ""},
+		{"0 0 1,15 * *", ""},
+		{"5/15 * * * *", ""},
+		{"0 0 * * 7", ""},
+		{"@daily", ""},
+		{"@every 90s", ""},
+		{"  @hourly  ", ""},
+		{"@every 500ms", "at least 1s"},
+		{"@every soon", "invalid interval"},
+		{"* * * *", "expected 5 fields"},
+		{"* * * * * *", "expected 5 fields"},
+		{"60 * * * *", "minute"},
+		{"* 24 * * *", "hour"},
+		{"* * 0 * *", "day of month"},
+		{"* * 32 * *", "day of month"},
+		{"* * * 13 *", "month"},
+		{"* * * * 8", "day of week"},
+		{"10-5 * * * *", "invalid range"},
+		{"*/0 * * * *", "invalid step"},
+		{"a * * * *", "minute"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := Parse(tt.spec)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Parse(%q) error = %v, want nil", tt.spec, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse(%q) error = %v, want %q", tt.spec, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	utc := func(value string) time.Time {
+		t.Helper()
+		parsed, err := time.Parse(time.DateTime, value)
+		if err != nil {
+			t.Fatalf("time.Parse(%q) error = %v", value, err)
+		}
+		return parsed
+	}
+	local := func(value string) time.Time {
+		t.Helper()
+		parsed, err := time.ParseInLocation(time.DateTime, value, berlin)
+		if err != nil {
+			t.Fatalf("time.ParseInLocation(%q) error = %v", value, err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name  string
+		spec  string
+		after time.Time
+		want  time.Time
+	}{
+		{"next minute", "* * * * *", utc("2026-01-01 10:00:30"), utc("2026-01-01 10:01:00")},
+		{"exactly on a match is not due again", "0 * * * *", utc("2026-01-01 10:00:00"), utc("2026-01-01 11:00:00")},
+		{"step", "*/15 * * * *", utc("2026-01-01 10:16:00"), utc("2026-01-01 10:30:00")},
+		{"next day", "30 3 * * *", utc("2026-01-01 04:00:00"), utc("2026-01-02 03:30:00")},
+		{"end of year", "0 0 * * *", utc("2026-12-31 23:59:00"), utc("2027-01-01 00:00:00")},
+
+		{"day 31 skips shorter months", "0 0 31 * *", utc("2026-01-31 00:00:00"), utc("2026-03-31 00:00:00")},
+		{"day 31 skips April", "0 0 31 * *", utc("2026-03-31 00:00:00"), utc("2026-05-31 00:00:00")},
+		{"day 30 skips February", "0 12 30 * *", utc("2026-01-30 12:00:00"), utc("2026-03-30 12:00:00")},
+		{"February 29 in a leap year", "0 0 29 2 *", utc("2026-03-01 00:00:00"), utc("2028-02-29 00:00:00")},
+		{"February 30 never", "0 0 30 2 *", utc("2026-01-01 00:00:00"), time.Time{}},
+		{"last days of the month", "0 0 28-31 * *", utc("2026-02-28 00:00:00"), utc("2026-03-28 00:00:00")},
+		{"@monthly from the last day", "@monthly", utc("2026-04-30 23:59:00"), utc("2026-05-01 00:00:00")},
+
+		{"day of month or weekday", "0 0 13 * 5", utc("2026-02-01 00:00:00"), utc("2026-02-06 00:00:00")},
+		{"day of month and any weekday", "0 0 13 * *", utc("2026-02-01 00:00:00"), utc("2026-02-13 00:00:00")},
+		{"Sunday as 7", "0 0 * * 7", utc("2026-01-01 00:00:00"), utc("2026-01-04 00:00:00")},
+
+		// Expressions are evaluated in UTC, local daylight saving time changes neither skip nor repeat runs
+		{"spring forward, hour missing in local time", "30 1 * * *", local("2026-03-29 01:59:00"), utc("2026-03-29 01:30:00")},
+		{"spring forward, next local day", "0 2 * * *", local("2026-03-29 04:00:00"), utc("2026-03-30 02:00:00")},
+		{"fall back, first local 02:30", "30 0 * * *", utc("2026-10-25 00:00:00").In(berlin), utc("2026-10-25 00:30:00")},
+		{"fall back, repeated local 02:30", "30 1 * * *", utc("2026-10-25 00:45:00").In(berlin), utc("2026-10-25 01:30:00")},
+		{"@every keeps the location", "@every 1h", local("2026-03-29 01:30:00"), local("2026-03-29 03:30:00")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.spec, err)
+			}
+			if got := schedule.Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+// An hourly expression runs once an hour across the local time changes, 24 times a day
+func TestNextHourlyAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	schedule, err := Parse("@hourly")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		day  time.Time
+	}{
+		{"spring forward", time.Date(2026, 3, 29, 0, 0, 0, 0, berlin)},
+		{"fall back", time.Date(2026, 10, 25, 0, 0, 0, 0, berlin)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := schedule.Next(tt.day)
+			for range 23 {
+				next := schedule.Next(previous)
+				if gap := next.Sub(previous); gap != time.Hour {
+					t.Fatalf("Next(%v) = %v, %v later, want 1h", previous, next, gap)
+				}
+				previous = next
+			}
+		})
+	}
+}
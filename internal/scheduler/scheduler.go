@@ -0,0 +1,285 @@
+// Package scheduler runs periodic tasks on a single replica. Replicas elect the leader with a Postgres
+// advisory lock, which is released when the leader stops or loses its connection, so another replica takes over.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+// Disabled is the schedule of tasks that never run, e.g. to turn off a task in the config
+const Disabled = "off"
+
+// lockKey identifies the advisory lock of the leader
+const lockKey int64 = 0x5c4ed
+
+// Task is a periodic task. Runs of the same task never overlap, a run that is due while the
+// previous one is still running is skipped. ctx is canceled when the replica stops leading.
+type Task func(ctx context.Context) error
+
+type task struct {
+	name     string
+	spec     string
+	schedule Schedule
+	fn       Task
+	running  atomic.Bool
+}
+
+// TaskInfo describes a registered task
+type TaskInfo struct {
+	Name      string
+	Spec      string
+	NextRunAt time.Time
+}
+
+// Scheduler runs the registered tasks while it is the leader
+type Scheduler struct {
+	db               *pgxpool.Pool
+	queries          *repository.Queries
+	electionInterval time.Duration
+	runRetention     time.Duration
+	replica          string
+
+	tasks  []*task
+	leader atomic.Bool
+}
+
+// New creates a scheduler that tries to become the leader every electionInterval.
+// The history of runs is kept for runRetention.
+func New(db *pgxpool.Pool, queries *repository.Queries, electionInterval, runRetention time.Duration) *Scheduler {
+	replica, err := os.Hostname()
+	if err != nil {
+		replica = "unknown"
+	}
+	return &Scheduler{
+		db:               db,
+		queries:          queries,
+		electionInterval: electionInterval,
+		runRetention:     runRetention,
+		replica:          replica,
+	}
+}
+
+// Register adds a task running on the cron expression spec, see Parse. A task with the schedule Disabled
+// is not registered. It must be called before Run.
+func (s *Scheduler) Register(name, spec string, fn Task) error {
+	if spec == Disabled {
+		slog.Info("Scheduled task disabled", "task", name)
+		return nil
+	}
+	if slices.ContainsFunc(s.tasks, func(t *task) bool { return t.name == name }) {
+		return fmt.Errorf("task %s is already registered", name)
+	}
+	schedule, err := Parse(spec)
+	if err != nil {
+		return fmt.Errorf("invalid schedule of task %s: %w", name, err)
+	}
+	s.tasks = append(s.tasks, &task{name: name, spec: spec, schedule: schedule, fn: fn})
+	return nil
+}
+
+// Tasks returns the registered tasks with their next run as seen from now
+func (s *Scheduler) Tasks() []TaskInfo {
+	now := time.Now()
+	tasks := make([]TaskInfo, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		tasks = append(tasks, TaskInfo{Name: t.name, Spec: t.spec, NextRunAt: t.schedule.Next(now)})
+	}
+	return tasks
+}
+
+// IsLeader reports whether this replica currently runs the tasks
+func (s *Scheduler) IsLeader() bool {
+	return s.leader.Load()
+}
+
+// DeleteOldRuns deletes the history of runs older than the retention. Register it as a task.
+func (s *Scheduler) DeleteOldRuns(ctx context.Context) error {
+	deleted, err := s.queries.DeleteScheduledTaskRuns(ctx, time.Now().Add(-s.runRetention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		slog.Debug("Deleted scheduled task runs", "count", deleted)
+	}
+	return nil
+}
+
+// Run campaigns for leadership until ctx is done and runs the tasks while it is the leader
+func (s *Scheduler) Run(ctx context.Context) {
+	if len(s.tasks) == 0 {
+		return
+	}
+
+	for {
+		if err := s.campaign(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("Scheduler lost leadership", "replica", s.replica, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.electionInterval):
+		}
+	}
+}
+
+// campaign takes the lock if it is free and leads until ctx is done or the lock's connection fails
+func (s *Scheduler) campaign(ctx context.Context) error {
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	lockQueries := repository.New(conn)
+
+	locked, err := lockQueries.TryAdvisoryLock(ctx, lockKey)
+	if err != nil || !locked {
+		return err
+	}
+	defer func() {
+		// Closing the session releases the lock if it cannot be unlocked
+		if err := lockQueries.AdvisoryUnlock(context.WithoutCancel(ctx), lockKey); err != nil {
+			_ = conn.Conn().Close(context.WithoutCancel(ctx))
+		}
+	}()
+
+	s.leader.Store(true)
+	defer s.leader.Store(false)
+	slog.Info("Became scheduler leader", "replica", s.replica, "tasks", len(s.tasks))
+
+	return s.lead(ctx, conn)
+}
+
+// lead starts the tasks when they are due. Running tasks are canceled and awaited when it returns.
+func (s *Scheduler) lead(ctx context.Context, conn *pgxpool.Conn) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	taskCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	now := time.Now()
+	due := map[*task]time.Time{}
+	for _, t := range s.tasks {
+		if next := t.schedule.Next(now); !next.IsZero() {
+			due[t] = next
+		}
+	}
+
+	// A leader that cannot reach the database has lost the lock with its session
+	health := time.NewTicker(s.electionInterval)
+	defer health.Stop()
+
+	for {
+		var next time.Time
+		for _, at := range due {
+			if next.IsZero() || at.Before(next) {
+				next = at
+			}
+		}
+		timer := time.NewTimer(time.Until(next))
+		if next.IsZero() {
+			timer.Stop()
+		}
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-health.C:
+			timer.Stop()
+			if err := conn.Ping(ctx); err != nil {
+				return fmt.Errorf("failed to reach the database: %w", err)
+			}
+		case now := <-timer.C:
+			for t, at := range due {
+				if at.After(now) {
+					continue
+				}
+				s.start(taskCtx, &wg, t)
+				if next := t.schedule.Next(now); !next.IsZero() {
+					due[t] = next
+				} else {
+					delete(due, t)
+				}
+			}
+		}
+	}
+}
+
+// start runs a task in the background unless it is still running
+func (s *Scheduler) start(ctx context.Context, wg *sync.WaitGroup, t *task) {
+	if !t.running.CompareAndSwap(false, true) {
+		slog.Warn("Skipped scheduled task, the previous run is still running", "task", t.name)
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer t.running.Store(false)
+		s.run(ctx, t)
+	}()
+}
+
+// run runs a task once and records the run
+func (s *Scheduler) run(ctx context.Context, t *task) {
+	run, err := s.queries.StartScheduledTaskRun(ctx, repository.StartScheduledTaskRunParams{
+		Task:    t.name,
+		Replica: s.replica,
+	})
+	if err != nil {
+		slog.Error("Failed to record scheduled task run", "task", t.name, "error", err)
+	}
+
+	start := time.Now()
+	taskErr := call(ctx, t)
+	if taskErr != nil {
+		slog.Error("Scheduled task failed", "task", t.name, "duration", time.Since(start), "error", taskErr)
+	} else {
+		slog.Debug("Scheduled task completed", "task", t.name, "duration", time.Since(start))
+	}
+
+	if err != nil {
+		return
+	}
+	var errorText pgtype.Text
+	if taskErr != nil {
+		errorText = pgtype.Text{String: taskErr.Error(), Valid: true}
+	}
+	err = s.queries.FinishScheduledTaskRun(context.WithoutCancel(ctx), repository.FinishScheduledTaskRunParams{
+		Error: errorText,
+		RunID: run.RunID,
+	})
+	if err != nil {
+		slog.Error("Failed to record scheduled task run", "task", t.name, "run_id", run.RunID, "error", err)
+	}
+}
+
+// call runs the task, turning a panic into an error
+func call(ctx context.Context, t *task) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			slog.Error("Scheduled task panicked", "task", t.name, "panic", recovered, "stack", string(debug.Stack()))
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	err = t.fn(ctx)
+	if err != nil && ctx.Err() != nil {
+		err = fmt.Errorf("canceled, the replica stopped leading: %w", err)
+	}
+	return err
+}
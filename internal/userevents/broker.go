@@ -0,0 +1,245 @@
+// Package userevents fans out the user events of the outbox to streaming clients on every replica.
+// A transaction writing user events notifies all replicas with LISTEN/NOTIFY when it commits,
+// each replica then reads the new events from the outbox and hands them to its subscribers.
+//
+// Event IDs are assigned on insert, so a long transaction commits events older than ones already sent.
+// Instead of the IDs, the broker follows the commit horizon: every transaction below it has ended,
+// so the events of transactions from the horizon on are the only ones that can still appear.
+package userevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"com.tom-ludwig/go-server-template/internal/pgnotify"
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+// Channel is notified by transactions that wrote user events
+const Channel = "user_events"
+
+const (
+	// pollInterval repairs notifications lost while the listener reconnects
+	pollInterval = 5 * time.Second
+	pageSize     = 500
+	// maxReplay is the most events replayed for Last-Event-ID, clients missing more have to refetch
+	maxReplay = 1000
+	// bufferSize is how many events a subscriber may lag behind before it is dropped
+	bufferSize = 64
+)
+
+// ErrReplayTooLong is returned by Replay if the client missed more events than are replayed
+var ErrReplayTooLong = errors.New("too many events to replay")
+
+// Event is a user event as it was written to the outbox
+type Event struct {
+	ID     uuid.UUID
+	Type   string
+	UserID string
+	Data   json.RawMessage
+}
+
+// Broker reads new user events and sends them to the subscribers of this replica
+type Broker struct {
+	queries      *repository.Queries
+	replayWindow time.Duration
+
+	wake chan struct{}
+	done chan struct{}
+
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+	// horizon is the commit horizon of the last read, seen are the events read since with their transaction
+	horizon int64
+	seen    map[uuid.UUID]int64
+}
+
+// NewBroker creates a broker that replays events up to replayWindow old
+func NewBroker(queries *repository.Queries, replayWindow time.Duration) *Broker {
+	return &Broker{
+		queries:      queries,
+		replayWindow: replayWindow,
+		wake:         make(chan struct{}, 1),
+		done:         make(chan struct{}),
+		subscribers:  map[chan Event]struct{}{},
+		seen:         map[uuid.UUID]int64{},
+	}
+}
+
+// Subscribe wakes the broker on notifications. It must be called before the listener runs.
+func (b *Broker) Subscribe(listener *pgnotify.Listener) {
+	listener.Subscribe(Channel, func(string) { b.notify() })
+	listener.OnListen(b.notify)
+}
+
+func (b *Broker) notify() {
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Done is closed when the broker stopped, streams end then
+func (b *Broker) Done() <-chan struct{} {
+	return b.done
+}
+
+// Run reads new events when notified until ctx is done
+func (b *Broker) Run(ctx context.Context) {
+	defer close(b.done)
+
+	// Events written before the start are only replayed, not sent
+	if err := b.fetch(ctx, false); err != nil && ctx.Err() == nil {
+		slog.Error("Failed to read user events", "error", err)
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.wake:
+		case <-ticker.C:
+		}
+		if err := b.fetch(ctx, true); err != nil && ctx.Err() == nil {
+			slog.Error("Failed to read user events", "error", err)
+		}
+	}
+}
+
+// fetch reads the events of the transactions from the last horizon on and sends the ones that were not sent yet
+func (b *Broker) fetch(ctx context.Context, send bool) error {
+	// Taken before reading, transactions still running then are at or above it and read again next time
+	horizon, err := b.queries.GetCommitHorizon(ctx)
+	if err != nil {
+		return err
+	}
+	if !send {
+		// Events of transactions that ended before the start are only replayed
+		b.horizon = horizon
+	}
+
+	after := uuid.Nil
+	for {
+		dbEvents, err := b.queries.ListUserEventsSince(ctx, repository.ListUserEventsSinceParams{
+			Horizon:    b.horizon,
+			After:      after,
+			MaxResults: pageSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, dbEvent := range dbEvents {
+			if _, ok := b.seen[dbEvent.EventID]; ok {
+				continue
+			}
+			b.seen[dbEvent.EventID] = dbEvent.XactID
+			if send {
+				b.broadcast(toEvent(dbEvent))
+			}
+		}
+
+		if len(dbEvents) < pageSize {
+			break
+		}
+		after = dbEvents[len(dbEvents)-1].EventID
+	}
+
+	// Events below the new horizon are not read again
+	for id, xactID := range b.seen {
+		if xactID < horizon {
+			delete(b.seen, id)
+		}
+	}
+	b.horizon = horizon
+	return nil
+}
+
+// broadcast sends the event to every subscriber. Subscribers that are too slow are dropped,
+// they resume with Last-Event-ID.
+func (b *Broker) broadcast(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// Listen subscribes to new events. The channel is closed if the subscriber falls behind.
+// Call cancel when done.
+func (b *Broker) Listen() (<-chan Event, func()) {
+	events := make(chan Event, bufferSize)
+
+	b.mu.Lock()
+	b.subscribers[events] = struct{}{}
+	b.mu.Unlock()
+
+	return events, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[events]; ok {
+			delete(b.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+// Replay returns the events after the given one, at most replay window old. It returns ErrReplayTooLong
+// if there are more than maxReplay, the client has to refetch the users instead.
+func (b *Broker) Replay(ctx context.Context, after uuid.UUID) ([]Event, error) {
+	if floor := idAt(time.Now().Add(-b.replayWindow)); bytes.Compare(after[:], floor[:]) < 0 {
+		after = floor
+	}
+
+	dbEvents, err := b.queries.ListUserEventsAfter(ctx, repository.ListUserEventsAfterParams{
+		After:      after,
+		MaxResults: maxReplay + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(dbEvents) > maxReplay {
+		return nil, ErrReplayTooLong
+	}
+
+	events := make([]Event, 0, len(dbEvents))
+	for _, dbEvent := range dbEvents {
+		events = append(events, toEvent(dbEvent))
+	}
+	return events, nil
+}
+
+// idAt returns the smallest UUIDv7 of the given time
+func idAt(t time.Time) uuid.UUID {
+	var id uuid.UUID
+	var millis [8]byte
+	binary.BigEndian.PutUint64(millis[:], uint64(t.UnixMilli()))
+	copy(id[:6], millis[2:])
+	return id
+}
+
+func toEvent(dbEvent repository.OutboxEvent) Event {
+	return Event{
+		ID:     dbEvent.EventID,
+		Type:   dbEvent.EventType,
+		UserID: dbEvent.AggregateID,
+		Data:   dbEvent.Payload,
+	}
+}
@@ -0,0 +1,289 @@
+// Package webhooks delivers outbox events to the subscribed webhook endpoints.
+// Deliveries are signed with HMAC-SHA256, retried with exponential backoff and moved
+// to the dead letter state after the last attempt.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"com.tom-ludwig/go-server-template/internal/outbox"
+	"com.tom-ludwig/go-server-template/internal/repository"
+)
+
+// Signature headers, compatible with the Standard Webhooks specification
+const (
+	IDHeader        = "Webhook-Id"
+	TimestampHeader = "Webhook-Timestamp"
+	SignatureHeader = "Webhook-Signature"
+)
+
+// SecretPrefix precedes the base64 signing key of a secret, e.g. "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+const SecretPrefix = "whsec_"
+
+// Signing keys are 24 to 64 random bytes, see https://github.com/standard-webhooks/standard-webhooks/blob/main/spec/standard-webhooks.md
+const (
+	minSigningKeySize = 24
+	maxSigningKeySize = 64
+)
+
+const (
+	minRetryDelay = 10 * time.Second
+	maxRetryDelay = 6 * time.Hour
+)
+
+// Enqueuer is an outbox publisher that creates a delivery for every subscription of the event
+type Enqueuer struct {
+	queries *repository.Queries
+}
+
+func NewEnqueuer(queries *repository.Queries) *Enqueuer {
+	return &Enqueuer{
+		queries: queries,
+	}
+}
+
+func (e *Enqueuer) Publish(ctx context.Context, message outbox.Message) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = e.queries.EnqueueWebhookDeliveries(ctx, repository.EnqueueWebhookDeliveriesParams{
+		EventID:   message.ID,
+		EventType: message.Type,
+		Payload:   payload,
+	})
+	return err
+}
+
+// Dispatcher sends due deliveries. Each subscription has at most max_concurrency deliveries in flight
+// across all replicas, so a slow receiver only delays its own deliveries.
+type Dispatcher struct {
+	db          *pgxpool.Pool
+	queries     *repository.Queries
+	client      *http.Client
+	timeout     time.Duration
+	maxAttempts int32
+
+	wg sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher that gives up on a delivery after maxAttempts
+func NewDispatcher(db *pgxpool.Pool, queries *repository.Queries, timeout time.Duration, maxAttempts int32) *Dispatcher {
+	return &Dispatcher{
+		db:          db,
+		queries:     queries,
+		client:      &http.Client{Timeout: timeout},
+		timeout:     timeout,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Run sends due deliveries every interval until ctx is done, then waits for the deliveries in flight to finish
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	defer d.wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.dispatch(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("Failed to dispatch webhook deliveries", "error", err)
+			}
+		}
+	}
+}
+
+// dispatch claims as many due deliveries of every subscription as it has free slots and sends them
+func (d *Dispatcher) dispatch(ctx context.Context) error {
+	subscriptions, err := d.queries.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		deliveries, err := d.claim(ctx, subscription.SubscriptionID)
+		if err != nil {
+			return err
+		}
+
+		for _, delivery := range deliveries {
+			// Deliveries in flight finish on shutdown, the client's timeout bounds them
+			d.wg.Go(func() { d.deliver(context.WithoutCancel(ctx), subscription, delivery) })
+		}
+	}
+	return nil
+}
+
+// claim leases the due deliveries of a subscription that fit into its free slots. The subscription is locked
+// while the slots are counted, so replicas claiming at the same time cannot exceed max_concurrency together.
+func (d *Dispatcher) claim(ctx context.Context, subscriptionID uuid.UUID) ([]repository.WebhookDelivery, error) {
+	tx, err := d.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+	queries := repository.New(tx)
+
+	if err := queries.LockWebhookSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	// The lease outlives the request, an expired lease means the replica stopped
+	deliveries, err := queries.ClaimWebhookDeliveries(ctx, repository.ClaimWebhookDeliveriesParams{
+		LockedUntil:    pgtype.Timestamptz{Time: time.Now().Add(2 * d.timeout), Valid: true},
+		SubscriptionID: subscriptionID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, tx.Commit(ctx)
+}
+
+// deliver sends a delivery once and records the attempt
+func (d *Dispatcher) deliver(ctx context.Context, subscription repository.WebhookSubscription, delivery repository.WebhookDelivery) {
+	start := time.Now()
+	statusCode, sendErr := d.send(ctx, subscription, delivery)
+
+	attempt := repository.InsertWebhookDeliveryAttemptParams{
+		DeliveryID: delivery.DeliveryID,
+		DurationMs: int32(time.Since(start).Milliseconds()),
+	}
+	if statusCode != 0 {
+		attempt.StatusCode = pgtype.Int4{Int32: int32(statusCode), Valid: true}
+	}
+	if sendErr != nil {
+		attempt.Error = pgtype.Text{String: sendErr.Error(), Valid: true}
+	}
+	if err := d.queries.InsertWebhookDeliveryAttempt(ctx, attempt); err != nil {
+		slog.Error("Failed to record webhook delivery attempt", "delivery_id", delivery.DeliveryID, "error", err)
+	}
+
+	var err error
+	switch {
+	case sendErr == nil:
+		err = d.queries.CompleteWebhookDelivery(ctx, delivery.DeliveryID)
+	case delivery.Attempts >= d.maxAttempts:
+		slog.Warn("Webhook delivery failed permanently", "delivery_id", delivery.DeliveryID, "url", subscription.Url, "attempts", delivery.Attempts, "error", sendErr)
+		err = d.queries.FailWebhookDelivery(ctx, repository.FailWebhookDeliveryParams{
+			LastError:  attempt.Error,
+			DeliveryID: delivery.DeliveryID,
+		})
+	default:
+		err = d.queries.FailWebhookDelivery(ctx, repository.FailWebhookDeliveryParams{
+			NextAttemptAt: pgtype.Timestamptz{Time: time.Now().Add(retryDelay(delivery.Attempts)), Valid: true},
+			LastError:     attempt.Error,
+			DeliveryID:    delivery.DeliveryID,
+		})
+	}
+	if err != nil {
+		slog.Error("Failed to update webhook delivery", "delivery_id", delivery.DeliveryID, "error", err)
+	}
+}
+
+// send POSTs the signed payload and returns the response status, 0 if there was no response
+func (d *Dispatcher) send(ctx context.Context, subscription repository.WebhookSubscription, delivery repository.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	// The event ID stays the same for retries and redeliveries, receivers deduplicate by it
+	id := delivery.EventID.String()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IDHeader, id)
+	req.Header.Set(TimestampHeader, timestamp)
+	signature, err := Sign(subscription.Secret, id, timestamp, delivery.Payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sign delivery: %w", err)
+	}
+	req.Header.Set(SignatureHeader, signature)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature header value: v1, followed by the base64 HMAC-SHA256 of "id.timestamp.payload",
+// keyed with the base64-decoded part of the secret after "whsec_".
+// Receivers recompute it with the secret and reject old timestamps to prevent replays.
+func Sign(secret, id, timestamp string, payload []byte) (string, error) {
+	key, err := signingKey(secret)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id + "." + timestamp + "."))
+	mac.Write(payload)
+	return "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// ValidateSecret checks that a secret chosen by the subscriber is a Standard Webhooks secret
+func ValidateSecret(secret string) error {
+	_, err := signingKey(secret)
+	return err
+}
+
+// signingKey decodes the key of a "whsec_<base64>" secret
+func signingKey(secret string) ([]byte, error) {
+	encoded, ok := strings.CutPrefix(secret, SecretPrefix)
+	if !ok {
+		return nil, fmt.Errorf("secret must start with %s", SecretPrefix)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("secret must be %s followed by base64: %w", SecretPrefix, err)
+	}
+	if len(key) < minSigningKeySize || len(key) > maxSigningKeySize {
+		return nil, fmt.Errorf("secret key must be %d to %d bytes, got %d", minSigningKeySize, maxSigningKeySize, len(key))
+	}
+	return key, nil
+}
+
+// GenerateSecret returns a random signing secret
+func GenerateSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return SecretPrefix + base64.StdEncoding.EncodeToString(key), nil
+}
+
+// retryDelay doubles the delay with every failed attempt, up to maxRetryDelay
+func retryDelay(attempts int32) time.Duration {
+	delay := minRetryDelay
+	for range attempts - 1 {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
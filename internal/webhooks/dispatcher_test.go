@@ -0,0 +1,55 @@
+package webhooks
+
+import (
+	"strings"
+	"testing"
+)
+
+// The test vector of the Standard Webhooks specification and its reference libraries
+func TestSignStandardWebhooksVector(t *testing.T) {
+	signature, err := Sign(
+		"whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw",
+		"msg_p5jXN8AQM9LWM0D4loKWxJek",
+		"1614265330",
+		[]byte(`{"test": 2432232314}`),
+	)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if want := "v1,g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE="; signature != want {
+		t.Errorf("Sign() = %q, want %q", signature, want)
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+	if !strings.HasPrefix(secret, SecretPrefix) {
+		t.Errorf("GenerateSecret() = %q, want prefix %q", secret, SecretPrefix)
+	}
+	if err := ValidateSecret(secret); err != nil {
+		t.Errorf("ValidateSecret(GenerateSecret()) error = %v", err)
+	}
+}
+
+func TestValidateSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{"standard", "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw", false},
+		{"missing prefix", "MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw", true},
+		{"not base64", "whsec_not-base64!", true},
+		{"too short", "whsec_c2hvcnQ=", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSecret(tt.secret); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSecret(%q) error = %v, wantErr %v", tt.secret, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,70 +2,194 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 
+	"com.tom-ludwig/go-server-template/internal/api/apikeys"
+	"com.tom-ludwig/go-server-template/internal/api/audit"
 	"com.tom-ludwig/go-server-template/internal/api/health"
+	"com.tom-ludwig/go-server-template/internal/api/metrics"
+	"com.tom-ludwig/go-server-template/internal/api/revocations"
+	"com.tom-ludwig/go-server-template/internal/api/scheduler"
+	"com.tom-ludwig/go-server-template/internal/api/scim"
+	"com.tom-ludwig/go-server-template/internal/api/useradmin"
 	"com.tom-ludwig/go-server-template/internal/api/users"
+	"com.tom-ludwig/go-server-template/internal/api/webhooks"
+	"com.tom-ludwig/go-server-template/internal/certs"
 	"com.tom-ludwig/go-server-template/internal/config"
+	"com.tom-ludwig/go-server-template/internal/devissuer"
 	"com.tom-ludwig/go-server-template/internal/middleware"
+	"com.tom-ludwig/go-server-template/internal/outbox"
+	"com.tom-ludwig/go-server-template/internal/pgnotify"
 	"com.tom-ludwig/go-server-template/internal/repository"
+	"com.tom-ludwig/go-server-template/internal/retention"
+	"com.tom-ludwig/go-server-template/internal/revocation"
 	"com.tom-ludwig/go-server-template/internal/routes"
+	"com.tom-ludwig/go-server-template/internal/userevents"
+	webhookdispatch "com.tom-ludwig/go-server-template/internal/webhooks"
 )
 
 func main() {
-	err := godotenv.Load()
-	if err != nil {
-		// Use a temporary logger before config is loaded
-		logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-		slog.SetDefault(logger)
-		slog.Warn("Error loading .env file", "error", err)
+	command := "serve"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
 	}
 
-	// Load configuration
-	cfg := config.Load()
-
-	// Setup Logger with configured log level
-	opts := &slog.HandlerOptions{
-		Level: cfg.LogLevel,
+	switch command {
+	case "serve":
+		serve()
+	case "worker":
+		worker()
+	case "mint-token":
+		os.Exit(mintToken(os.Args[2:]))
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\nUsage: %s [serve|worker|mint-token]\n", command, os.Args[0])
+		os.Exit(2)
 	}
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, opts))
-	slog.SetDefault(logger)
+}
 
-	dbpool, err := connectToDatabase(cfg)
-	if err != nil {
-		slog.Error("Failed to connect to database", "error", err)
-		os.Exit(1)
-	}
+func serve() {
+	cfg, dbpool := setup()
 	defer dbpool.Close()
-	slog.Info("Successfully connected to database")
+
+	// Stop on SIGINT or SIGTERM, requests and jobs get ShutdownTimeout to finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	queries := repository.New(dbpool)
 
+	// Background tasks stop with ctx, the server waits for them before it exits
+	var background sync.WaitGroup
+
+	// Subscriptions to LISTEN/NOTIFY channels must be registered before the listener starts
+	listener := pgnotify.NewListener(dbpool)
+
+	// Authenticators are tried in order: explicit credentials first, then the TLS client certificate
+	var authenticators []middleware.Authenticator
+
+	// Start the local dev issuer before JWT auth discovers it
+	if cfg.DevIssuerEnabled {
+		issuer, err := devissuer.New(cfg.OIDCIssuer, cfg.OIDCAudience)
+		if err != nil {
+			slog.Error("Failed to create dev issuer", "error", err)
+			os.Exit(1)
+		}
+		if err := issuer.ListenAndServe(context.Background(), "127.0.0.1:"+cfg.DevIssuerPort); err != nil {
+			slog.Error("Failed to start dev issuer", "error", err)
+			os.Exit(1)
+		}
+		slog.Warn("Dev issuer enabled, anybody with local access can mint tokens. Never use this in production", "issuer", cfg.OIDCIssuer)
+	}
+
 	// Initialize JWT auth if OIDC is enabled
 	var jwtAuth *middleware.JWTAuth
 	if cfg.OIDCEnabled {
-		if cfg.OIDCIssuer == "" {
-			slog.Error("OIDC_ISSUER must be set when OIDC_ENABLED is true")
-			os.Exit(1)
-		}
 		var err error
-		jwtAuth, err = middleware.NewJWTAuth(context.Background(), cfg.OIDCIssuer, cfg.OIDCAudience)
+		jwtAuth, err = middleware.NewJWTAuth(context.Background(), cfg.OIDCIssuer, cfg.OIDCAudience, middleware.JWTAuthOptions{
+			JWKS:            cfg.OIDCJWKS,
+			JWKSFile:        cfg.OIDCJWKSFile,
+			CacheFile:       cfg.OIDCJWKSCacheFile,
+			AllowDegraded:   cfg.OIDCAllowDegraded,
+			RefreshInterval: cfg.OIDCJWKSRefreshInterval,
+		})
 		if err != nil {
 			slog.Error("Failed to initialize JWT auth", "error", err)
 			os.Exit(1)
 		}
-		slog.Info("JWT authentication enabled", "issuer", cfg.OIDCIssuer)
+
+		// Reject revoked tokens, the denylist is kept in sync across replicas
+		denylist := revocation.NewDenylist(queries)
+		if err := denylist.Load(context.Background()); err != nil {
+			slog.Error("Failed to load token denylist", "error", err)
+			os.Exit(1)
+		}
+		denylist.Subscribe(listener)
+		background.Go(func() { denylist.Run(ctx, cfg.RevocationReloadInterval) })
+		jwtAuth.UseRevocationChecker(denylist)
+
+		jwtAuth.UseDPoP(middleware.DPoPOptions{
+			Mode:            cfg.OIDCDPoP,
+			PublicURL:       cfg.OIDCDPoPPublicURL,
+			MaxAge:          cfg.OIDCDPoPMaxAge,
+			ReplayCacheSize: cfg.OIDCDPoPReplayCacheSize,
+		})
+
+		authenticators = append(authenticators, jwtAuth)
+		slog.Info("JWT authentication enabled", "issuer", cfg.OIDCIssuer, "key_source", jwtAuth.KeyStatus().Source, "dpop", cfg.OIDCDPoP)
 	}
 
-	router := routes.NewRouter(cfg, queries, jwtAuth)
+	// Initialize API key auth if enabled
+	if cfg.APIKeysEnabled {
+		authenticators = append(authenticators, middleware.NewAPIKeyAuth(queries))
+		slog.Info("API key authentication enabled")
+	}
+
+	// Initialize client certificate auth if client certificates are verified
+	if cfg.ClientCertsEnabled() {
+		authenticators = append(authenticators, middleware.NewClientCertAuth(cfg.MTLSPrincipalRoles, cfg.MTLSRolesClaim))
+		slog.Info("Client certificate authentication enabled", "client_auth", cfg.TLSClientAuth)
+	}
+
+	// Stream user events to the clients of GET /users/events
+	userEvents := userevents.NewBroker(queries, cfg.SSEReplayWindow)
+	userEvents.Subscribe(listener)
+	// Streams end when the broker stops, so the server's shutdown does not wait for them
+	background.Go(func() { userEvents.Run(ctx) })
+
+	background.Go(func() { listener.Run(ctx) })
+
+	// Replay responses for retried requests with an Idempotency-Key
+	idempotency := middleware.NewIdempotency(queries, cfg.IdempotencyKeyTTL)
+
+	// Publish the domain events of committed mutations
+	if publisher := outboxPublisher(cfg, queries); publisher != nil {
+		relay := outbox.NewRelay(dbpool, publisher, int32(cfg.OutboxBatchSize), cfg.OutboxRetention)
+		background.Go(func() { relay.Run(ctx, cfg.OutboxPollInterval) })
+		slog.Info("Outbox relay enabled", "publisher", cfg.OutboxPublisher, "webhooks", cfg.WebhooksEnabled)
+	}
+
+	// Send webhook deliveries to the subscribed endpoints
+	if cfg.WebhooksEnabled {
+		dispatcher := webhookdispatch.NewDispatcher(dbpool, queries, cfg.WebhookTimeout, int32(cfg.WebhookMaxAttempts))
+		// Deliveries in flight are finished on shutdown
+		background.Go(func() { dispatcher.Run(ctx, cfg.WebhookPollInterval) })
+	}
+
+	// Hard-delete users whose retention window has passed
+	purger := retention.NewPurger(queries, cfg.UserRetention)
+
+	// Periodic tasks run on the elected leader only
+	tasks := newScheduler(cfg, dbpool, queries, purger)
+	if cfg.SchedulerEnabled {
+		background.Go(func() { tasks.Run(ctx) })
+	}
+
+	// Run background jobs in this process unless dedicated workers run them
+	if cfg.JobWorkerEmbedded {
+		background.Go(func() { newJobWorker(cfg, queries, purger).Run(ctx, cfg.JobPollInterval) })
+	}
+
+	router := routes.NewRouter(cfg, routes.Dependencies{
+		DB:             dbpool,
+		Queries:        queries,
+		Authenticators: authenticators,
+		JWTAuth:        jwtAuth,
+		Idempotency:    idempotency,
+		RateLimiter:    rateLimiter(cfg, dbpool, queries),
+		Scheduler:      tasks,
+		UserEvents:     userEvents,
+	})
 
 	// Print registered routes in debug mode
 	if cfg.LogLevel == slog.LevelDebug {
@@ -77,6 +201,30 @@ func main() {
 		if s, err := users.GetSwagger(); err == nil {
 			swaggers = append(swaggers, s)
 		}
+		if s, err := useradmin.GetSwagger(); err == nil {
+			swaggers = append(swaggers, s)
+		}
+		if s, err := apikeys.GetSwagger(); err == nil {
+			swaggers = append(swaggers, s)
+		}
+		if s, err := revocations.GetSwagger(); err == nil {
+			swaggers = append(swaggers, s)
+		}
+		if s, err := audit.GetSwagger(); err == nil {
+			swaggers = append(swaggers, s)
+		}
+		if s, err := scheduler.GetSwagger(); err == nil {
+			swaggers = append(swaggers, s)
+		}
+		if s, err := metrics.GetSwagger(); err == nil {
+			swaggers = append(swaggers, s)
+		}
+		if s, err := webhooks.GetSwagger(); err == nil {
+			swaggers = append(swaggers, s)
+		}
+		if s, err := scim.GetSwagger(); err == nil {
+			swaggers = append(swaggers, s)
+		}
 		routes.PrintRoutes(router, swaggers)
 	}
 
@@ -89,12 +237,137 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	slog.Info("Server starting", "port", cfg.Port, "log_level", cfg.LogLevel.String())
-	err = server.ListenAndServe()
+	if cfg.TLSEnabled() {
+		reloader, err := certs.NewReloader(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile, tlsClientAuth(cfg.TLSClientAuth))
+		if err != nil {
+			slog.Error("Failed to load TLS certificate", "error", err)
+			os.Exit(1)
+		}
+		go reloader.Watch(context.Background(), cfg.TLSReloadInterval)
+		server.TLSConfig = reloader.TLSConfig()
+	}
+
+	// Stop accepting connections on shutdown and wait for the requests in flight
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		slog.Info("Shutting down", "timeout", cfg.ShutdownTimeout)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("Failed to shut down server gracefully", "error", err)
+		}
+	}()
+
+	slog.Info("Server starting", "port", cfg.Port, "tls", cfg.TLSEnabled(), "log_level", cfg.LogLevel.String())
+	var err error
+	if cfg.TLSEnabled() {
+		// Certificates are provided by the TLS config
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
 	if err != nil && err != http.ErrServerClosed {
 		slog.Error("Server failed to start", "error", err)
 		os.Exit(1)
 	}
+
+	// ListenAndServe returns as soon as the shutdown starts
+	<-shutdownDone
+	background.Wait()
+	slog.Info("Server stopped")
+}
+
+// setup loads the configuration, configures the logger and connects to the database
+func setup() (*config.Config, *pgxpool.Pool) {
+	err := godotenv.Load()
+	if err != nil {
+		// Use a temporary logger before config is loaded
+		logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+		slog.SetDefault(logger)
+		slog.Warn("Error loading .env file", "error", err)
+	}
+
+	// Load configuration
+	cfg := config.Load()
+
+	// Setup Logger with configured log level
+	opts := &slog.HandlerOptions{
+		Level: cfg.LogLevel,
+	}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, opts))
+	slog.SetDefault(logger)
+
+	dbpool, err := connectToDatabase(cfg)
+	if err != nil {
+		slog.Error("Failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Successfully connected to database")
+
+	return cfg, dbpool
+}
+
+// rateLimiter returns the configured rate limiter, nil if rate limiting is disabled
+func rateLimiter(cfg *config.Config, dbpool *pgxpool.Pool, queries *repository.Queries) *middleware.RateLimiter {
+	if !cfg.RateLimitEnabled {
+		return nil
+	}
+
+	var store middleware.RateLimitStore = middleware.NewMemoryRateLimitStore()
+	if cfg.RateLimitStore == "postgres" {
+		store = middleware.NewPostgresRateLimitStore(dbpool, queries)
+	}
+	slog.Info("Rate limiting enabled", "store", cfg.RateLimitStore, "algorithm", cfg.RateLimitAlgorithm,
+		"requests", cfg.RateLimitRequests, "period", cfg.RateLimitPeriod)
+	return middleware.NewRateLimiter(store, middleware.RateLimit{
+		Algorithm: middleware.RateLimitAlgorithm(cfg.RateLimitAlgorithm),
+		Requests:  cfg.RateLimitRequests,
+		Period:    cfg.RateLimitPeriod,
+	}, middleware.RateLimit{
+		Algorithm: middleware.RateLimitAlgorithm(cfg.RateLimitAlgorithm),
+		Requests:  cfg.AuthFailureLimitRequests,
+		Period:    cfg.AuthFailureLimitPeriod,
+	})
+}
+
+// outboxPublisher returns the configured publishers, nil if events are not published.
+// Webhook deliveries are enqueued first, enqueueing an event again creates no duplicates.
+func outboxPublisher(cfg *config.Config, queries *repository.Queries) outbox.Publisher {
+	var publishers outbox.MultiPublisher
+	if cfg.WebhooksEnabled {
+		publishers = append(publishers, webhookdispatch.NewEnqueuer(queries))
+	}
+
+	switch cfg.OutboxPublisher {
+	case "log":
+		publishers = append(publishers, outbox.LogPublisher{})
+	case "webhook":
+		publishers = append(publishers, outbox.NewWebhookPublisher(cfg.OutboxWebhookURL, cfg.OutboxWebhookTimeout))
+	case "file":
+		publishers = append(publishers, outbox.NewFilePublisher(cfg.OutboxFile))
+	}
+
+	switch len(publishers) {
+	case 0:
+		return nil
+	case 1:
+		return publishers[0]
+	default:
+		return publishers
+	}
+}
+
+func tlsClientAuth(mode string) tls.ClientAuthType {
+	switch mode {
+	case "optional":
+		return tls.VerifyClientCertIfGiven
+	case "require":
+		return tls.RequireAndVerifyClientCert
+	default:
+		return tls.NoClientCert
+	}
 }
 
 func connectToDatabase(cfg *config.Config) (*pgxpool.Pool, error) {
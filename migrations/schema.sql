@@ -3,5 +3,178 @@ CREATE TABLE users (
     email        TEXT,
     first_name   TEXT,
     last_name    TEXT, 
+    issuer           TEXT, -- iss and sub of the token the user was provisioned from
+    external_subject TEXT,
+    status       TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended', 'deactivated')),
+    deleted_at   TIMESTAMPTZ, -- soft deleted, purged after the retention window
+    version      BIGINT NOT NULL DEFAULT 1, -- incremented on every update, used as ETag
     created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
 );
+
+CREATE UNIQUE INDEX users_issuer_external_subject_idx ON users (issuer, external_subject);
+-- The email is the SCIM userName, unique case-insensitively among users that are not deleted
+CREATE UNIQUE INDEX users_email_idx ON users (lower(email)) WHERE deleted_at IS NULL;
+CREATE INDEX users_deleted_at_idx ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE TABLE api_keys (
+    key_id       UUID PRIMARY KEY DEFAULT uuidv7(),
+    name         TEXT NOT NULL,
+    prefix       TEXT NOT NULL UNIQUE,
+    key_hash     TEXT NOT NULL,
+    owner        TEXT NOT NULL,
+    scopes       TEXT[] NOT NULL DEFAULT '{}',
+    expires_at   TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE revoked_tokens (
+    jti          TEXT PRIMARY KEY,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    reason       TEXT,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE revoked_subjects (
+    issuer         TEXT NOT NULL, -- a sub is only unique per issuer
+    subject        TEXT NOT NULL,
+    revoked_before TIMESTAMPTZ NOT NULL,
+    reason         TEXT,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (issuer, subject)
+);
+
+CREATE TABLE idempotency_keys (
+    owner            TEXT NOT NULL, -- key:<api key id> or sub:<issuer>|<subject> of the caller, keys are scoped per caller
+    key              TEXT NOT NULL,
+    fingerprint      TEXT NOT NULL, -- hash of method, path and body
+    status_code      INT, -- NULL while the first request is in progress
+    response_headers JSONB,
+    response_body    BYTEA,
+    expires_at       TIMESTAMPTZ NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (owner, key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+
+-- Append-only, the application never updates or deletes events
+CREATE TABLE audit_events (
+    event_id      UUID PRIMARY KEY DEFAULT uuidv7(), -- time ordered, used as the pagination cursor
+    actor         TEXT NOT NULL, -- subject of the caller, the key ID for API keys, empty if anonymous
+    actor_type    TEXT NOT NULL, -- subject, api_key or anonymous
+    action        TEXT NOT NULL, -- e.g. user.create
+    resource_type TEXT NOT NULL,
+    resource_id   TEXT NOT NULL,
+    before        JSONB, -- changed fields before the mutation, NULL for creations
+    after         JSONB, -- changed fields after the mutation, NULL for deletions
+    request_id    TEXT NOT NULL,
+    ip            TEXT NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_events_actor_idx ON audit_events (actor, event_id);
+CREATE INDEX audit_events_resource_idx ON audit_events (resource_type, resource_id, event_id);
+CREATE INDEX audit_events_created_at_idx ON audit_events (created_at);
+
+-- Transactional outbox, events are written with the mutation and published by the relay
+CREATE TABLE outbox_events (
+    event_id        UUID PRIMARY KEY DEFAULT uuidv7(), -- time ordered, events of an aggregate are published in this order
+    aggregate_type  TEXT NOT NULL, -- e.g. user
+    aggregate_id    TEXT NOT NULL,
+    event_type      TEXT NOT NULL, -- e.g. user.created
+    payload         JSONB NOT NULL,
+    attempts        INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_error      TEXT,
+    published_at    TIMESTAMPTZ, -- NULL until published, deleted after OUTBOX_RETENTION
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    xact_id         BIGINT NOT NULL DEFAULT pg_current_xact_id()::text::bigint -- writing transaction, events become visible in commit order, not ID order
+);
+
+CREATE INDEX outbox_events_pending_idx ON outbox_events (aggregate_type, aggregate_id, event_id) WHERE published_at IS NULL;
+CREATE INDEX outbox_events_published_at_idx ON outbox_events (published_at) WHERE published_at IS NOT NULL;
+CREATE INDEX outbox_events_user_xact_idx ON outbox_events (xact_id) WHERE aggregate_type = 'user';
+
+CREATE TABLE webhook_subscriptions (
+    subscription_id UUID PRIMARY KEY DEFAULT uuidv7(),
+    url             TEXT NOT NULL,
+    event_types     TEXT[] NOT NULL DEFAULT '{}', -- empty receives every event
+    secret          TEXT NOT NULL, -- HMAC key, needed in plaintext to sign deliveries
+    max_concurrency INT NOT NULL DEFAULT 4, -- deliveries in flight per replica
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE webhook_deliveries (
+    delivery_id     UUID PRIMARY KEY DEFAULT uuidv7(),
+    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions (subscription_id) ON DELETE CASCADE,
+    event_id        UUID NOT NULL, -- outbox event, the relay can publish an event more than once
+    event_type      TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'in_flight', 'succeeded', 'dead')),
+    attempts        INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    locked_until    TIMESTAMPTZ, -- lease of the replica sending it, expired leases are retried
+    last_error      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (subscription_id, next_attempt_at) WHERE status IN ('pending', 'in_flight');
+
+CREATE TABLE webhook_delivery_attempts (
+    attempt_id   UUID PRIMARY KEY DEFAULT uuidv7(),
+    delivery_id  UUID NOT NULL REFERENCES webhook_deliveries (delivery_id) ON DELETE CASCADE,
+    status_code  INT, -- NULL if no response was received
+    error        TEXT,
+    duration_ms  INT NOT NULL,
+    attempted_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX webhook_delivery_attempts_delivery_id_idx ON webhook_delivery_attempts (delivery_id, attempt_id);
+
+CREATE TABLE jobs (
+    job_id       UUID PRIMARY KEY DEFAULT uuidv7(),
+    kind         TEXT NOT NULL, -- e.g. users.purge, selects the registered handler
+    args         JSONB NOT NULL,
+    status       TEXT NOT NULL DEFAULT 'available' CHECK (status IN ('available', 'running', 'completed', 'discarded')),
+    unique_key   TEXT, -- at most one available or running job per kind and key
+    attempts     INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL,
+    run_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    locked_until TIMESTAMPTZ, -- lease of the running worker, expired leases are retried
+    last_error   TEXT,
+    finished_at  TIMESTAMPTZ, -- completed or discarded, deleted after JOB_RETENTION
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX jobs_available_idx ON jobs (run_at) WHERE status = 'available';
+CREATE INDEX jobs_running_idx ON jobs (locked_until) WHERE status = 'running';
+CREATE INDEX jobs_finished_at_idx ON jobs (finished_at) WHERE finished_at IS NOT NULL;
+CREATE UNIQUE INDEX jobs_unique_key_idx ON jobs (kind, unique_key) WHERE unique_key IS NOT NULL AND status IN ('available', 'running');
+
+CREATE TABLE scheduled_task_runs (
+    run_id      UUID PRIMARY KEY DEFAULT uuidv7(),
+    task        TEXT NOT NULL, -- name the task was registered with, e.g. idempotency.cleanup
+    replica     TEXT NOT NULL, -- host name of the leader that ran it
+    started_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at TIMESTAMPTZ, -- NULL while running, or if the leader stopped during the run
+    error       TEXT
+);
+
+CREATE INDEX scheduled_task_runs_task_idx ON scheduled_task_runs (task, run_id);
+CREATE INDEX scheduled_task_runs_started_at_idx ON scheduled_task_runs (started_at);
+
+-- State of the rate limits shared by the replicas. Unlogged, a crash only resets the limits.
+CREATE UNLOGGED TABLE rate_limits (
+    key          TEXT PRIMARY KEY, -- route or default, and the client, e.g. default|sub:<subject>
+    tokens       DOUBLE PRECISION NOT NULL DEFAULT 0, -- token bucket
+    updated_at   TIMESTAMPTZ, -- token bucket, NULL for a new key whose bucket is full
+    window_start TIMESTAMPTZ, -- sliding window
+    count        INT NOT NULL DEFAULT 0, -- sliding window, requests in the current window
+    prev_count   INT NOT NULL DEFAULT 0, -- sliding window, requests in the previous window
+    expires_at   TIMESTAMPTZ NOT NULL -- the state is deleted once it equals a new key's
+);
+
+CREATE INDEX rate_limits_expires_at_idx ON rate_limits (expires_at);
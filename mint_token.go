@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// mintToken requests a signed token from the dev issuer of a running server and prints it.
+// Usage: server mint-token -sub alice -scope "read:users admin" -roles admin -exp 1h
+func mintToken(args []string) int {
+	_ = godotenv.Load()
+
+	port := os.Getenv("DEV_ISSUER_PORT")
+	if port == "" {
+		port = "8081"
+	}
+	defaultIssuer := fmt.Sprintf("http://localhost:%s", port)
+
+	flags := flag.NewFlagSet("mint-token", flag.ContinueOnError)
+	issuer := flags.String("issuer", defaultIssuer, "URL of the dev issuer")
+	subject := flags.String("sub", "dev-user", "subject of the token")
+	scope := flags.String("scope", "", "space separated scopes")
+	roles := flags.String("roles", "", "comma separated roles")
+	rolesClaim := flags.String("roles-claim", "roles", "claim the roles are stored in")
+	audience := flags.String("aud", os.Getenv("OIDC_AUDIENCE"), "audience of the token")
+	expiresIn := flags.Duration("exp", time.Hour, "lifetime of the token")
+	jkt := flags.String("jkt", "", "JWK thumbprint of the client's DPoP key, binds the token to it")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	request := map[string]any{
+		"sub":         *subject,
+		"scope":       *scope,
+		"roles_claim": *rolesClaim,
+		"aud":         *audience,
+		"exp_in":      expiresIn.String(),
+	}
+	if *roles != "" {
+		request["roles"] = strings.Split(*roles, ",")
+	}
+	if *jkt != "" {
+		request["claims"] = map[string]any{"cnf": map[string]any{"jkt": *jkt}}
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to encode request:", err)
+		return 1
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(strings.TrimSuffix(*issuer, "/")+"/token", "application/json", bytes.NewReader(body))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to reach dev issuer, is the server running with DEV_ISSUER_ENABLED=true?", err)
+		return 1
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to decode response:", err)
+		return 1
+	}
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "dev issuer returned status %d: %s\n", resp.StatusCode, response.Error)
+		return 1
+	}
+
+	fmt.Println(response.AccessToken)
+	return 0
+}
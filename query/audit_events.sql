@@ -0,0 +1,15 @@
+-- name: InsertAuditEvent :exec
+INSERT INTO audit_events (actor, actor_type, action, resource_type, resource_id, before, after, request_id, ip)
+VALUES (@actor, @actor_type, @action, @resource_type, @resource_id, @before, @after, @request_id, @ip);
+
+-- name: ListAuditEvents :many
+-- Newest first. The cursor is the event_id of the last event of the previous page.
+SELECT * FROM audit_events
+WHERE (sqlc.narg('actor')::text IS NULL OR actor = sqlc.narg('actor'))
+  AND (sqlc.narg('resource_type')::text IS NULL OR resource_type = sqlc.narg('resource_type'))
+  AND (sqlc.narg('resource_id')::text IS NULL OR resource_id = sqlc.narg('resource_id'))
+  AND (sqlc.narg('since')::timestamptz IS NULL OR created_at >= sqlc.narg('since'))
+  AND (sqlc.narg('until')::timestamptz IS NULL OR created_at < sqlc.narg('until'))
+  AND (sqlc.narg('cursor')::uuid IS NULL OR event_id < sqlc.narg('cursor'))
+ORDER BY event_id DESC
+LIMIT @max_results;
@@ -0,0 +1,32 @@
+-- name: ClaimIdempotencyKey :one
+-- Inserts the key for a new request, or takes over an expired one. Returns no row if the key is in use.
+INSERT INTO idempotency_keys (owner, key, fingerprint, expires_at)
+VALUES (@owner, @key, @fingerprint, @expires_at)
+ON CONFLICT (owner, key) DO UPDATE
+SET fingerprint      = EXCLUDED.fingerprint,
+    status_code      = NULL,
+    response_headers = NULL,
+    response_body    = NULL,
+    expires_at       = EXCLUDED.expires_at,
+    created_at       = now()
+WHERE idempotency_keys.expires_at < now()
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys WHERE owner = $1 AND key = $2;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code      = @status_code,
+    response_headers = @response_headers,
+    response_body    = @response_body,
+    expires_at       = @expires_at
+WHERE owner = @owner AND key = @key AND fingerprint = @fingerprint;
+
+-- name: ReleaseIdempotencyKey :exec
+-- Lets the client retry a request that failed, only while it is still in progress.
+DELETE FROM idempotency_keys
+WHERE owner = @owner AND key = @key AND fingerprint = @fingerprint AND status_code IS NULL;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys WHERE expires_at < now();
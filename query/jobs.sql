@@ -0,0 +1,44 @@
+-- name: InsertJob :execrows
+-- Returns 0 if an available or running job with the same kind and unique key exists.
+INSERT INTO jobs (kind, args, unique_key, max_attempts, run_at)
+VALUES (@kind, @args, sqlc.narg('unique_key'), @max_attempts, @run_at)
+ON CONFLICT (kind, unique_key) WHERE unique_key IS NOT NULL AND status IN ('available', 'running') DO NOTHING;
+
+-- name: ClaimJobs :many
+-- Leases due jobs of the given kinds, including running jobs whose lease expired because their worker stopped.
+UPDATE jobs
+SET status = 'running', attempts = attempts + 1, locked_until = @locked_until
+WHERE job_id IN (
+    SELECT job_id FROM jobs j
+    WHERE j.kind = ANY(@kinds::text[])
+      AND ((j.status = 'available' AND j.run_at <= now())
+        OR (j.status = 'running' AND j.locked_until < now()))
+    ORDER BY j.run_at, j.job_id
+    LIMIT @max_results
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteJob :exec
+UPDATE jobs
+SET status = 'completed', locked_until = NULL, last_error = NULL, finished_at = now()
+WHERE job_id = @job_id;
+
+-- name: RetryJob :exec
+UPDATE jobs
+SET status = 'available', locked_until = NULL, last_error = @last_error, run_at = @run_at
+WHERE job_id = @job_id;
+
+-- name: DiscardJob :exec
+UPDATE jobs
+SET status = 'discarded', locked_until = NULL, last_error = @last_error, finished_at = now()
+WHERE job_id = @job_id;
+
+-- name: ReleaseJob :exec
+-- Returns a job interrupted by a shutdown without counting the attempt.
+UPDATE jobs
+SET status = 'available', locked_until = NULL, attempts = attempts - 1
+WHERE job_id = @job_id;
+
+-- name: DeleteFinishedJobs :execrows
+DELETE FROM jobs WHERE finished_at < @finished_before;
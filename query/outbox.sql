@@ -0,0 +1,61 @@
+-- name: EnqueueUserEvents :exec
+-- Adds an event with the current state of each user. Must run in the transaction of the mutation.
+INSERT INTO outbox_events (aggregate_type, aggregate_id, event_type, payload)
+SELECT 'user', user_id::text, @event_type, jsonb_build_object(
+    'user_id', user_id,
+    'email', email,
+    'first_name', first_name,
+    'last_name', last_name,
+    'status', status,
+    'deleted_at', deleted_at,
+    'version', version
+)
+FROM users
+WHERE user_id = ANY(@user_ids::uuid[])
+ORDER BY user_id;
+
+-- name: ClaimOutboxEvents :many
+-- Locks the oldest pending event of each aggregate until the end of the transaction. Later events
+-- of an aggregate wait until the earlier ones are published, so every aggregate is published in order.
+SELECT * FROM outbox_events o
+WHERE o.published_at IS NULL
+  AND o.next_attempt_at <= now()
+  AND NOT EXISTS (
+    SELECT 1 FROM outbox_events earlier
+    WHERE earlier.aggregate_type = o.aggregate_type
+      AND earlier.aggregate_id = o.aggregate_id
+      AND earlier.published_at IS NULL
+      AND earlier.event_id < o.event_id
+  )
+ORDER BY o.event_id
+LIMIT @batch_size
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxEventPublished :exec
+UPDATE outbox_events SET published_at = now(), attempts = attempts + 1, last_error = NULL
+WHERE event_id = @event_id;
+
+-- name: FailOutboxEvent :exec
+UPDATE outbox_events SET attempts = attempts + 1, last_error = @last_error, next_attempt_at = @next_attempt_at
+WHERE event_id = @event_id;
+
+-- name: DeletePublishedOutboxEvents :execrows
+DELETE FROM outbox_events WHERE published_at < @published_before;
+
+-- name: GetCommitHorizon :one
+-- Transactions with an ID below the horizon have ended, their events are all visible.
+SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint AS horizon;
+
+-- name: ListUserEventsSince :many
+-- Lists the visible events of the transactions from the horizon on, a page at a time ordered by event ID.
+SELECT * FROM outbox_events
+WHERE aggregate_type = 'user' AND xact_id >= @horizon AND event_id > @after
+ORDER BY event_id
+LIMIT @max_results;
+
+-- name: ListUserEventsAfter :many
+-- Event IDs are UUIDv7, so the events after an ID are the ones created after it.
+SELECT * FROM outbox_events
+WHERE aggregate_type = 'user' AND event_id > @after
+ORDER BY event_id
+LIMIT @max_results;
@@ -0,0 +1,28 @@
+-- name: LockRateLimit :one
+-- Returns the state of the key, a new one if it has none or it expired, locked until the end of the transaction.
+INSERT INTO rate_limits (key, expires_at)
+VALUES (@key, @expires_at)
+ON CONFLICT (key) DO UPDATE
+SET tokens       = CASE WHEN rate_limits.expires_at < now() THEN 0 ELSE rate_limits.tokens END,
+    updated_at   = CASE WHEN rate_limits.expires_at < now() THEN NULL ELSE rate_limits.updated_at END,
+    window_start = CASE WHEN rate_limits.expires_at < now() THEN NULL ELSE rate_limits.window_start END,
+    count        = CASE WHEN rate_limits.expires_at < now() THEN 0 ELSE rate_limits.count END,
+    prev_count   = CASE WHEN rate_limits.expires_at < now() THEN 0 ELSE rate_limits.prev_count END
+RETURNING *;
+
+-- name: GetRateLimit :one
+-- Returns the state of the key without locking it, no row if it has none or it expired.
+SELECT * FROM rate_limits WHERE key = @key AND expires_at >= now();
+
+-- name: UpdateRateLimit :exec
+UPDATE rate_limits
+SET tokens       = @tokens,
+    updated_at   = @updated_at,
+    window_start = @window_start,
+    count        = @count,
+    prev_count   = @prev_count,
+    expires_at   = @expires_at
+WHERE key = @key;
+
+-- name: DeleteExpiredRateLimits :execrows
+DELETE FROM rate_limits WHERE expires_at < now();
@@ -0,0 +1,37 @@
+-- name: RevokeToken :one
+INSERT INTO revoked_tokens (jti, expires_at, reason)
+VALUES ($1, $2, $3)
+ON CONFLICT (jti) DO UPDATE
+SET expires_at = GREATEST(revoked_tokens.expires_at, EXCLUDED.expires_at),
+    reason = EXCLUDED.reason
+RETURNING *;
+
+-- name: GetRevokedToken :one
+SELECT * FROM revoked_tokens WHERE jti = $1 AND expires_at > now();
+
+-- name: ListRevokedTokens :many
+SELECT * FROM revoked_tokens
+WHERE expires_at > now()
+ORDER BY created_at DESC;
+
+-- name: DeleteExpiredRevokedTokens :exec
+DELETE FROM revoked_tokens WHERE expires_at <= now();
+
+-- name: RevokeSubject :one
+INSERT INTO revoked_subjects (issuer, subject, revoked_before, reason)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (issuer, subject) DO UPDATE
+SET revoked_before = GREATEST(revoked_subjects.revoked_before, EXCLUDED.revoked_before),
+    reason = EXCLUDED.reason
+RETURNING *;
+
+-- name: GetRevokedSubject :one
+SELECT * FROM revoked_subjects WHERE issuer = $1 AND subject = $2;
+
+-- name: ListRevokedSubjects :many
+SELECT * FROM revoked_subjects
+ORDER BY created_at DESC;
+
+-- name: DeleteRevokedSubject :one
+DELETE FROM revoked_subjects WHERE issuer = $1 AND subject = $2
+RETURNING *;
@@ -0,0 +1,30 @@
+-- name: TryAdvisoryLock :one
+-- Session level lock, held until it is unlocked or the connection closes.
+SELECT pg_try_advisory_lock(@lock_key::bigint)::boolean AS locked;
+
+-- name: AdvisoryUnlock :exec
+SELECT pg_advisory_unlock(@lock_key::bigint);
+
+-- name: StartScheduledTaskRun :one
+INSERT INTO scheduled_task_runs (task, replica)
+VALUES (@task, @replica)
+RETURNING *;
+
+-- name: FinishScheduledTaskRun :exec
+UPDATE scheduled_task_runs SET finished_at = now(), error = @error
+WHERE run_id = @run_id;
+
+-- name: ListScheduledTaskRuns :many
+-- Newest first. The cursor is the run_id of the last run of the previous page.
+SELECT * FROM scheduled_task_runs
+WHERE (sqlc.narg('task')::text IS NULL OR task = sqlc.narg('task'))
+  AND (sqlc.narg('cursor')::uuid IS NULL OR run_id < sqlc.narg('cursor'))
+ORDER BY run_id DESC
+LIMIT @max_results;
+
+-- name: GetLatestScheduledTaskRuns :many
+SELECT DISTINCT ON (task) * FROM scheduled_task_runs
+ORDER BY task, run_id DESC;
+
+-- name: DeleteScheduledTaskRuns :execrows
+DELETE FROM scheduled_task_runs WHERE started_at < @started_before;
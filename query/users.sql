@@ -4,7 +4,9 @@ VALUES ($1, $2, $3)
 RETURNING *;
 
 -- name: GetUser :one
-SELECT * FROM users WHERE user_id = $1;
+SELECT * FROM users
+WHERE user_id = @user_id
+  AND (deleted_at IS NULL OR @include_deleted::boolean);
 
 -- name: GetUsers :many
 SELECT 
@@ -12,11 +14,127 @@ SELECT
     email, 
     first_name, 
     last_name, 
+    status,
+    deleted_at,
     created_at 
 FROM users 
+WHERE deleted_at IS NULL OR @include_deleted::boolean
 ORDER BY created_at DESC 
-LIMIT $1 
-OFFSET $2;
+LIMIT @max_results 
+OFFSET @skip;
 
 -- name: CountUsers :one
-SELECT COUNT(*) FROM users;
+SELECT COUNT(*) FROM users
+WHERE deleted_at IS NULL OR @include_deleted::boolean;
+
+-- name: GetUserBySubject :one
+SELECT * FROM users WHERE issuer = $1 AND external_subject = $2;
+
+-- name: UpsertUserBySubject :one
+-- Creates the user of a token subject or syncs the email from the token. Names are only filled in if
+-- they are missing, so they can be changed with UpdateUserProfile. Returns no row if nothing changed
+-- or the user was deleted.
+INSERT INTO users (issuer, external_subject, email, first_name, last_name)
+VALUES (@issuer, @external_subject, @email, @first_name, @last_name)
+ON CONFLICT (issuer, external_subject) DO UPDATE
+SET email      = COALESCE(EXCLUDED.email, users.email),
+    first_name = COALESCE(users.first_name, EXCLUDED.first_name),
+    last_name  = COALESCE(users.last_name, EXCLUDED.last_name),
+    version    = users.version + 1
+WHERE users.deleted_at IS NULL
+  AND (users.email IS DISTINCT FROM COALESCE(EXCLUDED.email, users.email)
+    OR (users.first_name IS NULL AND EXCLUDED.first_name IS NOT NULL)
+    OR (users.last_name IS NULL AND EXCLUDED.last_name IS NOT NULL))
+RETURNING *;
+
+-- name: UpdateUserProfile :one
+-- Returns no row if the user was deleted or is no longer active.
+UPDATE users
+SET first_name = COALESCE(sqlc.narg('first_name'), first_name),
+    last_name  = COALESCE(sqlc.narg('last_name'), last_name),
+    version    = version + 1
+WHERE user_id = @user_id
+  AND deleted_at IS NULL
+  AND status = 'active'
+RETURNING *;
+
+-- name: CreateExternalUser :one
+INSERT INTO users (email, first_name, last_name, issuer, external_subject)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ReplaceUser :one
+UPDATE users
+SET email            = @email,
+    first_name       = @first_name,
+    last_name        = @last_name,
+    issuer           = @issuer,
+    external_subject = @external_subject,
+    status           = COALESCE(sqlc.narg('status'), status),
+    version          = version + 1
+WHERE user_id = @user_id AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SoftDeleteUser :one
+-- A NULL version deletes any version of the user.
+UPDATE users SET deleted_at = now(), version = version + 1
+WHERE user_id = @user_id
+  AND deleted_at IS NULL
+  AND (sqlc.narg('version')::bigint IS NULL OR version = sqlc.narg('version'))
+RETURNING *;
+
+-- name: RestoreUser :one
+-- Only users deleted after deleted_after can be restored, older ones are about to be purged.
+UPDATE users SET deleted_at = NULL, version = version + 1
+WHERE user_id = @user_id AND deleted_at > @deleted_after AND version = @version
+RETURNING *;
+
+-- name: PurgeDeletedUsers :execrows
+DELETE FROM users WHERE deleted_at < @deleted_before;
+
+-- name: UpdateUserStatus :one
+-- Transitions are only applied from one of the allowed statuses, so concurrent transitions cannot skip the state machine.
+UPDATE users SET status = @status, version = version + 1
+WHERE user_id = @user_id
+  AND deleted_at IS NULL
+  AND status = ANY(@from_statuses::text[])
+  AND version = @version
+RETURNING *;
+
+-- name: ListUsersByFilter :many
+-- Patterns are LIKE patterns, NULL skips the condition. email and names are matched case-insensitively.
+SELECT * FROM users
+WHERE deleted_at IS NULL
+  AND (sqlc.narg('user_id')::uuid IS NULL OR user_id = sqlc.narg('user_id'))
+  AND (sqlc.narg('email')::text IS NULL OR email ILIKE sqlc.narg('email'))
+  AND (sqlc.narg('external_subject')::text IS NULL OR external_subject LIKE sqlc.narg('external_subject'))
+  AND (sqlc.narg('first_name')::text IS NULL OR first_name ILIKE sqlc.narg('first_name'))
+  AND (sqlc.narg('last_name')::text IS NULL OR last_name ILIKE sqlc.narg('last_name'))
+ORDER BY created_at, user_id
+LIMIT @max_results
+OFFSET @skip;
+
+-- name: CountUsersByFilter :one
+SELECT COUNT(*) FROM users
+WHERE deleted_at IS NULL
+  AND (sqlc.narg('user_id')::uuid IS NULL OR user_id = sqlc.narg('user_id'))
+  AND (sqlc.narg('email')::text IS NULL OR email ILIKE sqlc.narg('email'))
+  AND (sqlc.narg('external_subject')::text IS NULL OR external_subject LIKE sqlc.narg('external_subject'))
+  AND (sqlc.narg('first_name')::text IS NULL OR first_name ILIKE sqlc.narg('first_name'))
+  AND (sqlc.narg('last_name')::text IS NULL OR last_name ILIKE sqlc.narg('last_name'));
+
+-- name: CopyUsers :copyfrom
+INSERT INTO users (user_id, email, first_name, last_name) VALUES ($1, $2, $3, $4);
+
+-- name: DeclareUserExportCursor :exec
+-- Must run in a transaction, rows are read with FETCH FORWARD n FROM user_export.
+DECLARE user_export NO SCROLL CURSOR FOR
+SELECT * FROM users
+WHERE deleted_at IS NULL OR @include_deleted::boolean
+ORDER BY created_at, user_id;
+
+-- name: LockUser :one
+-- Locks the user until the end of the transaction, e.g. to record its state before an update.
+SELECT * FROM users
+WHERE user_id = @user_id AND deleted_at IS NULL
+FOR UPDATE;
@@ -0,0 +1,92 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (url, event_types, secret, max_concurrency)
+VALUES (@url, @event_types, @secret, @max_concurrency)
+RETURNING *;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM webhook_subscriptions ORDER BY created_at;
+
+-- name: GetWebhookSubscription :one
+SELECT * FROM webhook_subscriptions WHERE subscription_id = @subscription_id;
+
+-- name: DeleteWebhookSubscription :one
+DELETE FROM webhook_subscriptions WHERE subscription_id = @subscription_id
+RETURNING *;
+
+-- name: EnqueueWebhookDeliveries :execrows
+-- Creates a delivery of the event for every subscription of its type. Publishing an event again creates no duplicates.
+INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+SELECT subscription_id, @event_id, @event_type, @payload
+FROM webhook_subscriptions
+WHERE cardinality(event_types) = 0 OR @event_type = ANY(event_types)
+ON CONFLICT (subscription_id, event_id) DO NOTHING;
+
+-- name: LockWebhookSubscription :exec
+-- Serializes the claims of a subscription across replicas until the transaction ends.
+SELECT 1 FROM webhook_subscriptions WHERE subscription_id = @subscription_id FOR NO KEY UPDATE;
+
+-- name: ClaimWebhookDeliveries :many
+-- Leases due deliveries of a subscription, including deliveries whose lease expired because their replica stopped.
+-- Only as many as max_concurrency minus the unexpired leases of all replicas are leased, run it after
+-- LockWebhookSubscription in the same transaction so concurrent claims see each other's leases.
+UPDATE webhook_deliveries
+SET status = 'in_flight', attempts = attempts + 1, locked_until = @locked_until
+WHERE delivery_id IN (
+    SELECT delivery_id FROM webhook_deliveries d
+    WHERE d.subscription_id = @subscription_id
+      AND ((d.status = 'pending' AND d.next_attempt_at <= now())
+        OR (d.status = 'in_flight' AND d.locked_until < now()))
+    ORDER BY d.next_attempt_at, d.delivery_id
+    LIMIT COALESCE((
+        SELECT GREATEST(s.max_concurrency - count(l.delivery_id), 0)
+        FROM webhook_subscriptions s
+        LEFT JOIN webhook_deliveries l ON l.subscription_id = s.subscription_id
+            AND l.status = 'in_flight' AND l.locked_until >= now()
+        WHERE s.subscription_id = @subscription_id
+        GROUP BY s.max_concurrency
+    ), 0)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status = 'succeeded', locked_until = NULL, last_error = NULL
+WHERE delivery_id = @delivery_id;
+
+-- name: FailWebhookDelivery :exec
+-- A NULL next_attempt_at moves the delivery to the dead letter state.
+UPDATE webhook_deliveries
+SET status          = CASE WHEN sqlc.narg('next_attempt_at')::timestamptz IS NULL THEN 'dead' ELSE 'pending' END,
+    next_attempt_at = COALESCE(sqlc.narg('next_attempt_at'), next_attempt_at),
+    locked_until    = NULL,
+    last_error      = @last_error
+WHERE delivery_id = @delivery_id;
+
+-- name: RedeliverWebhookDelivery :one
+-- Schedules a delivery again with a fresh retry budget. Deliveries in flight cannot be redelivered.
+UPDATE webhook_deliveries
+SET status = 'pending', attempts = 0, next_attempt_at = now(), last_error = NULL
+WHERE delivery_id = @delivery_id AND status <> 'in_flight'
+RETURNING *;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries WHERE delivery_id = @delivery_id;
+
+-- name: ListWebhookDeliveries :many
+-- Newest first. The cursor is the delivery_id of the last delivery of the previous page.
+SELECT * FROM webhook_deliveries
+WHERE subscription_id = @subscription_id
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('cursor')::uuid IS NULL OR delivery_id < sqlc.narg('cursor'))
+ORDER BY delivery_id DESC
+LIMIT @max_results;
+
+-- name: InsertWebhookDeliveryAttempt :exec
+INSERT INTO webhook_delivery_attempts (delivery_id, status_code, error, duration_ms)
+VALUES (@delivery_id, @status_code, @error, @duration_ms);
+
+-- name: ListWebhookDeliveryAttempts :many
+SELECT * FROM webhook_delivery_attempts
+WHERE delivery_id = @delivery_id
+ORDER BY attempt_id;
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"com.tom-ludwig/go-server-template/internal/config"
+	"com.tom-ludwig/go-server-template/internal/jobs"
+	"com.tom-ludwig/go-server-template/internal/middleware"
+	"com.tom-ludwig/go-server-template/internal/repository"
+	"com.tom-ludwig/go-server-template/internal/retention"
+	"com.tom-ludwig/go-server-template/internal/scheduler"
+)
+
+// worker runs background jobs without serving HTTP, e.g. to scale workers separately from the API.
+// Set JOB_WORKER_EMBEDDED=false on the API replicas to run jobs only in worker processes.
+func worker() {
+	cfg, dbpool := setup()
+	defer dbpool.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	queries := repository.New(dbpool)
+	purger := retention.NewPurger(queries, cfg.UserRetention)
+
+	// Workers take part in the leader election too, so periodic tasks run even if no API replica does
+	var background sync.WaitGroup
+	if cfg.SchedulerEnabled {
+		background.Go(func() { newScheduler(cfg, dbpool, queries, purger).Run(ctx) })
+	}
+
+	slog.Info("Worker starting", "concurrency", cfg.JobConcurrency, "log_level", cfg.LogLevel.String())
+	newJobWorker(cfg, queries, purger).Run(ctx, cfg.JobPollInterval)
+	background.Wait()
+	slog.Info("Worker stopped")
+}
+
+// newJobWorker creates a worker with all job kinds registered. Register new kinds here.
+func newJobWorker(cfg *config.Config, queries *repository.Queries, purger *retention.Purger) *jobs.Worker {
+	worker := jobs.NewWorker(queries, cfg.JobConcurrency, cfg.ShutdownTimeout, cfg.JobRetention)
+	jobs.Register(worker, retention.PurgeJob, purger.Work, jobs.Options{Timeout: 10 * time.Minute})
+	return worker
+}
+
+// newScheduler creates the scheduler with all periodic tasks registered. Register new tasks here,
+// SCHEDULER_SCHEDULES overrides their schedules.
+func newScheduler(cfg *config.Config, dbpool *pgxpool.Pool, queries *repository.Queries, purger *retention.Purger) *scheduler.Scheduler {
+	tasks := scheduler.New(dbpool, queries, cfg.SchedulerElectionInterval, cfg.SchedulerRunRetention)
+	idempotency := middleware.NewIdempotency(queries, cfg.IdempotencyKeyTTL)
+
+	defaults := []struct {
+		name     string
+		schedule string
+		task     scheduler.Task
+	}{
+		{"idempotency.cleanup", "@every " + cfg.IdempotencyCleanupInterval.String(), idempotency.DeleteExpired},
+		{"users.purge", "@every " + cfg.UserPurgeInterval.String(), purger.EnqueuePurge},
+		{"scheduler.cleanup", "@daily", tasks.DeleteOldRuns},
+		{"ratelimits.cleanup", "@hourly", middleware.NewPostgresRateLimitStore(dbpool, queries).DeleteExpired},
+	}
+	known := map[string]bool{}
+	for _, task := range defaults {
+		known[task.name] = true
+		schedule := task.schedule
+		if override, ok := cfg.SchedulerSchedules[task.name]; ok {
+			schedule = override
+		}
+		if err := tasks.Register(task.name, schedule, task.task); err != nil {
+			slog.Error("Failed to register scheduled task", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	for name := range cfg.SchedulerSchedules {
+		if !known[name] {
+			slog.Error("SCHEDULER_SCHEDULES contains an unknown task", "task", name)
+			os.Exit(1)
+		}
+	}
+	return tasks
+}